@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jackuait/wisp-deck/internal/usage"
+)
+
+var budgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Show this month's spend against the budget",
+	Long:  "Prints this month's API-rate spend against each cap of the budget set in the wisp-deck settings file (budget=200,project:app=50,account:work=100), most-spent first.",
+	Args:  cobra.NoArgs,
+	RunE:  runBudget,
+}
+
+var budgetCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Record the budget alert and print it when a threshold is newly crossed",
+	Long:  "Evaluates the budget, writes the current alert to the budget-alert state file the status line reads, and prints the alert only when spend has crossed 50%, 80% or 100% of a cap since the last check this month. The tab-title watcher runs it to sound the crossing once.",
+	Args:  cobra.NoArgs,
+	RunE:  runBudgetCheck,
+}
+
+func init() {
+	budgetCmd.AddCommand(budgetCheckCmd)
+	rootCmd.AddCommand(budgetCmd)
+}
+
+// evaluateBudget loads the budget and this month's usage the same way the
+// Stats tab does. A budget with no caps yields no statuses and no scan.
+func evaluateBudget(home string) ([]usage.BudgetStatus, string, error) {
+	month := time.Now().UTC().Format("2006-01")
+	b, err := usage.LoadBudget(usage.SettingsPath(home))
+	if err != nil {
+		return nil, month, fmt.Errorf("budget setting: %w", err)
+	}
+	if b.IsZero() {
+		return nil, month, nil
+	}
+	if err := usage.LoadPricing(usage.PricingPath(home)); err != nil {
+		return nil, month, fmt.Errorf("pricing overrides: %w", err)
+	}
+	_, _, cachePath := usage.DefaultPaths(home)
+	records, err := usage.Collect(usage.DefaultSources(home), cachePath)
+	if err != nil {
+		return nil, month, fmt.Errorf("aggregate usage: %w", err)
+	}
+	return usage.EvaluateBudget(records, b, month), month, nil
+}
+
+func runBudget(cmd *cobra.Command, args []string) error {
+	home, _ := os.UserHomeDir()
+	statuses, month, err := evaluateBudget(home)
+	if err != nil {
+		return err
+	}
+	if len(statuses) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No budget set. Add budget=<USD per month> to "+usage.SettingsPath(home)+" or set it in the Settings tab.")
+		return nil
+	}
+	renderBudget(cmd.OutOrStdout(), month, statuses)
+	return nil
+}
+
+// renderBudget prints one aligned row per cap.
+func renderBudget(w io.Writer, month string, statuses []usage.BudgetStatus) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\tSPENT\tLIMIT\tUSED\n", month)
+	for _, s := range statuses {
+		approx := ""
+		if !s.Priced {
+			approx = "~"
+		}
+		fmt.Fprintf(tw, "%s\t%s$%.2f\t$%.2f\t%.0f%%\n", s.Label(), approx, s.SpentUSD, s.LimitUSD, s.Percent())
+	}
+	tw.Flush()
+}
+
+func runBudgetCheck(cmd *cobra.Command, args []string) error {
+	home, _ := os.UserHomeDir()
+	statuses, month, err := evaluateBudget(home)
+	if err != nil {
+		return err
+	}
+	alert, raised, err := usage.RecordBudgetAlert(usage.BudgetStatePath(home), month, statuses)
+	if err != nil {
+		return fmt.Errorf("budget state: %w", err)
+	}
+	if raised {
+		fmt.Fprintln(cmd.OutOrStdout(), alert)
+	}
+	return nil
+}
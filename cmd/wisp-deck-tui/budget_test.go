@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// budgetHome lays out a home dir with $9 of Sonnet input this month and the
+// given budget spec in the settings file, and returns the config dir.
+func budgetHome(t *testing.T, spec string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("OPENCODE_DATA_DIR", filepath.Join(home, "opencode"))
+	t.Setenv("CODEX_HOME", filepath.Join(home, "codex"))
+	t.Setenv("AIDER_ANALYTICS_LOG", "")
+	proj := filepath.Join(home, ".claude", "projects", "-src-app")
+	cfg := filepath.Join(home, ".config", "wisp-deck")
+	for _, d := range []string{proj, cfg} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	os.WriteFile(filepath.Join(proj, "a.jsonl"), []byte(
+		`{"type":"assistant","timestamp":"`+now+`","cwd":"/src/app","message":{"id":"1","model":"claude-sonnet-4-5","usage":{"input_tokens":3000000}}}`+"\n"), 0o644)
+	os.WriteFile(filepath.Join(cfg, "settings"), []byte("theme=auto\nbudget="+spec+"\n"), 0o644)
+	return cfg
+}
+
+func TestBudgetCmd_listsEachCap(t *testing.T) {
+	budgetHome(t, "100,project:app=10")
+	out := execRoot(t, "budget")
+	if !strings.Contains(out, "project app") || !strings.Contains(out, "90%") || !strings.Contains(out, "9%") {
+		t.Errorf("budget output = %q, want the project cap at 90%% and the overall at 9%%", out)
+	}
+}
+
+func TestBudgetCheck_printsANewCrossingOnce(t *testing.T) {
+	cfg := budgetHome(t, "10")
+	if out := strings.TrimSpace(execRoot(t, "budget", "check")); out != "90% of budget ($9/$10)" {
+		t.Errorf("first check = %q, want the 80%% crossing", out)
+	}
+	if out := strings.TrimSpace(execRoot(t, "budget", "check")); out != "" {
+		t.Errorf("second check = %q, want silence for an announced threshold", out)
+	}
+	state, err := os.ReadFile(filepath.Join(cfg, "budget-alert"))
+	if err != nil || !strings.Contains(string(state), " 80 90% of budget") {
+		t.Errorf("state = %q, %v, want the 80%% alert recorded", state, err)
+	}
+}
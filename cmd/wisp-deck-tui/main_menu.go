@@ -41,6 +41,7 @@ var (
 	mainMenuClaudeAccountsDir      string
 	mainMenuClaudeDefaultLabelFile string
 	mainMenuAutoSwitchFile         string
+	mainMenuRotationPolicyFile     string
 )
 
 func init() {
@@ -65,6 +66,7 @@ func init() {
 	mainMenuCmd.Flags().StringVar(&mainMenuClaudeAccountsDir, "claude-accounts-dir", "", "Path to Claude accounts directory (per-account config dirs)")
 	mainMenuCmd.Flags().StringVar(&mainMenuClaudeDefaultLabelFile, "claude-default-label-file", "", "Path to the Default login's custom label file")
 	mainMenuCmd.Flags().StringVar(&mainMenuAutoSwitchFile, "auto-switch-file", "", "Path to the automatic account-switching on/off flag file")
+	mainMenuCmd.Flags().StringVar(&mainMenuRotationPolicyFile, "rotation-policy-file", "", "Path to the account-rotation policy file")
 	rootCmd.AddCommand(mainMenuCmd)
 }
 
@@ -123,6 +125,9 @@ func runMainMenu(cmd *cobra.Command, args []string) error {
 	if mainMenuAutoSwitchFile != "" {
 		model.SetAutoSwitchFile(mainMenuAutoSwitchFile)
 	}
+	if mainMenuRotationPolicyFile != "" {
+		model.SetRotationPolicyFile(mainMenuRotationPolicyFile)
+	}
 	if mainMenuClaudeDefaultLabelFile != "" {
 		model.SetClaudeDefaultLabelFile(mainMenuClaudeDefaultLabelFile)
 		model.SetClaudeDefaultLabel(tui.ReadDefaultAccountLabel(mainMenuClaudeDefaultLabelFile))
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/jackuait/wisp-deck/internal/proxy"
+)
+
+var (
+	proxyRecordDir string
+	proxyReplayDir string
+)
+
+func init() {
+	proxyCmd.Flags().StringVar(&proxyRecordDir, "record", "", "save every upstream exchange (credentials redacted) as a cassette in this directory")
+	proxyCmd.Flags().StringVar(&proxyReplayDir, "replay", "", "serve upstream traffic from the cassettes in this directory instead of the network; quota state and the usage ledger are left untouched")
+}
+
+// proxyCassetteOptions wires --record / --replay into the server. It returns
+// the upstream base URL to use — for a replay, a local stand-in upstream
+// serving the cassettes with their recorded timing — and the extra options.
+func proxyCassetteOptions() (string, []proxy.Option, error) {
+	switch {
+	case proxyRecordDir != "" && proxyReplayDir != "":
+		return "", nil, errors.New("--record and --replay cannot be combined")
+	case proxyRecordDir != "":
+		rec, err := proxy.NewRecorder(proxyRecordDir, http.DefaultTransport)
+		if err != nil {
+			return "", nil, fmt.Errorf("record: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "[wisp-deck-proxy] recording upstream exchanges to %s\n", proxyRecordDir)
+		return proxyUpstream, []proxy.Option{proxy.WithTransport(rec)}, nil
+	case proxyReplayDir != "":
+		rep, err := proxy.NewReplayer(proxyReplayDir, time.Sleep)
+		if err != nil {
+			return "", nil, fmt.Errorf("replay: %w", err)
+		}
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return "", nil, fmt.Errorf("replay: %w", err)
+		}
+		go http.Serve(ln, rep)
+		base := "http://" + ln.Addr().String()
+		opts := []proxy.Option{proxy.WithTokenEndpoint(base + "/v1/oauth/token")}
+		// Clients still dial the real upstream host through the MITM tunnel.
+		if u, err := url.Parse(proxyUpstream); err == nil {
+			opts = append(opts, proxy.WithMITMHost(u.Hostname()))
+		}
+		fmt.Fprintf(os.Stderr, "[wisp-deck-proxy] replaying cassettes from %s\n", proxyReplayDir)
+		return base, opts, nil
+	}
+	return proxyUpstream, nil, nil
+}
@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -24,6 +30,8 @@ var (
 	proxyUpstream    string
 	proxyMITM        bool
 	proxyCertDir     string
+	proxyPolicy      string
+	proxyMaxAttempts int
 )
 
 var proxyCmd = &cobra.Command{
@@ -41,10 +49,20 @@ func init() {
 	proxyCmd.Flags().IntVar(&proxyPort, "port", 0, "listen port (0 picks a free port)")
 	proxyCmd.Flags().StringVar(&proxyUpstream, "upstream", "https://api.anthropic.com", "upstream Anthropic base URL")
 	proxyCmd.Flags().BoolVar(&proxyMITM, "mitm", true, "enable the CONNECT/MITM forward proxy (like teamclaude's default); --mitm=false uses base-URL mode only")
+	proxyCmd.Flags().StringVar(&proxyPolicy, "policy", proxy.PolicyDrain, "rotation policy: "+strings.Join(proxy.PolicyNames, ", ")+" (priority reads each login's priority=N,weight=N from the rotation sidecar; lower priorities run first, weights split a tier)")
+	proxyCmd.Flags().IntVar(&proxyMaxAttempts, "max-attempts", proxy.DefaultRetryPolicy().MaxAttempts, "attempts per request on one account while upstream is overloaded (529), first included; failover is bounded by the pool size")
 	proxyCmd.Flags().StringVar(&proxyCertDir, "cert-dir", "", "directory for the MITM CA/leaf certs (defaults to the accounts dir's parent)")
 	rootCmd.AddCommand(proxyCmd)
 }
 
+// proxyReloadInterval is how often the proxy re-checks the accounts list and
+// credentials for logins added, removed or refreshed by another process.
+const proxyReloadInterval = 2 * time.Second
+
+// proxyShutdownGrace bounds how long a stopping proxy waits for in-flight
+// requests (long streams included) before cutting them off.
+const proxyShutdownGrace = 10 * time.Second
+
 // proxyStartupJSON renders the startup line bash reads to learn the port, key,
 // and (in MITM mode) the CA cert path clients trust via NODE_EXTRA_CA_CERTS.
 func proxyStartupJSON(port int, key, ca string) string {
@@ -64,18 +82,71 @@ func generateProxyKey() string {
 }
 
 func runProxy(cmd *cobra.Command, args []string) error {
+	policy, err := proxy.ParsePolicy(proxyPolicy)
+	if err != nil {
+		return err
+	}
+	if proxyMaxAttempts < 1 {
+		return fmt.Errorf("--max-attempts must be at least 1, got %d", proxyMaxAttempts)
+	}
+	// SIGINT/SIGTERM stop the proxy gracefully so its pending state is saved.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// As the shared daemon, hold the per-user lock for the whole lifetime so a
+	// second daemon started by a racing launcher exits instead of competing.
+	var sessions *proxy.Sessions
+	if proxyDaemon {
+		lock, err := proxy.LockDaemon(filepath.Join(proxyDaemonStateDir(), proxy.DaemonLockFile))
+		if err != nil {
+			return err
+		}
+		defer lock.Close()
+		sessions = proxy.NewSessions(time.Now())
+	}
 	accounts, err := proxy.LoadAccounts(proxyAccountsDir, proxyListFile)
 	if err != nil {
 		return fmt.Errorf("load accounts: %w", err)
 	}
+	// A small (even empty) pool is fine: the watcher below adds logins as they
+	// are created, so the proxy need not be restarted to grow.
 	if len(accounts) < 2 {
-		return fmt.Errorf("account rotation needs at least 2 accounts with credentials, found %d", len(accounts))
+		fmt.Fprintf(os.Stderr, "[wisp-deck-proxy] starting with %d account(s) with credentials; more are picked up as they are added\n", len(accounts))
 	}
 
 	mgr := proxy.NewManager(accounts, proxyThreshold)
+	mgr.SetPolicy(policy)
+	upstream, cassetteOpts, err := proxyCassetteOptions()
+	if err != nil {
+		return err
+	}
+	// A replay must not leak recorded quota, usage or (redacted) refreshed
+	// tokens into the live files.
+	persist := proxyAccountsDir != "" && proxyReplayDir == ""
+	// Resume the quota/throttle picture the previous proxy learned, so the first
+	// request already avoids accounts upstream is still rejecting.
+	if persist {
+		if err := mgr.LoadState(filepath.Join(proxyAccountsDir, proxy.StateFile), time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "[wisp-deck-proxy] ignoring unreadable quota state: %v\n", err)
+		}
+	}
 	mgr.SelectBest(time.Now())
+	if proxyListFile != "" {
+		go proxy.NewPoolWatcher(mgr, proxyAccountsDir, proxyListFile).Run(ctx, proxyReloadInterval)
+	}
 	key := generateProxyKey()
-	srv := proxy.NewServer(mgr, key, proxyUpstream, proxy.WithAccountsDir(proxyAccountsDir))
+	retry := proxy.DefaultRetryPolicy()
+	retry.MaxAttempts = proxyMaxAttempts
+	opts := append(slices.Clip(cassetteOpts), proxy.WithRetryPolicy(retry))
+	if persist {
+		// Refreshed tokens are written back, and usage goes to the per-account
+		// token ledger.
+		opts = append(opts, proxy.WithAccountsDir(proxyAccountsDir))
+		opts = append(opts, proxy.WithLedger(proxy.NewLedger(filepath.Join(proxyAccountsDir, proxy.LedgerFile))))
+	}
+	if sessions != nil {
+		opts = append(opts, proxy.WithSessions(sessions))
+	}
+	srv := proxy.NewServer(mgr, key, upstream, opts...)
 
 	// Enable the CONNECT/MITM forward proxy by default (teamclaude's default
 	// mode), so even hardcoded api.anthropic.com endpoints get the injected
@@ -104,5 +175,36 @@ func runProxy(cmd *cobra.Command, args []string) error {
 	os.Stdout.Sync()
 
 	httpSrv := &http.Server{Handler: srv}
-	return httpSrv.Serve(ln)
+	if sessions != nil {
+		info := proxy.DaemonInfo{PID: os.Getpid(), Port: port, Key: key, CA: caPath}
+		err = serveDaemon(ctx, httpSrv, ln, sessions, info)
+	} else {
+		err = serveProxy(ctx, httpSrv, ln)
+	}
+	// Saves are debounced; write whatever is still pending now that no
+	// request can change it.
+	if persist {
+		mgr.FlushState()
+	}
+	return err
+}
+
+// serveProxy serves ln until ctx is done, then shuts down gracefully, giving
+// in-flight requests up to proxyShutdownGrace to finish. It returns once the
+// server has fully stopped.
+func serveProxy(ctx context.Context, httpSrv *http.Server, ln net.Listener) error {
+	errc := make(chan error, 1)
+	go func() { errc <- httpSrv.Serve(ln) }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), proxyShutdownGrace)
+	defer cancel()
+	if err := httpSrv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("[wisp-deck-proxy] requests still running after %s — closing them", proxyShutdownGrace)
+		httpSrv.Close()
+	}
+	return nil
 }
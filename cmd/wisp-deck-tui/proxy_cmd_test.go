@@ -1,9 +1,21 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/jackuait/wisp-deck/internal/proxy"
 )
 
 func TestProxyStartupJSON_reportsPortKeyAndCA(t *testing.T) {
@@ -44,3 +56,192 @@ func TestGenerateProxyKey_isNonEmptyAndPrefixed(t *testing.T) {
 		t.Errorf("key should be prefixed wd-, got %q", k1)
 	}
 }
+
+func TestProxyStatusCmd_registeredUnderProxy(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"proxy", "status"})
+	if err != nil || cmd.Name() != "status" {
+		t.Fatalf("proxy status not registered: %v", err)
+	}
+	for _, name := range []string{"port", "key", "json"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("missing --%s flag", name)
+		}
+	}
+}
+
+func TestRenderProxyStatus_tableShowsActiveAndState(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	st := proxy.PoolStatus{
+		Now:       now,
+		Threshold: 0.98,
+		Policy:    proxy.PolicyDrain,
+		Active:    0,
+		Accounts: []proxy.AccountStatus{
+			{Label: "work", Active: true, Available: true, Util5h: 0.42, Util7d: -1, Reset5h: now.Add(90 * time.Minute)},
+			{Label: "personal", Util5h: -1, Util7d: 0.5, ThrottledUntil: now.Add(5 * time.Minute)},
+			{Label: "spare", Util5h: -1, Util7d: -1, Errored: true},
+		},
+	}
+	var buf bytes.Buffer
+	renderProxyStatus(&buf, st)
+	out := buf.String()
+	for _, want := range []string{"*  work", "42%", "1h30m", "throttled", "5m", "errored", "1 of 3 accounts available", "policy drain", "98%"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestProxyStatus_fetchesFromRunningServer(t *testing.T) {
+	mgr := proxy.NewManager([]proxy.Account{{Label: "A", AccessToken: "a"}, {Label: "B", AccessToken: "b"}}, 0.98)
+	ts := httptest.NewServer(proxy.NewServer(mgr, "k", "http://127.0.0.1:1"))
+	defer ts.Close()
+
+	st, err := proxy.FetchStatus(ts.URL, "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st.Accounts) != 2 || !st.Accounts[0].Active {
+		t.Errorf("got %+v", st)
+	}
+	if _, err := proxy.FetchStatus(ts.URL, "wrong"); err == nil {
+		t.Error("a bad key should fail")
+	}
+}
+
+// startDaemonServer runs a daemon-mode proxy server and publishes its record in
+// stateDir, as `proxy --daemon` would.
+func startDaemonServer(t *testing.T, stateDir string) (*proxy.Sessions, proxy.DaemonInfo) {
+	t.Helper()
+	ss := proxy.NewSessions(time.Now())
+	mgr := proxy.NewManager([]proxy.Account{{AccessToken: "tok-A"}}, 0.98)
+	ts := httptest.NewServer(proxy.NewServer(mgr, "daemon-key", "http://upstream.invalid", proxy.WithSessions(ss)))
+	t.Cleanup(ts.Close)
+	port, _ := strconv.Atoi(ts.URL[strings.LastIndex(ts.URL, ":")+1:])
+	info := proxy.DaemonInfo{PID: os.Getpid(), Port: port, Key: "daemon-key"}
+	if err := proxy.WriteDaemonInfo(filepath.Join(stateDir, proxy.DaemonFile), info); err != nil {
+		t.Fatal(err)
+	}
+	return ss, info
+}
+
+// A stop (SIGINT/SIGTERM cancel the context) lets an in-flight request finish
+// before serveProxy returns, so the caller's state flush sees its effects.
+func TestServeProxy_drainsInFlightRequestOnStop(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	httpSrv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		io.WriteString(w, "done")
+	})}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- serveProxy(ctx, httpSrv, ln) }()
+
+	body := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		body <- string(b)
+	}()
+	<-started
+	cancel()
+	select {
+	case err := <-served:
+		t.Fatalf("serveProxy returned %v with a request still running", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(release)
+	if got := <-body; got != "done" {
+		t.Errorf("in-flight response = %q, want it to finish", got)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("serveProxy = %v, want nil after a graceful stop", err)
+	}
+}
+
+func TestAttachDaemon_reusesRunningDaemon(t *testing.T) {
+	dir := t.TempDir()
+	ss, want := startDaemonServer(t, dir)
+	spawned := false
+	got, err := attachDaemon(dir, proxy.AttachRequest{PID: os.Getpid()}, func() error { spawned = true; return nil }, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spawned {
+		t.Error("a live daemon must be reused, not respawned")
+	}
+	if got != want {
+		t.Errorf("info = %+v, want %+v", got, want)
+	}
+	if d := ss.IdleFor(time.Now()); d != 0 {
+		t.Errorf("the caller should be attached (idle = %s)", d)
+	}
+}
+
+func TestAttachDaemon_spawnsWhenRecordIsStale(t *testing.T) {
+	dir := t.TempDir()
+	// A record left behind by a daemon that is gone.
+	proxy.WriteDaemonInfo(filepath.Join(dir, proxy.DaemonFile), proxy.DaemonInfo{PID: 1, Port: 1, Key: "old"})
+	spawned := 0
+	got, err := attachDaemon(dir, proxy.AttachRequest{PID: os.Getpid()}, func() error {
+		spawned++
+		startDaemonServer(t, dir)
+		return nil
+	}, 2*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spawned != 1 || got.Key != "daemon-key" {
+		t.Errorf("spawned = %d info = %+v, want one spawn and the new daemon's record", spawned, got)
+	}
+}
+
+func TestAttachDaemon_timesOutWhenDaemonNeverAppears(t *testing.T) {
+	_, err := attachDaemon(t.TempDir(), proxy.AttachRequest{PID: os.Getpid()}, func() error { return nil }, 200*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), proxy.DaemonLogFile) {
+		t.Errorf("err = %v, want a timeout pointing at the daemon log", err)
+	}
+}
+
+func TestProxyCassetteOptions_recordAndReplayAreExclusive(t *testing.T) {
+	proxyRecordDir, proxyReplayDir = t.TempDir(), t.TempDir()
+	defer func() { proxyRecordDir, proxyReplayDir = "", "" }()
+	if _, _, err := proxyCassetteOptions(); err == nil {
+		t.Error("--record with --replay should be rejected")
+	}
+}
+
+func TestProxyCassetteOptions_replayServesFromLocalUpstream(t *testing.T) {
+	dir := t.TempDir()
+	data, _ := json.Marshal(proxy.Cassette{Seq: 1, Request: proxy.CassetteRequest{Method: "GET", Path: "/v1/models"},
+		Response: proxy.CassetteResponse{Status: 200, Chunks: []proxy.CassetteChunk{{Data: "ok"}}}})
+	os.WriteFile(filepath.Join(dir, "0001.json"), data, 0o600)
+	proxyReplayDir = dir
+	defer func() { proxyReplayDir = "" }()
+
+	upstream, _, err := proxyCassetteOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(upstream, "http://127.0.0.1:") {
+		t.Fatalf("upstream = %q, want the local stand-in", upstream)
+	}
+	resp, err := http.Get(upstream + "/v1/models")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if body, _ := io.ReadAll(resp.Body); resp.StatusCode != 200 || string(body) != "ok" {
+		t.Errorf("replayed %d %q, want the cassette's 200 ok", resp.StatusCode, body)
+	}
+}
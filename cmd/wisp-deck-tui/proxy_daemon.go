@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jackuait/wisp-deck/internal/proxy"
+)
+
+var (
+	proxyDaemon      bool
+	proxyStateDir    string
+	proxyIdleTimeout time.Duration
+	proxyAttachPID   int
+)
+
+// daemonIdleCheck is how often the daemon looks for exited sessions.
+const daemonIdleCheck = 10 * time.Second
+
+// daemonStartTimeout bounds how long attach waits for a freshly spawned daemon
+// to publish its discovery file.
+const daemonStartTimeout = 5 * time.Second
+
+var proxyAttachCmd = &cobra.Command{
+	Use:   "attach",
+	Short: "Attach to the shared rotation proxy, starting it if needed",
+	Long:  "Attaches the calling session to the per-user shared account-rotation proxy daemon, starting one in the background when none is running, and prints its {\"port\":N,\"key\":\"...\"} startup line. The daemon exits on its own once no attached session is alive for --idle-timeout.",
+	RunE:  runProxyAttach,
+}
+
+func init() {
+	proxyCmd.Flags().BoolVar(&proxyDaemon, "daemon", false, "run as the per-user shared daemon (lockfile + discovery file in --state-dir)")
+	proxyCmd.Flags().StringVar(&proxyStateDir, "state-dir", "", "directory for the daemon lock, discovery and log files (defaults to the accounts dir's parent)")
+	proxyCmd.Flags().DurationVar(&proxyIdleTimeout, "idle-timeout", 5*time.Minute, "daemon: exit after this long with no live attached session")
+
+	f := proxyAttachCmd.Flags()
+	f.StringVar(&proxyAccountsDir, "accounts-dir", "", "directory holding per-account credential dirs")
+	f.StringVar(&proxyListFile, "list", "", "path to the claude-accounts.list file (label:dir lines)")
+	f.Float64Var(&proxyThreshold, "threshold", 0.98, "utilization (0-1) at which to switch accounts")
+	f.StringVar(&proxyUpstream, "upstream", "https://api.anthropic.com", "upstream Anthropic base URL")
+	f.BoolVar(&proxyMITM, "mitm", true, "enable the CONNECT/MITM forward proxy")
+	f.StringVar(&proxyPolicy, "policy", proxy.PolicyDrain, "rotation policy: "+strings.Join(proxy.PolicyNames, ", "))
+	f.IntVar(&proxyMaxAttempts, "max-attempts", proxy.DefaultRetryPolicy().MaxAttempts, "attempts per request on one account while upstream is overloaded (529)")
+	f.StringVar(&proxyStateDir, "state-dir", "", "directory for the daemon lock, discovery and log files (defaults to the accounts dir's parent)")
+	f.DurationVar(&proxyIdleTimeout, "idle-timeout", 5*time.Minute, "exit the daemon after this long with no live attached session")
+	f.IntVar(&proxyAttachPID, "client-pid", 0, "pid of the session referencing the daemon (defaults to the parent process)")
+	proxyCmd.AddCommand(proxyAttachCmd)
+}
+
+// proxyDaemonStateDir resolves where the daemon keeps its lock/discovery/log.
+func proxyDaemonStateDir() string {
+	if proxyStateDir != "" {
+		return proxyStateDir
+	}
+	return filepath.Dir(proxyAccountsDir)
+}
+
+func runProxyAttach(cmd *cobra.Command, args []string) error {
+	if _, err := proxy.ParsePolicy(proxyPolicy); err != nil {
+		return err
+	}
+	if proxyMaxAttempts < 1 {
+		return fmt.Errorf("--max-attempts must be at least 1, got %d", proxyMaxAttempts)
+	}
+	pid := proxyAttachPID
+	if pid == 0 {
+		pid = os.Getppid()
+	}
+	// The policy is always sent, so one changed in Settings reaches a daemon
+	// already running; the threshold only when given explicitly.
+	attach := proxy.AttachRequest{PID: pid, Policy: proxyPolicy}
+	if cmd.Flags().Changed("threshold") {
+		attach.Threshold = proxyThreshold
+	}
+	stateDir := proxyDaemonStateDir()
+	info, err := attachDaemon(stateDir, attach, func() error { return spawnProxyDaemon(stateDir) }, daemonStartTimeout)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(cmd.OutOrStdout(), proxyStartupJSON(info.Port, info.Key, info.CA))
+	return nil
+}
+
+// attachDaemon registers the launcher with the daemon published in stateDir,
+// applying its settings (see proxy.AttachRequest). When none answers it calls
+// spawn once and waits up to timeout for the new daemon (or a concurrently
+// started one that won the lock) to come up.
+func attachDaemon(stateDir string, attach proxy.AttachRequest, spawn func() error, timeout time.Duration) (proxy.DaemonInfo, error) {
+	path := filepath.Join(stateDir, proxy.DaemonFile)
+	try := func() (proxy.DaemonInfo, bool) {
+		info, err := proxy.ReadDaemonInfo(path)
+		if err != nil {
+			return info, false
+		}
+		// Registering doubles as the liveness probe, and the record's pid plays
+		// no part in it: the probe goes to the record's port and authenticates
+		// with its key, a secret unique to that daemon, so a record left by a
+		// dead daemon fails here even when its pid or port has been reused.
+		err = proxy.AttachSession(fmt.Sprintf("http://127.0.0.1:%d", info.Port), info.Key, attach)
+		return info, err == nil
+	}
+	if info, ok := try(); ok {
+		return info, nil
+	}
+	if err := spawn(); err != nil {
+		return proxy.DaemonInfo{}, fmt.Errorf("start shared proxy: %w", err)
+	}
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); {
+		time.Sleep(100 * time.Millisecond)
+		if info, ok := try(); ok {
+			return info, nil
+		}
+	}
+	return proxy.DaemonInfo{}, fmt.Errorf("shared proxy did not start within %s (see %s)", timeout, filepath.Join(stateDir, proxy.DaemonLogFile))
+}
+
+// spawnProxyDaemon starts `proxy --daemon` detached in its own session, logging
+// to the state dir, so it outlives the launcher that started it.
+func spawnProxyDaemon(stateDir string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(stateDir, 0o700); err != nil {
+		return err
+	}
+	logf, err := os.OpenFile(filepath.Join(stateDir, proxy.DaemonLogFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer logf.Close()
+	c := exec.Command(exe, "proxy", "--daemon",
+		"--accounts-dir", proxyAccountsDir,
+		"--list", proxyListFile,
+		"--threshold", strconv.FormatFloat(proxyThreshold, 'f', -1, 64),
+		"--upstream", proxyUpstream,
+		"--mitm="+strconv.FormatBool(proxyMITM),
+		"--policy", proxyPolicy,
+		"--max-attempts", strconv.Itoa(proxyMaxAttempts),
+		"--state-dir", stateDir,
+		"--idle-timeout", proxyIdleTimeout.String(),
+	)
+	c.Stdout, c.Stderr = logf, logf
+	c.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Process.Release()
+}
+
+// serveDaemon publishes the discovery record and serves until ctx is done or
+// no attached session has been alive for proxyIdleTimeout, then unpublishes
+// and shuts down gracefully (see serveProxy).
+func serveDaemon(ctx context.Context, httpSrv *http.Server, ln net.Listener, sessions *proxy.Sessions, info proxy.DaemonInfo) error {
+	path := filepath.Join(proxyDaemonStateDir(), proxy.DaemonFile)
+	if err := proxy.WriteDaemonInfo(path, info); err != nil {
+		return fmt.Errorf("publish daemon: %w", err)
+	}
+	defer os.Remove(path)
+
+	serveCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go func() {
+		defer stop()
+		t := time.NewTicker(daemonIdleCheck)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+			case <-serveCtx.Done():
+				return
+			case <-t.C:
+				idle := sessions.IdleFor(time.Now())
+				if idle < proxyIdleTimeout {
+					continue
+				}
+				log.Printf("[wisp-deck-proxy] no attached sessions for %s — shutting down", idle.Round(time.Second))
+			}
+			// Unpublish first so launchers start a fresh daemon instead of
+			// attaching to one that is going away.
+			os.Remove(path)
+			return
+		}
+	}()
+	return serveProxy(serveCtx, httpSrv, ln)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jackuait/wisp-deck/internal/proxy"
+)
+
+var (
+	proxyStatusPort int
+	proxyStatusKey  string
+	proxyStatusJSON bool
+)
+
+var proxyStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the live account pool of a running rotation proxy",
+	Long:  "Connects to a running account-rotation proxy and prints each account's 5h/7d utilization, reset times, throttle deadline and errored flag. Port and key default to $WISP_DECK_PROXY_PORT and $WISP_DECK_PROXY_KEY.",
+	RunE:  runProxyStatus,
+}
+
+func init() {
+	proxyStatusCmd.Flags().IntVar(&proxyStatusPort, "port", 0, "proxy port (defaults to $WISP_DECK_PROXY_PORT)")
+	proxyStatusCmd.Flags().StringVar(&proxyStatusKey, "key", "", "proxy key (defaults to $WISP_DECK_PROXY_KEY)")
+	proxyStatusCmd.Flags().BoolVar(&proxyStatusJSON, "json", false, "print the raw status JSON instead of a table")
+	proxyCmd.AddCommand(proxyStatusCmd)
+}
+
+func runProxyStatus(cmd *cobra.Command, args []string) error {
+	port, key := proxyStatusPort, proxyStatusKey
+	if port == 0 {
+		port, _ = strconv.Atoi(os.Getenv("WISP_DECK_PROXY_PORT"))
+	}
+	if key == "" {
+		key = os.Getenv("WISP_DECK_PROXY_KEY")
+	}
+	if port == 0 || key == "" {
+		return fmt.Errorf("no running proxy: pass --port and --key (or set WISP_DECK_PROXY_PORT/WISP_DECK_PROXY_KEY)")
+	}
+
+	st, err := proxy.FetchStatus(fmt.Sprintf("http://127.0.0.1:%d", port), key)
+	if err != nil {
+		return fmt.Errorf("proxy status: %w", err)
+	}
+	if proxyStatusJSON {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(st)
+	}
+	renderProxyStatus(cmd.OutOrStdout(), st)
+	return nil
+}
+
+// renderProxyStatus prints the pool snapshot as an aligned table, one account
+// per row, with "*" marking the active account and relative times measured
+// from the snapshot's own clock.
+func renderProxyStatus(w io.Writer, st proxy.PoolStatus) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\tACCOUNT\tSTATE\t5H\t5H RESET\t7D\t7D RESET\tTHROTTLED\tCONVS")
+	for _, a := range st.Accounts {
+		mark := ""
+		if a.Active {
+			mark = "*"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n",
+			mark, a.Label, accountState(a),
+			fmtUtil(a.Util5h), fmtUntil(st.Now, a.Reset5h),
+			fmtUtil(a.Util7d), fmtUntil(st.Now, a.Reset7d),
+			fmtUntil(st.Now, a.ThrottledUntil), a.Conversations)
+	}
+	tw.Flush()
+
+	avail := 0
+	for _, a := range st.Accounts {
+		if a.Available {
+			avail++
+		}
+	}
+	fmt.Fprintf(w, "\n%d of %d accounts available (policy %s, switch threshold %.0f%%)\n", avail, len(st.Accounts), st.Policy, st.Threshold*100)
+}
+
+// accountState summarizes why an account can or cannot serve requests.
+func accountState(a proxy.AccountStatus) string {
+	switch {
+	case a.Errored:
+		return "errored"
+	case !a.ThrottledUntil.IsZero():
+		return "throttled"
+	case a.Status == "rejected":
+		return "rejected"
+	case a.Capped != "":
+		return "capped (" + a.Capped + ")"
+	case !a.Available:
+		return "near quota"
+	default:
+		return "ok"
+	}
+}
+
+// fmtUtil renders a 0-1 utilization as a percentage, "?" when unknown.
+func fmtUtil(u float64) string {
+	if u < 0 {
+		return "?"
+	}
+	return fmt.Sprintf("%.0f%%", u*100)
+}
+
+// fmtUntil renders the time remaining until t, "-" when t is unset or past.
+func fmtUntil(now, t time.Time) string {
+	if t.IsZero() || !t.After(now) {
+		return "-"
+	}
+	d := t.Sub(now).Round(time.Minute)
+	if d < time.Minute {
+		return "<1m"
+	}
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
@@ -1,26 +1,47 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/jackuait/wisp-deck/internal/tui"
+	"github.com/jackuait/wisp-deck/internal/usage"
 	"github.com/jackuait/wisp-deck/internal/util"
 	"github.com/spf13/cobra"
 )
 
+var (
+	statsFormat  string
+	statsSince   string
+	statsUntil   string
+	statsGroupBy string
+)
+
 var statsCmd = &cobra.Command{
 	Use:   "stats",
 	Short: "Show monthly token usage",
-	Long:  "Displays Claude Code token usage aggregated by month.",
+	Long:  "Displays Claude Code token usage aggregated by month, with today so far at the top, a daily chart and model mix for the selected month, and Enter to drill into its days, projects and conversations, rescanning live while sessions run. With --format it prints a report to stdout instead (json, csv or markdown), optionally limited to --since/--until and grouped by day, week, month, project, model or account.",
 	RunE:  runStats,
 }
 
 func init() {
+	statsCmd.Flags().StringVar(&statsFormat, "format", "", "print a report instead of opening the TUI: json, csv or markdown")
+	statsCmd.Flags().StringVar(&statsSince, "since", "", "first day to include, YYYY-MM-DD or YYYY-MM (inclusive)")
+	statsCmd.Flags().StringVar(&statsUntil, "until", "", "last day to include, YYYY-MM-DD or YYYY-MM (inclusive)")
+	statsCmd.Flags().StringVar(&statsGroupBy, "group-by", string(usage.ByMonth), "report grouping: day, week, month, project, model or account")
 	rootCmd.AddCommand(statsCmd)
 }
 
 func runStats(cmd *cobra.Command, args []string) error {
+	if statsFormat != "" {
+		return runStatsReport(cmd)
+	}
+	if statsSince != "" || statsUntil != "" || cmd.Flags().Changed("group-by") {
+		return errors.New("--since, --until and --group-by only apply to a report; add --format")
+	}
+
 	tui.ApplyTheme(effectiveTheme(aiToolFlag))
 
 	model := tui.NewStatsModel()
@@ -31,10 +52,44 @@ func runStats(cmd *cobra.Command, args []string) error {
 	}
 	defer cleanup()
 
-	opts := append([]tea.ProgramOption{tea.WithAltScreen()}, ttyOpts...)
+	opts := append([]tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseAllMotion()}, ttyOpts...)
 	p := tea.NewProgram(model, opts...)
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("failed to run TUI: %w", err)
 	}
 	return nil
 }
+
+// runStatsReport validates the report flags, aggregates usage the same way the
+// TUI does, and writes the report to stdout. No terminal is needed.
+func runStatsReport(cmd *cobra.Command) error {
+	write, ok := statsWriters[statsFormat]
+	if !ok {
+		return fmt.Errorf("unknown --format %q (want json, csv or markdown)", statsFormat)
+	}
+	by, err := usage.ParseDimension(statsGroupBy)
+	if err != nil {
+		return fmt.Errorf("--group-by: %w", err)
+	}
+	for _, b := range []struct{ flag, value string }{{"--since", statsSince}, {"--until", statsUntil}} {
+		if err := validateStatsBound(b.value); err != nil {
+			return fmt.Errorf("%s: %w", b.flag, err)
+		}
+	}
+
+	home, _ := os.UserHomeDir()
+	if err := usage.LoadPricing(usage.PricingPath(home)); err != nil {
+		return fmt.Errorf("pricing overrides: %w", err)
+	}
+	_, _, cachePath := usage.DefaultPaths(home)
+	records, err := usage.Collect(usage.DefaultSources(home), cachePath)
+	if err != nil {
+		return fmt.Errorf("aggregate usage: %w", err)
+	}
+	records = usage.FilterRecords(records, statsSince, statsUntil)
+	report := buildStatsReport(records, by, statsSince, statsUntil)
+	if by == usage.ByAccount {
+		labelAccounts(&report, usage.AccountLabels(home))
+	}
+	return write(cmd.OutOrStdout(), report)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jackuait/wisp-deck/internal/usage"
+)
+
+var (
+	statsPricingFile      string
+	statsPricingProviders []string
+)
+
+// defaultPricingProviders are the models.dev providers imported by default:
+// the first-party APIs whose model ids the transcripts record, ahead of any
+// reseller listing the same id at its own price.
+var defaultPricingProviders = []string{
+	"anthropic", "openai", "google", "xai", "deepseek", "alibaba", "moonshotai", "zai", "xiaomi", "mistral",
+}
+
+var statsPricingCmd = &cobra.Command{
+	Use:   "pricing",
+	Short: "Manage the pricing overrides used for cost estimates",
+}
+
+var statsPricingImportCmd = &cobra.Command{
+	Use:   "import <models.dev api.json>",
+	Short: "Refresh the pricing overrides from a models.dev dump",
+	Long:  "Reads a local models.dev API dump (https://models.dev/api.json) and merges every priced model of the chosen providers into the pricing override file, replacing entries with the same id and keeping the rest. Overrides are matched by longest model-id prefix over the built-in prices.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runStatsPricingImport,
+}
+
+func init() {
+	statsPricingImportCmd.Flags().StringVar(&statsPricingFile, "file", "", "pricing override file (default $XDG_CONFIG_HOME/wisp-deck/pricing.json, or ~/.config/wisp-deck/pricing.json)")
+	statsPricingImportCmd.Flags().StringSliceVar(&statsPricingProviders, "provider", defaultPricingProviders, "models.dev providers to import, earlier ones winning on duplicate ids")
+	statsPricingCmd.AddCommand(statsPricingImportCmd)
+	statsCmd.AddCommand(statsPricingCmd)
+}
+
+func runStatsPricingImport(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	imported, found, err := usage.ImportModelsDev(data, statsPricingProviders)
+	if err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		return fmt.Errorf("%s lists none of the providers %s", args[0], strings.Join(statsPricingProviders, ", "))
+	}
+
+	path := statsPricingFile
+	if path == "" {
+		home, _ := os.UserHomeDir()
+		path = usage.PricingPath(home)
+	}
+	f, err := usage.ReadPricing(path)
+	if err != nil {
+		return err
+	}
+	for id, o := range imported {
+		f.Models[id] = o
+	}
+	if err := f.Save(path); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "imported %d model prices from %s into %s\n", len(imported), strings.Join(found, ", "), path)
+	return nil
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackuait/wisp-deck/internal/usage"
+)
+
+// statsReport is the non-interactive `stats --format` output: one row per group
+// with its per-model breakdown, plus a grand total over every group.
+type statsReport struct {
+	GroupBy string           `json:"group_by"`
+	Since   string           `json:"since,omitempty"`
+	Until   string           `json:"until,omitempty"`
+	Groups  []statsReportRow `json:"groups"`
+	Total   statsReportRow   `json:"total"`
+}
+
+// statsReportRow is one group (or the grand total). Label is the account's
+// display name when grouping by account. PartiallyUnpriced is set when a model
+// in it has no pricing entry, so CostUSD is a lower bound.
+type statsReportRow struct {
+	Key               string             `json:"key"`
+	Label             string             `json:"label,omitempty"`
+	Input             int64              `json:"input"`
+	Output            int64              `json:"output"`
+	CacheWrite        int64              `json:"cache_write"`
+	CacheRead         int64              `json:"cache_read"`
+	Total             int64              `json:"total"`
+	CostUSD           float64            `json:"cost_usd"`
+	PartiallyUnpriced bool               `json:"partially_unpriced"`
+	Models            []statsReportModel `json:"models"`
+}
+
+// statsReportModel is one model's share of a row. Priced is false when the
+// model has no pricing entry (its CostUSD is then 0).
+type statsReportModel struct {
+	Model      string  `json:"model"`
+	Input      int64   `json:"input"`
+	Output     int64   `json:"output"`
+	CacheWrite int64   `json:"cache_write"`
+	CacheRead  int64   `json:"cache_read"`
+	Total      int64   `json:"total"`
+	CostUSD    float64 `json:"cost_usd"`
+	Priced     bool    `json:"priced"`
+}
+
+// validateStatsBound accepts an empty bound, a day (YYYY-MM-DD) or a month
+// (YYYY-MM), the shapes usage.FilterRecords compares against.
+func validateStatsBound(s string) error {
+	if s == "" {
+		return nil
+	}
+	if _, err := time.Parse("2006-01-02", s); err == nil {
+		return nil
+	}
+	if _, err := time.Parse("2006-01", s); err == nil {
+		return nil
+	}
+	return fmt.Errorf("%q is not YYYY-MM-DD or YYYY-MM", s)
+}
+
+// cents rounds a USD estimate to whole cents for reporting.
+func cents(usd float64) float64 {
+	return math.Round(usd*100) / 100
+}
+
+// reportRow converts a folded group into a report row.
+func reportRow(g usage.GroupUsage) statsReportRow {
+	usd, allPriced := g.CostUSD()
+	row := statsReportRow{
+		Key:               g.Key,
+		Input:             g.Input,
+		Output:            g.Output,
+		CacheWrite:        g.CacheWrite,
+		CacheRead:         g.CacheRead,
+		Total:             g.Total(),
+		CostUSD:           cents(usd),
+		PartiallyUnpriced: !allPriced,
+		Models:            make([]statsReportModel, 0, len(g.Models)),
+	}
+	for _, m := range g.Models {
+		usd, priced := usage.ModelCostUSD(m)
+		row.Models = append(row.Models, statsReportModel{
+			Model:      m.Model,
+			Input:      m.Input,
+			Output:     m.Output,
+			CacheWrite: m.CacheWrite,
+			CacheRead:  m.CacheRead,
+			Total:      m.Total(),
+			CostUSD:    cents(usd),
+			Priced:     priced,
+		})
+	}
+	return row
+}
+
+// buildStatsReport groups records by by and totals them.
+func buildStatsReport(records []usage.Record, by usage.Dimension, since, until string) statsReport {
+	r := statsReport{GroupBy: string(by), Since: since, Until: until, Groups: []statsReportRow{}}
+	for _, g := range usage.GroupRecords(records, by) {
+		r.Groups = append(r.Groups, reportRow(g))
+	}
+	total := usage.GroupUsage{Key: "total"}
+	for _, m := range usage.GroupRecords(records, usage.ByModel) {
+		total.Input += m.Input
+		total.Output += m.Output
+		total.CacheWrite += m.CacheWrite
+		total.CacheRead += m.CacheRead
+		total.Models = append(total.Models, m.Models...)
+	}
+	r.Total = reportRow(total)
+	return r
+}
+
+// labelAccounts names each account group after its login, as the menu does.
+func labelAccounts(r *statsReport, labels map[string]string) {
+	for i := range r.Groups {
+		r.Groups[i].Label = usage.AccountLabel(labels, r.Groups[i].Key)
+	}
+}
+
+// name is how the CSV and markdown reports show a group.
+func (row statsReportRow) name() string {
+	if row.Label != "" {
+		return row.Label
+	}
+	return row.Key
+}
+
+// statsWriters maps each --format to its writer.
+var statsWriters = map[string]func(io.Writer, statsReport) error{
+	"json":     writeStatsJSON,
+	"csv":      writeStatsCSV,
+	"markdown": writeStatsMarkdown,
+}
+
+func writeStatsJSON(w io.Writer, r statsReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// writeStatsCSV writes one row per group and model — the flat shape that
+// pivots cleanly in a spreadsheet. Group totals are left to the sum so nothing
+// is counted twice.
+func writeStatsCSV(w io.Writer, r statsReport) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{r.GroupBy, "model", "input", "output", "cache_write", "cache_read", "total", "cost_usd", "priced"})
+	for _, g := range r.Groups {
+		for _, m := range g.Models {
+			cw.Write([]string{
+				g.name(), m.Model,
+				strconv.FormatInt(m.Input, 10), strconv.FormatInt(m.Output, 10),
+				strconv.FormatInt(m.CacheWrite, 10), strconv.FormatInt(m.CacheRead, 10),
+				strconv.FormatInt(m.Total, 10),
+				strconv.FormatFloat(m.CostUSD, 'f', 2, 64),
+				strconv.FormatBool(m.Priced),
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeStatsMarkdown writes a report table: each group's row followed by its
+// models, then the grand total. A "~" marks costs that are a lower bound.
+func writeStatsMarkdown(w io.Writer, r statsReport) error {
+	var b strings.Builder
+	title := strings.ToUpper(r.GroupBy[:1]) + r.GroupBy[1:]
+	fmt.Fprintf(&b, "# Token usage by %s\n\n", r.GroupBy)
+	if r.Since != "" || r.Until != "" {
+		fmt.Fprintf(&b, "Range: %s – %s\n\n", openBound(r.Since), openBound(r.Until))
+	}
+	fmt.Fprintf(&b, "| %s | Model | Input | Output | Cache W | Cache R | Total | Cost (USD) |\n", title)
+	b.WriteString("|---|---|--:|--:|--:|--:|--:|--:|\n")
+	partial := false
+	line := func(key, model string, in, out, cw, cr, total int64, cost string) {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			mdEscape(key), mdEscape(model), fmtCount(in), fmtCount(out), fmtCount(cw), fmtCount(cr), fmtCount(total), cost)
+	}
+	rowCost := func(row statsReportRow) string {
+		if row.PartiallyUnpriced {
+			partial = true
+			return "~" + fmtUSD(row.CostUSD)
+		}
+		return fmtUSD(row.CostUSD)
+	}
+	for _, g := range r.Groups {
+		key := g.name()
+		if key == "" {
+			key = "(unknown)" // e.g. usage migrated without a project
+		}
+		line("**"+key+"**", "", g.Input, g.Output, g.CacheWrite, g.CacheRead, g.Total, "**"+rowCost(g)+"**")
+		if r.GroupBy == string(usage.ByModel) {
+			continue // the group is the model
+		}
+		for _, m := range g.Models {
+			cost := "—"
+			if m.Priced {
+				cost = fmtUSD(m.CostUSD)
+			}
+			line("", m.Model, m.Input, m.Output, m.CacheWrite, m.CacheRead, m.Total, cost)
+		}
+	}
+	t := r.Total
+	line("**Total**", "", t.Input, t.Output, t.CacheWrite, t.CacheRead, t.Total, "**"+rowCost(t)+"**")
+	if partial {
+		b.WriteString("\n~ partially unpriced: some models have no pricing entry, so the cost is a lower bound.\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// openBound renders a range bound, "…" when open.
+func openBound(s string) string {
+	if s == "" {
+		return "…"
+	}
+	return s
+}
+
+// mdEscape keeps a key (a project path may contain anything) from breaking
+// the table.
+func mdEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// fmtCount renders n with comma thousands separators.
+func fmtCount(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	var out []byte
+	for i := 0; i < len(s); i++ {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			out = append(out, ',')
+		}
+		out = append(out, s[i])
+	}
+	return string(out)
+}
+
+// fmtUSD renders a dollar amount with cents and thousands separators.
+func fmtUSD(v float64) string {
+	c := int64(math.Round(v * 100))
+	return fmt.Sprintf("$%s.%02d", fmtCount(c/100), c%100)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jackuait/wisp-deck/internal/usage"
+)
+
+// statsHome lays out a home dir with two Claude transcripts in May and June
+// 2026 and points the stats command at it.
+func statsHome(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("OPENCODE_DATA_DIR", filepath.Join(home, "opencode"))
+	proj := filepath.Join(home, ".claude", "projects", "-src-app")
+	if err := os.MkdirAll(proj, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(proj, "a.jsonl"), []byte(
+		`{"type":"assistant","timestamp":"2026-05-04T10:00:00Z","cwd":"/src/app","message":{"id":"1","model":"claude-opus-4-7","usage":{"input_tokens":1000000,"output_tokens":10}}}`+"\n"+
+			`{"type":"assistant","timestamp":"2026-05-05T10:00:00Z","cwd":"/src/app","message":{"id":"2","model":"mystery-model","usage":{"input_tokens":7}}}`+"\n"), 0o644)
+	os.WriteFile(filepath.Join(proj, "b.jsonl"), []byte(
+		`{"type":"assistant","timestamp":"2026-06-01T10:00:00Z","cwd":"/src/web","message":{"id":"3","model":"claude-opus-4-7","usage":{"input_tokens":2000000}}}`+"\n"), 0o644)
+}
+
+// execStats runs `stats` with args, resetting its flags first so runs do not
+// leak into each other.
+func execStats(t *testing.T, args ...string) string {
+	t.Helper()
+	statsFormat, statsSince, statsUntil = "", "", ""
+	statsCmd.Flags().Set("group-by", "month")
+	statsCmd.Flags().Lookup("group-by").Changed = false
+	return execRoot(t, append([]string{"stats"}, args...)...)
+}
+
+func TestStatsReport_jsonGroupsByMonthWithCostAndUnpricedFlag(t *testing.T) {
+	statsHome(t)
+	var r statsReport
+	if err := json.Unmarshal([]byte(execStats(t, "--format", "json")), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.GroupBy != "month" || len(r.Groups) != 2 || r.Groups[0].Key != "2026-06" || r.Groups[1].Key != "2026-05" {
+		t.Fatalf("groups = %+v, want June then May", r.Groups)
+	}
+	may := r.Groups[1]
+	if !may.PartiallyUnpriced || len(may.Models) != 2 || may.Models[1].Model != "mystery-model" || may.Models[1].Priced {
+		t.Errorf("May = %+v, want the unknown model flagged unpriced", may)
+	}
+	if r.Groups[0].PartiallyUnpriced || r.Groups[0].CostUSD <= 0 {
+		t.Errorf("June = %+v, want a fully priced cost", r.Groups[0])
+	}
+	if r.Total.Input != 3_000_007 || !r.Total.PartiallyUnpriced {
+		t.Errorf("total = %+v", r.Total)
+	}
+}
+
+func TestStatsReport_filtersAndGroupsByProject(t *testing.T) {
+	statsHome(t)
+	var r statsReport
+	json.Unmarshal([]byte(execStats(t, "--format", "json", "--since", "2026-05-05", "--until", "2026-06", "--group-by", "project")), &r)
+	if len(r.Groups) != 2 || r.Groups[0].Key != "/src/web" || r.Groups[1].Key != "/src/app" || r.Groups[1].Input != 7 {
+		t.Errorf("groups = %+v, want /src/web then /src/app with only May 5", r.Groups)
+	}
+}
+
+func TestStatsReport_csvHasOneRowPerGroupAndModel(t *testing.T) {
+	statsHome(t)
+	rows, err := csv.NewReader(strings.NewReader(execStats(t, "--format", "csv", "--group-by", "day"))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || strings.Join(rows[0], ",") != "day,model,input,output,cache_write,cache_read,total,cost_usd,priced" {
+		t.Fatalf("csv = %v", rows)
+	}
+	if rows[1][0] != "2026-06-01" || rows[2][1] != "mystery-model" || rows[2][8] != "false" {
+		t.Errorf("csv rows = %v", rows[1:])
+	}
+}
+
+func TestStatsReport_markdownMarksPartialCosts(t *testing.T) {
+	statsHome(t)
+	out := execStats(t, "--format", "markdown")
+	for _, want := range []string{"# Token usage by month", "| Month | Model |", "| **2026-05** |", "| **Total** |", "~$", "partially unpriced", "1,000,007"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestStatsReport_rejectsBadFlags(t *testing.T) {
+	statsHome(t)
+	for _, args := range [][]string{
+		{"--format", "xml"},
+		{"--format", "json", "--group-by", "year"},
+		{"--format", "json", "--since", "May"},
+		{"--since", "2026-05"},
+	} {
+		statsFormat, statsSince, statsUntil = "", "", ""
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetErr(&buf)
+		rootCmd.SetArgs(append([]string{"stats"}, args...))
+		if err := rootCmd.Execute(); err == nil {
+			t.Errorf("stats %v: want an error", args)
+		}
+	}
+}
+
+func TestFmtUSD_roundsToCents(t *testing.T) {
+	for v, want := range map[float64]string{0: "$0.00", 1.999: "$2.00", 1234.5: "$1,234.50"} {
+		if got := fmtUSD(v); got != want {
+			t.Errorf("fmtUSD(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func TestStatsPricingImport_mergesIntoOverrideFile(t *testing.T) {
+	dir := t.TempDir()
+	dump := filepath.Join(dir, "api.json")
+	os.WriteFile(dump, []byte(`{"anthropic":{"models":{"claude-nova-6":{"cost":{"input":7,"output":35,"cache_read":0.7}}}}}`), 0o644)
+	file := filepath.Join(dir, "pricing.json")
+	os.WriteFile(file, []byte(`{"models":{"my-local-model":{"input":1,"output":2}}}`), 0o644)
+
+	out := execRoot(t, "stats", "pricing", "import", dump, "--file", file)
+	if !strings.Contains(out, "imported 1 model prices from anthropic") {
+		t.Errorf("output = %q", out)
+	}
+	f, err := usage.ReadPricing(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Models) != 2 || f.Models["claude-nova-6"].Input != 7 || f.Models["my-local-model"].Output != 2 {
+		t.Errorf("pricing file = %+v, want the import merged over the manual entry", f.Models)
+	}
+}
+
+// A model released after the binary is priced once the override file lists it.
+func TestStatsReport_usesPricingOverrides(t *testing.T) {
+	statsHome(t)
+	home, _ := os.UserHomeDir()
+	os.MkdirAll(filepath.Dir(usage.PricingPath(home)), 0o755)
+	os.WriteFile(usage.PricingPath(home), []byte(`{"models":{"mystery-model":{"input":1000000,"output":0}}}`), 0o644)
+	t.Cleanup(func() { usage.SetPricingOverrides(nil) })
+
+	var r statsReport
+	json.Unmarshal([]byte(execStats(t, "--format", "json", "--group-by", "model")), &r)
+	if r.Total.PartiallyUnpriced {
+		t.Errorf("total = %+v, want every model priced", r.Total)
+	}
+	for _, g := range r.Groups {
+		if g.Key == "mystery-model" && g.CostUSD != 7 {
+			t.Errorf("mystery-model cost = %v, want 7 from the override", g.CostUSD)
+		}
+	}
+}
+
+func TestStatsReport_groupsByAccountUnderTheirLabels(t *testing.T) {
+	statsHome(t)
+	cfg := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "wisp-deck")
+	work := filepath.Join(cfg, "claude-accounts", "work", "projects", "-src-app")
+	if err := os.MkdirAll(work, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(work, "w.jsonl"), []byte(
+		`{"type":"assistant","timestamp":"2026-05-06T10:00:00Z","cwd":"/src/app","message":{"id":"w","model":"claude-opus-4-7","usage":{"input_tokens":5000000}}}`+"\n"), 0o644)
+	os.WriteFile(filepath.Join(cfg, "claude-accounts.list"), []byte("Work Max:work\n"), 0o644)
+
+	var r statsReport
+	if err := json.Unmarshal([]byte(execStats(t, "--format", "json", "--group-by", "account")), &r); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Groups) != 2 || r.Groups[0].Key != "work" || r.Groups[0].Label != "Work Max" || r.Groups[0].Total != 5000000 ||
+		r.Groups[1].Key != usage.DefaultAccount || r.Groups[1].Label != "Default" {
+		t.Fatalf("groups = %+v, want Work Max then Default", r.Groups)
+	}
+	if md := execStats(t, "--format", "markdown", "--group-by", "account"); !strings.Contains(md, "| **Work Max** |") {
+		t.Errorf("markdown should name the account by its label:\n%s", md)
+	}
+}
@@ -0,0 +1,111 @@
+package claudeaccount
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Caps are per-login usage limits the account proxy enforces on top of its
+// pool-wide switch threshold: a login at or past any of its caps is taken out
+// of rotation until the cap lifts. Zero fields are unset.
+type Caps struct {
+	Max5h       float64 // 0-1 utilization of the 5-hour window
+	Max7d       float64 // 0-1 utilization of the weekly window
+	DailyOutput int64   // output tokens per local calendar day
+}
+
+// Cap spec keys, as written in the caps sidecar and typed in the Login panel.
+const (
+	capKey5h          = "5h"
+	capKey7d          = "7d"
+	capKeyDailyOutput = "daily-out"
+)
+
+// IsZero reports whether no cap is set.
+func (c Caps) IsZero() bool { return c == Caps{} }
+
+// String renders the caps in the spec form ParseCaps reads, e.g.
+// "7d=80,daily-out=200000"; "" when none are set.
+func (c Caps) String() string {
+	var parts []string
+	if c.Max5h > 0 {
+		parts = append(parts, capKey5h+"="+strconv.FormatFloat(c.Max5h*100, 'f', -1, 64))
+	}
+	if c.Max7d > 0 {
+		parts = append(parts, capKey7d+"="+strconv.FormatFloat(c.Max7d*100, 'f', -1, 64))
+	}
+	if c.DailyOutput > 0 {
+		parts = append(parts, capKeyDailyOutput+"="+strconv.FormatInt(c.DailyOutput, 10))
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseCaps reads a cap spec: comma- or space-separated key=value pairs where
+// 5h and 7d take a percentage (1-100, optional "%") and daily-out an output
+// token count (optional k/m suffix). An empty spec clears all caps.
+func ParseCaps(spec string) (Caps, error) {
+	var c Caps
+	err := eachSpecSetting(spec, "cap", func(key, val string) error {
+		switch key {
+		case capKey5h, capKey7d:
+			pct, err := strconv.ParseFloat(strings.TrimSuffix(val, "%"), 64)
+			if err != nil || pct <= 0 || pct > 100 {
+				return fmt.Errorf("cap %s: want a percentage between 1 and 100, got %q", key, val)
+			}
+			if key == capKey5h {
+				c.Max5h = pct / 100
+			} else {
+				c.Max7d = pct / 100
+			}
+		case capKeyDailyOutput:
+			n, err := parseTokenCount(val)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("cap %s: want a token count such as 200000 or 200k, got %q", key, val)
+			}
+			c.DailyOutput = n
+		default:
+			return fmt.Errorf("unknown cap %q (want %s, %s or %s)", key, capKey5h, capKey7d, capKeyDailyOutput)
+		}
+		return nil
+	})
+	if err != nil {
+		return Caps{}, err
+	}
+	return c, nil
+}
+
+// parseTokenCount parses an integer with an optional k (thousand) or m
+// (million) suffix.
+func parseTokenCount(s string) (int64, error) {
+	mult := int64(1)
+	switch {
+	case strings.HasSuffix(strings.ToLower(s), "k"):
+		mult, s = 1_000, s[:len(s)-1]
+	case strings.HasSuffix(strings.ToLower(s), "m"):
+		mult, s = 1_000_000, s[:len(s)-1]
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(f * float64(mult)), nil
+}
+
+// CapsFile returns the caps sidecar kept next to an accounts list
+// (claude-accounts.list → claude-accounts.caps).
+func CapsFile(listFile string) string {
+	return sidecarFile(listFile, "caps")
+}
+
+// LoadCaps reads the caps sidecar into a map keyed by account dir. A missing
+// file yields an empty map; lines whose spec does not parse are skipped.
+func LoadCaps(path string) (map[string]Caps, error) {
+	return loadSidecar(path, ParseCaps)
+}
+
+// SaveCaps sets (or, for zero caps, removes) one account's caps in the sidecar,
+// keeping every other account's line.
+func SaveCaps(path, dir string, c Caps) error {
+	return saveSidecar(path, dir, c.String())
+}
@@ -0,0 +1,61 @@
+package claudeaccount
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCaps_roundTripsThroughString(t *testing.T) {
+	c, err := ParseCaps("5h=90%, 7d=80 daily-out=200k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Caps{Max5h: 0.9, Max7d: 0.8, DailyOutput: 200_000}
+	if c != want {
+		t.Fatalf("ParseCaps = %+v, want %+v", c, want)
+	}
+	if s := c.String(); s != "5h=90,7d=80,daily-out=200000" {
+		t.Errorf("String = %q", s)
+	}
+	if again, err := ParseCaps(c.String()); err != nil || again != c {
+		t.Errorf("ParseCaps(String()) = %+v, %v; want %+v", again, err, c)
+	}
+	if c, err := ParseCaps("  "); err != nil || !c.IsZero() {
+		t.Errorf("empty spec = %+v, %v; want zero caps", c, err)
+	}
+}
+
+func TestParseCaps_rejectsBadSpecs(t *testing.T) {
+	for _, spec := range []string{"7d", "7d=0", "5h=120", "daily-out=lots", "monthly=5"} {
+		if _, err := ParseCaps(spec); err == nil {
+			t.Errorf("ParseCaps(%q) should fail", spec)
+		}
+	}
+}
+
+func TestSaveCaps_setsAndClearsOneAccount(t *testing.T) {
+	list := filepath.Join(t.TempDir(), "claude-accounts.list")
+	path := CapsFile(list)
+	if !strings.HasSuffix(path, "claude-accounts.caps") {
+		t.Fatalf("CapsFile = %q", path)
+	}
+	if err := SaveCaps(path, "work", Caps{Max7d: 0.8}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveCaps(path, "home", Caps{DailyOutput: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	caps, err := LoadCaps(path)
+	if err != nil || caps["work"].Max7d != 0.8 || caps["home"].DailyOutput != 1000 {
+		t.Fatalf("LoadCaps = %+v, %v", caps, err)
+	}
+	if err := SaveCaps(path, "work", Caps{}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "home:daily-out=1000\n" {
+		t.Errorf("sidecar = %q, want only the home line after clearing work", data)
+	}
+}
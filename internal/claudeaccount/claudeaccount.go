@@ -6,6 +6,11 @@
 // Storage layout (all under the wisp-deck config dir):
 //   - <accountsDir>/<dir>/          the per-account CLAUDE_CONFIG_DIR (its login)
 //   - <listFile>                    label:dir per line (display label decoupled)
+//   - <listFile minus .list>.caps   dir:caps per line, each login's proxy caps
+//   - <listFile minus .list>.rotation  dir:rotation per line, each login's
+//     priority and weight in the proxy's rotation
+//   - <listFile minus .list>.plans  dir:plan per line, each login's subscription
+//     (see sidecar.go for the sidecar format)
 //   - <pointerFile>                 active dir name, or absent/"default" = the
 //     standard ~/.claude (Keychain) login
 //
@@ -20,6 +25,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/jackuait/wisp-deck/internal/util"
 )
 
 // Account is one selectable native login (display label + config dir name).
@@ -28,6 +35,22 @@ type Account struct {
 	Dir   string
 }
 
+// ParseLine parses one list line, label:dir. Blank lines, comment lines
+// (leading '#') and lines without a colon are not accounts; anything after a
+// second colon is not part of the dir and is ignored.
+func ParseLine(line string) (Account, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return Account{}, false
+	}
+	label, rest, ok := strings.Cut(line, ":")
+	if !ok {
+		return Account{}, false
+	}
+	dir, _, _ := strings.Cut(rest, ":")
+	return Account{Label: label, Dir: dir}, true
+}
+
 var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
 
 // Slugify lowercases label, collapses every run of non-alphanumeric characters
@@ -38,9 +61,9 @@ func Slugify(label string) string {
 	return strings.Trim(s, "-")
 }
 
-// Load parses a label:dir list file into Account entries, skipping blank lines,
-// comment lines (leading '#'), and lines without a colon. Returns nil if the
-// file cannot be read.
+// Load parses a label:dir list file into Account entries (see ParseLine),
+// skipping blank lines, comment lines (leading '#'), and lines without a colon.
+// Returns nil if the file cannot be read.
 func Load(listFile string) []Account {
 	data, err := os.ReadFile(listFile)
 	if err != nil {
@@ -48,15 +71,9 @@ func Load(listFile string) []Account {
 	}
 	var out []Account
 	for _, line := range strings.Split(string(data), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		i := strings.Index(line, ":")
-		if i < 0 {
-			continue
+		if a, ok := ParseLine(line); ok {
+			out = append(out, a)
 		}
-		out = append(out, Account{Label: line[:i], Dir: line[i+1:]})
 	}
 	return out
 }
@@ -171,7 +188,8 @@ func SetDefaultLabel(file, label string) error {
 
 // Rename changes the display label of the account whose dir matches, leaving the
 // config directory (and its login) untouched. It is a no-op if no line matches.
-// The dir name is the stable identifier; only the label changes.
+// The dir name is the stable identifier; only the label changes. The rewrite is
+// atomic, as a running proxy polls the list.
 func Rename(listFile, dir, newLabel string) error {
 	data, err := os.ReadFile(listFile)
 	if err != nil {
@@ -179,20 +197,17 @@ func Rename(listFile, dir, newLabel string) error {
 	}
 	lines := strings.Split(string(data), "\n")
 	for i, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
-			continue
-		}
-		if j := strings.Index(trimmed, ":"); j >= 0 && trimmed[j+1:] == dir {
+		if a, ok := ParseLine(line); ok && a.Dir == dir {
 			lines[i] = newLabel + ":" + dir
 		}
 	}
-	return os.WriteFile(listFile, []byte(strings.Join(lines, "\n")), 0644)
+	return util.WriteFileAtomic(listFile, []byte(strings.Join(lines, "\n")), 0644)
 }
 
 // Remove deletes an account: it drops the matching "label:dir" line from the
-// list file and removes the account's config directory. If the removed account
-// was active, it clears the pointer (reverting to Default).
+// list file, its lines in the settings sidecars (so a later login that reuses
+// the dir starts clean), and the account's config directory. If the removed
+// account was active, it clears the pointer (reverting to Default).
 func Remove(listFile, accountsDir, pointerFile, dir string) error {
 	data, err := os.ReadFile(listFile)
 	if err != nil && !os.IsNotExist(err) {
@@ -204,7 +219,7 @@ func Remove(listFile, accountsDir, pointerFile, dir string) error {
 		if trimmed == "" {
 			continue
 		}
-		if i := strings.Index(trimmed, ":"); i >= 0 && trimmed[i+1:] == dir {
+		if a, ok := ParseLine(trimmed); ok && a.Dir == dir {
 			continue
 		}
 		kept = append(kept, line)
@@ -213,7 +228,10 @@ func Remove(listFile, accountsDir, pointerFile, dir string) error {
 	if out != "" {
 		out += "\n"
 	}
-	if err := os.WriteFile(listFile, []byte(out), 0644); err != nil {
+	if err := util.WriteFileAtomic(listFile, []byte(out), 0644); err != nil {
+		return err
+	}
+	if err := dropFromSidecars(listFile, dir); err != nil {
 		return err
 	}
 	if err := os.RemoveAll(filepath.Join(accountsDir, dir)); err != nil {
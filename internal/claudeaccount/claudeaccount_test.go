@@ -234,3 +234,117 @@ func TestResolveDir_existing_vs_missing_vs_default(t *testing.T) {
 		t.Fatalf("missing dir should resolve empty, got %q", got)
 	}
 }
+
+func TestParsePlan_presets_prices_and_errors(t *testing.T) {
+	for spec, want := range map[string]Plan{
+		"max-20x":    {Name: "max-20x", MonthlyUSD: 200},
+		"Pro":        {Name: "pro", MonthlyUSD: 20},
+		"max-5x $90": {Name: "max-5x", MonthlyUSD: 90},
+		"team, 30":   {Name: "team", MonthlyUSD: 30},
+		"$150":       {MonthlyUSD: 150},
+		"":           {},
+	} {
+		got, err := ParsePlan(spec)
+		if err != nil || got != want {
+			t.Errorf("ParsePlan(%q) = %+v, %v; want %+v", spec, got, err, want)
+		}
+	}
+	for _, bad := range []string{"enterprise", "pro max", "$-5"} {
+		if _, err := ParsePlan(bad); err == nil {
+			t.Errorf("ParsePlan(%q) should fail", bad)
+		}
+	}
+}
+
+func TestSavePlan_round_trips_and_zero_removes(t *testing.T) {
+	path := PlansFile(filepath.Join(t.TempDir(), "claude-accounts.list"))
+	if filepath.Base(path) != "claude-accounts.plans" {
+		t.Fatalf("PlansFile = %q", path)
+	}
+	if err := SavePlan(path, "work", Plan{Name: "max-20x", MonthlyUSD: 200}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SavePlan(path, "default", Plan{Name: "pro", MonthlyUSD: 20}); err != nil {
+		t.Fatal(err)
+	}
+	plans, err := LoadPlans(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plans) != 2 || plans["work"].MonthlyUSD != 200 || plans["default"].String() != "pro $20" {
+		t.Fatalf("plans = %+v", plans)
+	}
+	if err := SavePlan(path, "work", Plan{}); err != nil {
+		t.Fatal(err)
+	}
+	plans, _ = LoadPlans(path)
+	if _, ok := plans["work"]; ok || len(plans) != 1 {
+		t.Fatalf("zero plan should remove work: %+v", plans)
+	}
+}
+
+func TestParseRotation_specs_and_errors(t *testing.T) {
+	for spec, want := range map[string]Rotation{
+		"priority=9":            {Priority: 9},
+		"weight=2, priority=-1": {Priority: -1, Weight: 2},
+		"":                      {},
+	} {
+		got, err := ParseRotation(spec)
+		if err != nil || got != want {
+			t.Errorf("ParseRotation(%q) = %+v, %v; want %+v", spec, got, err, want)
+		}
+	}
+	for _, bad := range []string{"9", "weight=0", "priority=high", "tier=1"} {
+		if _, err := ParseRotation(bad); err == nil {
+			t.Errorf("ParseRotation(%q) should fail", bad)
+		}
+	}
+}
+
+func TestSaveRotation_sidecar_survives_rename_and_clears_on_remove(t *testing.T) {
+	dir := t.TempDir()
+	accountsDir := filepath.Join(dir, "claude-accounts")
+	list := filepath.Join(dir, "claude-accounts.list")
+	rotFile := RotationFile(list)
+	work, _ := Add(list, accountsDir, "Work")
+	personal, _ := Add(list, accountsDir, "Personal")
+
+	if err := SaveRotation(rotFile, personal, Rotation{Priority: 9, Weight: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveRotation(rotFile, work, Rotation{Priority: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(list); string(data) != "Work:work\nPersonal:personal\n" {
+		t.Fatalf("list = %q, want plain label:dir lines", data)
+	}
+	// A rename keeps the rotation; removing a login drops its sidecar line.
+	if err := Rename(list, personal, "Mine"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Remove(list, accountsDir, filepath.Join(dir, "claude-account"), work); err != nil {
+		t.Fatal(err)
+	}
+	rots, err := LoadRotations(rotFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rots) != 1 || rots[personal] != (Rotation{Priority: 9, Weight: 2}) {
+		t.Fatalf("rotations after rename/remove: %+v", rots)
+	}
+	// The default rotation removes the line.
+	if err := SaveRotation(rotFile, personal, Rotation{}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(rotFile); string(data) != "" {
+		t.Errorf("rotation sidecar = %q, want empty", data)
+	}
+}
+
+// A field after the dir is not part of it.
+func TestParseLine_ignores_a_trailing_field(t *testing.T) {
+	a, ok := ParseLine("Work:work:priority=1")
+	if !ok || a != (Account{Label: "Work", Dir: "work"}) {
+		t.Errorf("ParseLine = %+v, %v; want Work:work", a, ok)
+	}
+}
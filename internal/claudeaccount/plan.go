@@ -0,0 +1,88 @@
+package claudeaccount
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Plan is the subscription a login is paid for: a name and its flat monthly
+// price in USD. The zero Plan means none is recorded.
+type Plan struct {
+	Name       string
+	MonthlyUSD float64
+}
+
+// IsZero reports whether no plan is recorded.
+func (p Plan) IsZero() bool { return p.Name == "" && p.MonthlyUSD == 0 }
+
+// String renders the plan the way ParsePlan reads it, e.g. "max-20x $200".
+func (p Plan) String() string {
+	if p.IsZero() {
+		return ""
+	}
+	price := "$" + strconv.FormatFloat(p.MonthlyUSD, 'f', -1, 64)
+	if p.Name == "" {
+		return price
+	}
+	return p.Name + " " + price
+}
+
+// planPrices are the monthly list prices of the Claude plans ParsePlan knows
+// by name, so "max-20x" alone is enough.
+var planPrices = map[string]float64{
+	"pro":          20,
+	"max-5x":       100,
+	"max-20x":      200,
+	"team":         30,
+	"team-premium": 150,
+}
+
+// ParsePlan reads a plan spec: a plan name, a monthly price ("$30" or "30"),
+// or both, separated by spaces or commas. A known name (pro, max-5x, max-20x,
+// team, team-premium) brings its list price unless one is given; any other
+// name needs a price. An empty spec clears the plan.
+func ParsePlan(spec string) (Plan, error) {
+	var p Plan
+	priced := false
+	for _, f := range specFields(spec) {
+		if v, err := strconv.ParseFloat(strings.TrimPrefix(f, "$"), 64); err == nil {
+			if v < 0 {
+				return Plan{}, fmt.Errorf("plan price %q: want a positive amount", f)
+			}
+			p.MonthlyUSD, priced = v, true
+			continue
+		}
+		if p.Name != "" {
+			return Plan{}, fmt.Errorf("plan %q: want one name and a price such as \"team $30\"", spec)
+		}
+		p.Name = strings.ToLower(f)
+	}
+	if p.Name != "" && !priced {
+		price, ok := planPrices[p.Name]
+		if !ok {
+			return Plan{}, fmt.Errorf("plan %q: unknown plan, add its monthly price such as \"%s $30\"", p.Name, p.Name)
+		}
+		p.MonthlyUSD = price
+	}
+	return p, nil
+}
+
+// PlansFile returns the plans sidecar kept next to an accounts list
+// (claude-accounts.list → claude-accounts.plans). The Default login's plan is
+// keyed "default".
+func PlansFile(listFile string) string {
+	return sidecarFile(listFile, "plans")
+}
+
+// LoadPlans reads the plans sidecar into a map keyed by account dir. A missing
+// file yields an empty map; lines whose spec does not parse are skipped.
+func LoadPlans(path string) (map[string]Plan, error) {
+	return loadSidecar(path, ParsePlan)
+}
+
+// SavePlan sets (or, for the zero Plan, removes) one account's plan in the
+// sidecar, keeping every other account's line.
+func SavePlan(path, dir string, p Plan) error {
+	return saveSidecar(path, dir, p.String())
+}
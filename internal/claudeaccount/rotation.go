@@ -0,0 +1,106 @@
+package claudeaccount
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rotation is a login's place in the account proxy's rotation: its priority
+// tier (lower runs first) and its weight, the share of its tier's traffic it
+// takes relative to the tier's other logins. The zero Rotation is priority 0,
+// weight 1. It is kept in the list's rotation sidecar (see RotationFile).
+type Rotation struct {
+	Priority int
+	Weight   int // 0 reads as 1
+}
+
+// Rotation policy names: how the account proxy picks among the logins in
+// rotation (see proxy.ParsePolicy), chosen in Settings or with --policy.
+const (
+	PolicyDrain         = "drain"
+	PolicyRoundRobin    = "round-robin"
+	PolicyLeastUtilized = "least-utilized"
+	PolicyPriority      = "priority"
+)
+
+// PolicyNames lists the built-in policies in menu order (default first).
+var PolicyNames = []string{PolicyDrain, PolicyRoundRobin, PolicyLeastUtilized, PolicyPriority}
+
+// Rotation spec keys, as written in the rotation sidecar and typed in the Login panel.
+const (
+	rotationKeyPriority = "priority"
+	rotationKeyWeight   = "weight"
+)
+
+// EffectiveWeight returns the weight with the unset zero read as 1.
+func (r Rotation) EffectiveWeight() int {
+	if r.Weight < 1 {
+		return 1
+	}
+	return r.Weight
+}
+
+// IsZero reports whether the rotation is the default (priority 0, weight 1).
+func (r Rotation) IsZero() bool { return r.Priority == 0 && r.EffectiveWeight() == 1 }
+
+// String renders the rotation in the spec form ParseRotation reads, e.g.
+// "priority=9,weight=2"; "" for the default.
+func (r Rotation) String() string {
+	var parts []string
+	if r.Priority != 0 {
+		parts = append(parts, rotationKeyPriority+"="+strconv.Itoa(r.Priority))
+	}
+	if w := r.EffectiveWeight(); w != 1 {
+		parts = append(parts, rotationKeyWeight+"="+strconv.Itoa(w))
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseRotation reads a rotation spec: comma- or space-separated key=value
+// pairs where priority takes any integer (lower runs first) and weight a
+// positive one. An empty spec restores the default.
+func ParseRotation(spec string) (Rotation, error) {
+	var r Rotation
+	err := eachSpecSetting(spec, "rotation", func(key, val string) error {
+		n, err := strconv.Atoi(val)
+		switch key {
+		case rotationKeyPriority:
+			if err != nil {
+				return fmt.Errorf("rotation priority: want a whole number, got %q", val)
+			}
+			r.Priority = n
+		case rotationKeyWeight:
+			if err != nil || n < 1 {
+				return fmt.Errorf("rotation weight: want a whole number of 1 or more, got %q", val)
+			}
+			r.Weight = n
+		default:
+			return fmt.Errorf("unknown rotation setting %q (want %s or %s)", key, rotationKeyPriority, rotationKeyWeight)
+		}
+		return nil
+	})
+	if err != nil {
+		return Rotation{}, err
+	}
+	return r, nil
+}
+
+// RotationFile returns the rotation sidecar kept next to an accounts list
+// (claude-accounts.list → claude-accounts.rotation).
+func RotationFile(listFile string) string {
+	return sidecarFile(listFile, "rotation")
+}
+
+// LoadRotations reads the rotation sidecar into a map keyed by account dir. A
+// missing file yields an empty map; lines whose spec does not parse are
+// skipped, leaving that login at the default rotation.
+func LoadRotations(path string) (map[string]Rotation, error) {
+	return loadSidecar(path, ParseRotation)
+}
+
+// SaveRotation sets (or, for the default Rotation, removes) one account's
+// rotation in the sidecar, keeping every other account's line.
+func SaveRotation(path, dir string, r Rotation) error {
+	return saveSidecar(path, dir, r.String())
+}
@@ -0,0 +1,128 @@
+package claudeaccount
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jackuait/wisp-deck/internal/util"
+)
+
+// Per-login settings live in sidecars next to the accounts list, one per kind
+// (claude-accounts.list → claude-accounts.caps, .plans, .rotation), each holding
+// dir:spec lines keyed by account dir. The Default login is keyed "default".
+
+// sidecarFile returns the sidecar with extension ext next to listFile.
+func sidecarFile(listFile, ext string) string {
+	return strings.TrimSuffix(listFile, ".list") + "." + ext
+}
+
+// readSidecar reads a sidecar's dir:spec lines ("#" comments and blanks
+// skipped) into a map of raw specs keyed by account dir. A missing file yields
+// an empty map.
+func readSidecar(path string) (map[string]string, error) {
+	specs := map[string]string{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return specs, nil
+		}
+		return nil, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		dir, spec, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		specs[strings.TrimSpace(dir)] = strings.TrimSpace(spec)
+	}
+	return specs, nil
+}
+
+// loadSidecar reads a sidecar and parses each spec with parse, skipping the
+// lines whose spec does not parse or parses to the zero setting.
+func loadSidecar[T interface{ IsZero() bool }](path string, parse func(string) (T, error)) (map[string]T, error) {
+	specs, err := readSidecar(path)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]T, len(specs))
+	for dir, spec := range specs {
+		v, err := parse(spec)
+		if err != nil || v.IsZero() {
+			continue
+		}
+		out[dir] = v
+	}
+	return out, nil
+}
+
+// saveSidecar sets one account's spec in a sidecar, or removes its line when
+// spec is empty, keeping every other account's line. The write is atomic so a
+// running proxy polling the sidecar never reads a half-written file.
+func saveSidecar(path, dir, spec string) error {
+	specs, err := readSidecar(path)
+	if err != nil {
+		return err
+	}
+	if spec == "" {
+		delete(specs, dir)
+	} else {
+		specs[dir] = spec
+	}
+	dirs := make([]string, 0, len(specs))
+	for d := range specs {
+		dirs = append(dirs, d)
+	}
+	sort.Strings(dirs)
+	var b strings.Builder
+	for _, d := range dirs {
+		fmt.Fprintf(&b, "%s:%s\n", d, specs[d])
+	}
+	return util.WriteFileAtomic(path, []byte(b.String()), 0o644)
+}
+
+// sidecarKinds lists the extension of every per-login settings sidecar.
+var sidecarKinds = []string{"caps", "plans", "rotation"}
+
+// dropFromSidecars removes dir's line from every settings sidecar next to
+// listFile that exists.
+func dropFromSidecars(listFile, dir string) error {
+	for _, ext := range sidecarKinds {
+		path := sidecarFile(listFile, ext)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if err := saveSidecar(path, dir, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// specFields splits a settings spec into its comma-, space- or tab-separated
+// fields, the form every per-login setting is typed and stored in.
+func specFields(spec string) []string {
+	return strings.FieldsFunc(spec, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+}
+
+// eachSpecSetting calls set with every key=value field of a settings spec, the
+// key lowercased and both sides trimmed, stopping at the first error. noun
+// names the setting kind in the error for a field without "=".
+func eachSpecSetting(spec, noun string, set func(key, val string) error) error {
+	for _, f := range specFields(spec) {
+		key, val, ok := strings.Cut(f, "=")
+		if !ok {
+			return fmt.Errorf("%s %q: want key=value", noun, f)
+		}
+		if err := set(strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(val)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
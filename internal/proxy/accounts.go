@@ -13,6 +13,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/jackuait/wisp-deck/internal/claudeaccount"
 )
 
 // Account is one Claude login in the rotation pool, sourced from a
@@ -22,7 +24,10 @@ type Account struct {
 	Dir          string // directory name under the accounts dir
 	AccessToken  string
 	RefreshToken string
-	ExpiresAt    int64 // ms epoch; 0 if unknown
+	ExpiresAt    int64              // ms epoch; 0 if unknown
+	Priority     int                // rotation tier for PriorityPolicy; lower runs first
+	Weight       int                // share of its tier's traffic under PriorityPolicy; 0 reads as 1
+	Caps         claudeaccount.Caps // per-account usage limits; zero when none
 }
 
 // credentialsFile mirrors the Claude Code ~/.claude/.credentials.json shape,
@@ -42,8 +47,19 @@ type oauthCreds struct {
 // LoadAccounts reads the accounts list (label:dir lines, "#" comments and blanks
 // skipped) and loads each account's credentials from
 // <accountsDir>/<dir>/.credentials.json. Accounts whose credentials are missing
-// or unreadable are skipped rather than failing the whole load.
+// or unreadable are skipped rather than failing the whole load. Each account's
+// Priority and Weight come from the list's rotation sidecar
+// (claudeaccount.RotationFile) and its Caps from the caps sidecar
+// (claudeaccount.CapsFile), when present.
 func LoadAccounts(accountsDir, listFile string) ([]Account, error) {
+	caps, err := claudeaccount.LoadCaps(claudeaccount.CapsFile(listFile))
+	if err != nil {
+		return nil, err
+	}
+	rots, err := claudeaccount.LoadRotations(claudeaccount.RotationFile(listFile))
+	if err != nil {
+		return nil, err
+	}
 	f, err := os.Open(listFile)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -56,15 +72,11 @@ func LoadAccounts(accountsDir, listFile string) ([]Account, error) {
 	var accounts []Account
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		label, dir, ok := strings.Cut(line, ":")
+		entry, ok := claudeaccount.ParseLine(scanner.Text())
 		if !ok {
 			continue
 		}
-		label, dir = strings.TrimSpace(label), strings.TrimSpace(dir)
+		label, dir := strings.TrimSpace(entry.Label), strings.TrimSpace(entry.Dir)
 		if label == "" || dir == "" {
 			continue
 		}
@@ -78,6 +90,9 @@ func LoadAccounts(accountsDir, listFile string) ([]Account, error) {
 			AccessToken:  creds.AccessToken,
 			RefreshToken: creds.RefreshToken,
 			ExpiresAt:    creds.ExpiresAt,
+			Priority:     rots[dir].Priority,
+			Weight:       rots[dir].EffectiveWeight(),
+			Caps:         caps[dir],
 		})
 	}
 	if err := scanner.Err(); err != nil {
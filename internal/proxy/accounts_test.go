@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/jackuait/wisp-deck/internal/claudeaccount"
 )
 
 // writeCreds writes a .credentials.json for an account dir in the Claude Code
@@ -103,3 +105,30 @@ func TestLoadAccounts_emptyListReturnsNoAccounts(t *testing.T) {
 		t.Fatalf("got %d, want 0", len(accts))
 	}
 }
+
+func TestLoadAccounts_readsRotationFromSidecar(t *testing.T) {
+	root := t.TempDir()
+	accountsDir := filepath.Join(root, "claude-accounts")
+	listFile := filepath.Join(root, "claude-accounts.list")
+
+	writeCreds(t, accountsDir, "work", "tok-work", "", 0)
+	writeCreds(t, accountsDir, "personal", "tok-personal", "", 0)
+	writeCreds(t, accountsDir, "team", "tok-team", "", 0)
+	writeList(t, listFile, "Work:work\nPersonal:personal\nTeam:team\n")
+	if err := os.WriteFile(claudeaccount.RotationFile(listFile), []byte("work:weight=3\npersonal:priority=9\nteam:bogus\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	accts, err := LoadAccounts(accountsDir, listFile)
+	if err != nil {
+		t.Fatalf("LoadAccounts: %v", err)
+	}
+	if len(accts) != 3 || accts[1].Dir != "personal" {
+		t.Fatalf("accounts = %+v", accts)
+	}
+	for i, want := range []struct{ prio, weight int }{{0, 3}, {9, 1}, {0, 1}} {
+		if accts[i].Priority != want.prio || accts[i].Weight != want.weight {
+			t.Errorf("%s = priority %d weight %d, want %d/%d", accts[i].Dir, accts[i].Priority, accts[i].Weight, want.prio, want.weight)
+		}
+	}
+}
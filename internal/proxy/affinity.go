@@ -0,0 +1,122 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// affinityTTL is how long an idle conversation stays pinned to its account.
+// Upstream prompt caches live at most an hour, so past that there is nothing
+// left to preserve and the pin is dropped.
+const affinityTTL = time.Hour
+
+// sessionHeader is the per-conversation id newer Claude Code builds send on
+// every request; preferred over the body metadata when present.
+const sessionHeader = "X-Claude-Code-Session-Id"
+
+// pin records which account a conversation is bound to.
+type pin struct {
+	idx  int
+	last time.Time
+}
+
+// conversationKey derives a stable per-conversation key from a request so its
+// turns can stay on one account (keeping the upstream prompt cache warm). It
+// uses the session header when present, else the session in the Messages
+// body's metadata.user_id — Claude Code encodes it there as
+// "user_<hash>_account_<uuid>_session_<uuid>". A user_id without a session
+// names the user, not the conversation, so it is no key: pinning it would hold
+// every one of that user's conversations on one account. Returns "" when the
+// request carries no conversation identity.
+func conversationKey(h http.Header, body []byte) string {
+	if v := strings.TrimSpace(h.Get(sessionHeader)); v != "" {
+		return "session:" + v
+	}
+	if _, session, ok := strings.Cut(metadataUserID(body), "_session_"); ok && session != "" {
+		return "session:" + session
+	}
+	return ""
+}
+
+// metadataUserID returns a Messages body's metadata.user_id, or "" when it
+// has none. Only the metadata object is decoded; the other top-level fields
+// (the messages, system prompt and tools that make up nearly all of a body)
+// are stepped over without being built into values.
+func metadataUserID(body []byte) string {
+	if len(body) == 0 || body[0] != '{' {
+		return ""
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if _, err := dec.Token(); err != nil { // the opening '{'
+		return ""
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return ""
+		}
+		if key, _ := tok.(string); key == "metadata" {
+			var md struct {
+				UserID string `json:"user_id"`
+			}
+			if dec.Decode(&md) != nil {
+				return ""
+			}
+			return md.UserID
+		}
+		// Decoding into an empty struct skips the value whatever its shape; a
+		// type mismatch is reported only after the value is consumed.
+		var skip struct{}
+		if err := dec.Decode(&skip); err != nil {
+			if _, mismatch := err.(*json.UnmarshalTypeError); !mismatch {
+				return ""
+			}
+		}
+	}
+	return ""
+}
+
+// GetAccountFor selects the account for a request belonging to conversation
+// key. A conversation stays on the account it is pinned to for as long as that
+// account is available (not throttled, errored or over the threshold) and not
+// excluded; otherwise — and for new conversations — the policy picks as in
+// GetActiveAccount and the conversation is (re)pinned to the result. An empty
+// key has no affinity.
+func (m *Manager) GetAccountFor(key string, exclude map[int]bool, now time.Time) (int, bool) {
+	if key == "" {
+		return m.GetActiveAccount(exclude, now)
+	}
+	m.mu.Lock()
+	m.pruneAffinity(now)
+	if p, ok := m.affinity[key]; ok && p.idx < len(m.accounts) && !exclude[p.idx] && m.isAvailable(p.idx, now) {
+		p.last = now
+		m.affinity[key] = p
+		m.mu.Unlock()
+		return p.idx, true
+	}
+	m.mu.Unlock()
+
+	idx, ok := m.GetActiveAccount(exclude, now)
+	if !ok {
+		return -1, false
+	}
+	m.mu.Lock()
+	if m.affinity == nil {
+		m.affinity = map[string]pin{}
+	}
+	m.affinity[key] = pin{idx: idx, last: now}
+	m.mu.Unlock()
+	return idx, true
+}
+
+// pruneAffinity drops pins idle longer than affinityTTL. Caller holds m.mu.
+func (m *Manager) pruneAffinity(now time.Time) {
+	for k, p := range m.affinity {
+		if now.Sub(p.last) > affinityTTL {
+			delete(m.affinity, k)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package proxy
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestConversationKey_sources(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		body   string
+		want   string
+	}{
+		{"session header wins", "hdr-1", `{"metadata":{"user_id":"user_x_account_y_session_abc"}}`, "session:hdr-1"},
+		{"session suffix of user_id", "", `{"metadata":{"user_id":"user_x_account_y_session_abc"}}`, "session:abc"},
+		{"metadata after the messages", "", `{"messages":[{"role":"user","content":"{\"metadata\":1}"}],"max_tokens":8,"metadata":{"user_id":"user_x_session_def"}}`, "session:def"},
+		{"plain user_id is no conversation", "", `{"metadata":{"user_id":"someone"}}`, ""},
+		{"no metadata", "", `{"model":"claude"}`, ""},
+		{"not json", "", `garbage`, ""},
+		{"empty", "", ``, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := http.Header{}
+			if c.header != "" {
+				h.Set(sessionHeader, c.header)
+			}
+			if got := conversationKey(h, []byte(c.body)); got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestGetAccountFor_pinsUntilUnavailable(t *testing.T) {
+	m := NewManager(testAccounts(2), 0.98)
+	now := time.Now()
+	if idx, _ := m.GetAccountFor("c1", nil, now); idx != 0 {
+		t.Fatalf("new conversation should start on the best account (0), got %d", idx)
+	}
+	// The pool moves on (e.g. another conversation failed over) …
+	m.MarkThrottled(0, now.Add(time.Minute))
+	if idx, _ := m.GetAccountFor("c2", nil, now); idx != 1 {
+		t.Fatalf("c2 should go to 1, got %d", idx)
+	}
+	// … c1's account is throttled, so it is re-pinned to 1 …
+	if idx, _ := m.GetAccountFor("c1", nil, now); idx != 1 {
+		t.Fatalf("c1 should fail over to 1, got %d", idx)
+	}
+	// … and stays there after 0 recovers.
+	if idx, _ := m.GetAccountFor("c1", nil, now.Add(2*time.Minute)); idx != 1 {
+		t.Errorf("c1 should stay pinned to 1 after 0 recovers, got %d", idx)
+	}
+}
+
+func TestGetAccountFor_idlePinExpires(t *testing.T) {
+	m := NewManager(testAccounts(2), 0.98)
+	m.SetPolicy(RoundRobinPolicy{})
+	now := time.Now()
+	first, _ := m.GetAccountFor("c1", nil, now)
+	if again, _ := m.GetAccountFor("c1", nil, now.Add(time.Minute)); again != first {
+		t.Fatalf("pinned conversation moved from %d to %d", first, again)
+	}
+	if later, _ := m.GetAccountFor("c1", nil, now.Add(2*affinityTTL)); later == first {
+		t.Errorf("idle pin should expire and let round-robin move on, still on %d", later)
+	}
+}
@@ -0,0 +1,94 @@
+package proxy
+
+import (
+	"fmt"
+	"time"
+)
+
+// dayKey is the local calendar day the daily output cap counts against.
+func dayKey(t time.Time) string { return t.Local().Format("2006-01-02") }
+
+// nextLocalMidnight is when the daily output cap for t's day lifts.
+func nextLocalMidnight(t time.Time) time.Time {
+	l := t.Local()
+	return time.Date(l.Year(), l.Month(), l.Day()+1, 0, 0, 0, 0, l.Location())
+}
+
+// capReason explains why account idx is at one of its caps ("" when it is
+// not). Caller holds m.mu.
+func (m *Manager) capReason(idx int, now time.Time) string {
+	a := m.accounts[idx]
+	c := a.Caps
+	switch {
+	case c.Max5h > 0 && a.q.u5h >= c.Max5h:
+		return fmt.Sprintf("5h ≥ %.0f%%", c.Max5h*100)
+	case c.Max7d > 0 && a.q.u7d >= c.Max7d:
+		return fmt.Sprintf("7d ≥ %.0f%%", c.Max7d*100)
+	case c.DailyOutput > 0 && a.day == dayKey(now) && a.dayOutput >= c.DailyOutput:
+		return fmt.Sprintf("%d output tokens today", c.DailyOutput)
+	}
+	return ""
+}
+
+// capLifts returns when account idx's binding caps lift (zero when unknown).
+// Caller holds m.mu.
+func (m *Manager) capLifts(idx int, now time.Time) time.Time {
+	a := m.accounts[idx]
+	c := a.Caps
+	var lift time.Time
+	later := func(t time.Time) {
+		if t.After(lift) {
+			lift = t
+		}
+	}
+	if c.Max5h > 0 && a.q.u5h >= c.Max5h {
+		later(a.q.u5hReset)
+	}
+	if c.Max7d > 0 && a.q.u7d >= c.Max7d {
+		later(a.q.u7dReset)
+	}
+	if c.DailyOutput > 0 && a.day == dayKey(now) && a.dayOutput >= c.DailyOutput {
+		later(nextLocalMidnight(now))
+	}
+	return lift
+}
+
+// RecordOutput adds output tokens served by account idx to its daily total,
+// which starts over each local day.
+func (m *Manager) RecordOutput(idx int, tokens int64, now time.Time) {
+	defer m.persist()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if idx < 0 || idx >= len(m.accounts) || tokens <= 0 {
+		return
+	}
+	a := m.accounts[idx]
+	if day := dayKey(now); a.day != day {
+		a.day, a.dayOutput = day, 0
+	}
+	a.dayOutput += tokens
+}
+
+// AllCapped reports whether every account in the pool is held back by one of
+// its caps, and if so the earliest time one of them lifts (zero if unknown).
+// An empty pool is not capped.
+func (m *Manager) AllCapped(now time.Time) (bool, time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var earliest time.Time
+	live := 0
+	for i, a := range m.accounts {
+		if a.removed {
+			continue
+		}
+		live++
+		m.clearExpired(i, now)
+		if m.capReason(i, now) == "" {
+			return false, time.Time{}
+		}
+		if t := m.capLifts(i, now); !t.IsZero() && (earliest.IsZero() || t.Before(earliest)) {
+			earliest = t
+		}
+	}
+	return live > 0, earliest
+}
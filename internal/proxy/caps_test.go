@@ -0,0 +1,100 @@
+package proxy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackuait/wisp-deck/internal/claudeaccount"
+)
+
+func TestLoadAccounts_readsCapsSidecar(t *testing.T) {
+	dir := t.TempDir()
+	list := filepath.Join(dir, "claude-accounts.list")
+	writeCreds(t, dir, "work", "tok-W", "ref-W", 0)
+	writeList(t, list, "Work:work\n")
+	os.WriteFile(claudeaccount.CapsFile(list), []byte("work:7d=75\n"), 0o644)
+
+	accts, err := LoadAccounts(dir, list)
+	if err != nil || len(accts) != 1 {
+		t.Fatalf("LoadAccounts = %+v, %v", accts, err)
+	}
+	if accts[0].Caps.Max7d != 0.75 {
+		t.Errorf("Caps = %+v, want 7d=75%%", accts[0].Caps)
+	}
+}
+
+func TestCaps_weeklyCapTakesAccountOutOfRotation(t *testing.T) {
+	accts := testAccounts(2)
+	accts[0].Caps = claudeaccount.Caps{Max7d: 0.8}
+	m := NewManager(accts, 0.98)
+	now := time.Now()
+	m.UpdateQuota(0, hdr(
+		"anthropic-ratelimit-unified-7d-utilization", "0.85",
+		"anthropic-ratelimit-unified-7d-reset", itoa(now.Add(48*time.Hour).Unix()),
+	))
+
+	idx, ok := m.GetActiveAccount(nil, now)
+	if !ok || idx != 1 {
+		t.Errorf("GetActiveAccount = (%d, %v), want (1, true): A is past its 80%% weekly cap", idx, ok)
+	}
+	if capped, _ := m.AllCapped(now); capped {
+		t.Error("AllCapped should be false while B is uncapped")
+	}
+	if st := m.Status(now); st.Accounts[0].Capped == "" || st.Accounts[1].Capped != "" {
+		t.Errorf("Status capped = %q / %q, want only A capped", st.Accounts[0].Capped, st.Accounts[1].Capped)
+	}
+}
+
+func TestCaps_dailyOutputCapResetsNextDay(t *testing.T) {
+	accts := testAccounts(1)
+	accts[0].Caps = claudeaccount.Caps{DailyOutput: 1000}
+	m := NewManager(accts, 0.98)
+	day := time.Date(2026, 5, 1, 15, 0, 0, 0, time.Local)
+
+	m.RecordOutput(0, 600, day)
+	if !m.isAvailable(0, day) {
+		t.Fatal("600 of 1000 output tokens should leave the account available")
+	}
+	m.RecordOutput(0, 500, day)
+	capped, lifts := m.AllCapped(day)
+	if !capped {
+		t.Fatal("1100 of 1000 output tokens should cap the only account")
+	}
+	if want := time.Date(2026, 5, 2, 0, 0, 0, 0, time.Local); !lifts.Equal(want) {
+		t.Errorf("cap lifts at %v, want local midnight %v", lifts, want)
+	}
+	if next := day.Add(12 * time.Hour); !m.isAvailable(0, next) {
+		t.Error("the daily cap should lift on the next local day")
+	}
+}
+
+func TestCaps_dailyOutputSurvivesRestartSameDay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), StateFile)
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.Local)
+	accts := testAccounts(1)
+	accts[0].Caps = claudeaccount.Caps{DailyOutput: 1000}
+
+	m := NewManager(accts, 0.98)
+	if err := m.LoadState(path, now); err != nil {
+		t.Fatal(err)
+	}
+	m.RecordOutput(0, 1200, now)
+	m.FlushState()
+
+	m2 := NewManager(accts, 0.98)
+	if err := m2.LoadState(path, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if m2.isAvailable(0, now.Add(time.Hour)) {
+		t.Error("today's output should be restored, keeping the account capped")
+	}
+	m3 := NewManager(accts, 0.98)
+	if err := m3.LoadState(path, now.Add(24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if !m3.isAvailable(0, now.Add(24*time.Hour)) {
+		t.Error("yesterday's output should not be restored")
+	}
+}
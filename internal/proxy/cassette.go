@@ -0,0 +1,367 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A cassette is one recorded upstream exchange — request, response status and
+// headers (rate-limit headers included), and the body as the chunks it arrived
+// in with their timing — stored as <dir>/NNNN.json. `proxy --record` writes
+// them from live traffic; `proxy --replay` serves them back from a local
+// stand-in upstream, so rotation bugs can be reproduced offline and the Server
+// can be integration-tested against realistic traffic.
+
+// Cassette is one recorded exchange.
+type Cassette struct {
+	Seq      int              `json:"seq"` // order the request was sent in
+	Time     time.Time        `json:"time"`
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest is the recorded request. Credentials are redacted.
+type CassetteRequest struct {
+	Method string      `json:"method"`
+	Host   string      `json:"host"`
+	Path   string      `json:"path"` // request URI, including any query
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// CassetteResponse is the recorded response. Error is set instead of the rest
+// when the exchange failed at the transport level.
+type CassetteResponse struct {
+	Status int             `json:"status,omitempty"`
+	Header http.Header     `json:"header,omitempty"`
+	Chunks []CassetteChunk `json:"chunks,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// CassetteChunk is one read of the response body, AtMS milliseconds after the
+// response headers arrived.
+type CassetteChunk struct {
+	AtMS int64  `json:"atMs"`
+	Data string `json:"data"`
+}
+
+// redactedHeaders never reach a cassette: they carry the account's OAuth token
+// or the local proxy key.
+var redactedHeaders = map[string]bool{
+	"authorization":       true,
+	"x-api-key":           true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"set-cookie":          true,
+}
+
+// redactedFields are JSON body fields blanked in OAuth token exchanges.
+var redactedFields = []string{"access_token", "refresh_token", "id_token"}
+
+const redacted = "REDACTED"
+
+// redactHeader copies h with credential headers replaced by a placeholder, so
+// a cassette still shows that one was sent.
+func redactHeader(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for k, vv := range h {
+		if redactedHeaders[strings.ToLower(k)] {
+			out[k] = []string{redacted}
+			continue
+		}
+		out[k] = append([]string(nil), vv...)
+	}
+	return out
+}
+
+// redactBody blanks token fields in an OAuth token request or response body;
+// other bodies are returned unchanged.
+func redactBody(path string, body []byte) string {
+	if !strings.HasSuffix(strings.SplitN(path, "?", 2)[0], "/oauth/token") {
+		return string(body)
+	}
+	var doc map[string]any
+	if json.Unmarshal(body, &doc) != nil {
+		return string(body)
+	}
+	for _, f := range redactedFields {
+		if _, ok := doc[f]; ok {
+			doc[f] = redacted
+		}
+	}
+	out, _ := json.Marshal(doc)
+	return string(out)
+}
+
+// Recorder is an http.RoundTripper that forwards to base and writes every
+// exchange to a cassette in dir.
+type Recorder struct {
+	dir  string
+	base http.RoundTripper
+	now  func() time.Time
+
+	mu  sync.Mutex
+	seq int
+}
+
+// NewRecorder returns a Recorder writing to dir (created if needed). Numbering
+// continues after any cassettes already there, so a session can be recorded in
+// several runs.
+func NewRecorder(dir string, base http.RoundTripper) (*Recorder, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	existing, err := LoadCassettes(dir)
+	if err != nil {
+		return nil, err
+	}
+	r := &Recorder{dir: dir, base: base, now: time.Now}
+	if n := len(existing); n > 0 {
+		r.seq = existing[n-1].Seq
+	}
+	return r, nil
+}
+
+// RoundTrip forwards req and records the exchange once the response body has
+// been read to the end or closed.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	r.mu.Lock()
+	r.seq++
+	c := &Cassette{
+		Seq:  r.seq,
+		Time: r.now().UTC(),
+		Request: CassetteRequest{
+			Method: req.Method,
+			Host:   req.URL.Host,
+			Path:   req.URL.RequestURI(),
+			Header: redactHeader(req.Header),
+			Body:   redactBody(req.URL.Path, body),
+		},
+	}
+	r.mu.Unlock()
+
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		c.Response.Error = err.Error()
+		r.save(c)
+		return nil, err
+	}
+	c.Response.Status = resp.StatusCode
+	c.Response.Header = redactHeader(resp.Header)
+	resp.Body = &recordingBody{rc: resp.Body, start: r.now(), now: r.now, path: req.URL.Path, c: c, save: r.save}
+	return resp, nil
+}
+
+// save writes c to its numbered file. A failed write is logged rather than
+// failing the exchange it recorded.
+func (r *Recorder) save(c *Cassette) {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err == nil {
+		err = writeAtomic(filepath.Join(r.dir, fmt.Sprintf("%04d.json", c.Seq)), append(data, '\n'), 0o600)
+	}
+	if err != nil {
+		log.Printf("[wisp-deck-proxy] could not record cassette %d: %v", c.Seq, err)
+	}
+}
+
+// recordingBody captures each read of a response body with its timing and
+// saves the cassette once, at EOF or Close.
+type recordingBody struct {
+	rc    io.ReadCloser
+	start time.Time
+	now   func() time.Time
+	path  string
+	c     *Cassette
+	save  func(*Cassette)
+	once  sync.Once
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.rc.Read(p)
+	if n > 0 {
+		b.c.Response.Chunks = append(b.c.Response.Chunks, CassetteChunk{
+			AtMS: b.now().Sub(b.start).Milliseconds(),
+			Data: string(p[:n]),
+		})
+	}
+	if err != nil {
+		b.flush()
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	b.flush()
+	return b.rc.Close()
+}
+
+func (b *recordingBody) flush() {
+	b.once.Do(func() {
+		if strings.HasSuffix(b.path, "/oauth/token") {
+			var whole strings.Builder
+			for _, ch := range b.c.Response.Chunks {
+				whole.WriteString(ch.Data)
+			}
+			b.c.Response.Chunks = []CassetteChunk{{Data: redactBody(b.path, []byte(whole.String()))}}
+		}
+		b.save(b.c)
+	})
+}
+
+// LoadCassettes reads every NNNN.json cassette in dir, ordered by Seq. A
+// missing dir yields none.
+func LoadCassettes(dir string) ([]Cassette, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []Cassette
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		if _, err := strconv.Atoi(strings.TrimSuffix(name, ".json")); err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		var c Cassette
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("cassette %s: %w", name, err)
+		}
+		out = append(out, c)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Seq < out[j].Seq })
+	return out, nil
+}
+
+// Replayer is a stand-in upstream that answers each request with the next
+// unplayed cassette recorded for the same method and path, in recording order.
+// Account tokens are ignored, so a replay exercises whatever rotation the
+// Server under test decides on.
+type Replayer struct {
+	sleep func(time.Duration) // paces chunks; nil replays without delays
+
+	mu        sync.Mutex
+	cassettes []Cassette
+	played    []bool
+}
+
+// NewReplayer loads the cassettes in dir. sleep paces the body chunks by their
+// recorded timing (time.Sleep reproduces it in real time); nil plays every
+// chunk immediately, which keeps tests fast and deterministic.
+func NewReplayer(dir string, sleep func(time.Duration)) (*Replayer, error) {
+	cassettes, err := LoadCassettes(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(cassettes) == 0 {
+		return nil, fmt.Errorf("no cassettes in %s", dir)
+	}
+	return &Replayer{sleep: sleep, cassettes: cassettes, played: make([]bool, len(cassettes))}, nil
+}
+
+// Remaining reports how many cassettes have not been played yet.
+func (p *Replayer) Remaining() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, done := range p.played {
+		if !done {
+			n++
+		}
+	}
+	return n
+}
+
+// next claims the first unplayed cassette matching r.
+func (p *Replayer) next(r *http.Request) (Cassette, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, c := range p.cassettes {
+		if !p.played[i] && c.Request.Method == r.Method && c.Request.Path == r.URL.RequestURI() {
+			p.played[i] = true
+			return c, true
+		}
+	}
+	return Cassette{}, false
+}
+
+func (p *Replayer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	io.Copy(io.Discard, r.Body)
+	c, ok := p.next(r)
+	if !ok {
+		log.Printf("[wisp-deck-proxy] replay: no cassette left for %s %s", r.Method, r.URL.RequestURI())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"type":  "error",
+			"error": map[string]string{"type": "not_found_error", "message": "no cassette left for " + r.Method + " " + r.URL.RequestURI()},
+		})
+		return
+	}
+	if c.Response.Error != "" {
+		// Reproduce a transport failure by dropping the connection.
+		if hj, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hj.Hijack(); err == nil {
+				conn.Close()
+				return
+			}
+		}
+		panic(http.ErrAbortHandler)
+	}
+	for k, vv := range c.Response.Header {
+		if lk := strings.ToLower(k); hopByHopHeaders[lk] || lk == "content-length" || lk == "content-encoding" {
+			continue
+		}
+		for _, v := range vv {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(c.Response.Status)
+	flusher, _ := w.(http.Flusher)
+	var at int64
+	for _, ch := range c.Response.Chunks {
+		if p.sleep != nil && ch.AtMS > at {
+			p.sleep(time.Duration(ch.AtMS-at) * time.Millisecond)
+		}
+		at = ch.AtMS
+		io.WriteString(w, ch.Data)
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if r.Context().Err() != nil {
+			return
+		}
+	}
+}
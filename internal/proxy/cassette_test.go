@@ -0,0 +1,202 @@
+package proxy
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// replayServer runs the cassettes in testdata/cassettes/<name> as the upstream.
+func replayServer(t *testing.T, name string) (*Replayer, *httptest.Server) {
+	t.Helper()
+	rep, err := NewReplayer(filepath.Join("testdata", "cassettes", name), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(rep)
+	t.Cleanup(ts.Close)
+	return rep, ts
+}
+
+func readCassette(t *testing.T, dir string, seq int) Cassette {
+	t.Helper()
+	cs, err := LoadCassettes(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cs {
+		if c.Seq == seq {
+			return c
+		}
+	}
+	t.Fatalf("no cassette %d in %s (have %d)", seq, dir, len(cs))
+	return Cassette{}
+}
+
+func TestRecorder_capturesStreamAndRedactsCredentials(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("anthropic-ratelimit-unified-5h-utilization", "0.4")
+		half := len(sseStream) / 2
+		io.WriteString(w, sseStream[:half])
+		w.(http.Flusher).Flush()
+		io.WriteString(w, sseStream[half:])
+	}))
+	defer upstream.Close()
+
+	dir := t.TempDir()
+	rec, err := NewRecorder(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mgr := NewManager([]Account{{Label: "A", AccessToken: "secret-token"}}, 0.98)
+	srv := NewServer(mgr, "proxy-key", upstream.URL, WithTransport(rec))
+	if got := doRequest(t, srv, "proxy-key", `{"model":"m"}`); got.Body.String() != sseStream {
+		t.Fatalf("client body = %q, want the stream relayed unchanged", got.Body.String())
+	}
+
+	c := readCassette(t, dir, 1)
+	if c.Request.Method != http.MethodPost || c.Request.Path != "/v1/messages" || c.Request.Body != `{"model":"m"}` {
+		t.Errorf("request = %+v", c.Request)
+	}
+	if got := c.Request.Header.Get("Authorization"); got != redacted {
+		t.Errorf("Authorization = %q, want it redacted", got)
+	}
+	raw, _ := os.ReadFile(filepath.Join(dir, "0001.json"))
+	if strings.Contains(string(raw), "secret-token") || strings.Contains(string(raw), "proxy-key") {
+		t.Error("a cassette must never contain the account token or proxy key")
+	}
+	if c.Response.Status != 200 || c.Response.Header.Get("anthropic-ratelimit-unified-5h-utilization") != "0.4" {
+		t.Errorf("response = %d %v, want the rate-limit headers kept", c.Response.Status, c.Response.Header)
+	}
+	var body strings.Builder
+	for _, ch := range c.Response.Chunks {
+		body.WriteString(ch.Data)
+	}
+	if body.String() != sseStream {
+		t.Errorf("recorded stream = %q", body.String())
+	}
+}
+
+func TestRecorder_redactsTokenRefreshes(t *testing.T) {
+	oauth := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"access_token":"new-access","refresh_token":"new-refresh","expires_in":3600}`)
+	}))
+	defer oauth.Close()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer upstream.Close()
+
+	dir := t.TempDir()
+	rec, _ := NewRecorder(dir, nil)
+	mgr := NewManager([]Account{{Label: "A", AccessToken: "old", RefreshToken: "old-refresh", ExpiresAt: 1}}, 0.98)
+	srv := NewServer(mgr, "proxy-key", upstream.URL, WithTransport(rec), WithTokenEndpoint(oauth.URL+"/v1/oauth/token"))
+	doRequest(t, srv, "proxy-key", `{}`)
+
+	raw, _ := os.ReadFile(filepath.Join(dir, "0001.json"))
+	for _, secret := range []string{"old-refresh", "new-access", "new-refresh"} {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("refresh cassette leaks %q:\n%s", secret, raw)
+		}
+	}
+	if c := readCassette(t, dir, 1); c.Request.Path != "/v1/oauth/token" || c.Response.Status != 200 {
+		t.Errorf("first cassette = %+v, want the token refresh", c.Request)
+	}
+}
+
+func TestReplay_reproducesARecordedSession(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, sseStream)
+	}))
+	dir := t.TempDir()
+	rec, _ := NewRecorder(dir, nil)
+	live := NewServer(NewManager([]Account{{Label: "A", AccessToken: "tok-A"}}, 0.98), "proxy-key", upstream.URL, WithTransport(rec))
+	want := doRequest(t, live, "proxy-key", `{}`).Body.String()
+	upstream.Close() // from here on, the network is gone
+
+	rep, err := NewReplayer(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(rep)
+	defer ts.Close()
+	replayed := NewServer(NewManager([]Account{{Label: "A", AccessToken: "tok-A"}}, 0.98), "proxy-key", ts.URL)
+	got := doRequest(t, replayed, "proxy-key", `{}`)
+	if got.Code != 200 || got.Body.String() != want {
+		t.Errorf("replay = %d %q, want the recorded %q", got.Code, got.Body.String(), want)
+	}
+	if rep.Remaining() != 0 {
+		t.Errorf("Remaining = %d, want every cassette played", rep.Remaining())
+	}
+	if again := doRequest(t, replayed, "proxy-key", `{}`); again.Code != http.StatusNotFound {
+		t.Errorf("a request past the end of the tape = %d, want 404", again.Code)
+	}
+}
+
+// The failover-429 session: account A is rejected with a 429, and the same
+// request is served as a stream by account B.
+func TestReplay_failoverOn429(t *testing.T) {
+	rep, ts := replayServer(t, "failover-429")
+	mgr := NewManager([]Account{{Label: "A", Dir: "a", AccessToken: "tok-A"}, {Label: "B", Dir: "b", AccessToken: "tok-B"}}, 0.98)
+	srv := newTestServer(t, mgr, ts.URL)
+
+	got := doConversation(t, srv, "replay")
+	if got.Code != 200 || !strings.Contains(got.Body.String(), `"text":" there"`) {
+		t.Fatalf("client saw %d %q, want B's stream", got.Code, got.Body.String())
+	}
+	if rep.Remaining() != 0 {
+		t.Errorf("Remaining = %d, want both exchanges played", rep.Remaining())
+	}
+	st := mgr.Status(time.Now())
+	a, b := st.Accounts[0], st.Accounts[1]
+	if a.ThrottledUntil.IsZero() || a.Status != "rejected" {
+		t.Errorf("A = %+v, want it throttled after the recorded 429", a)
+	}
+	if !b.Active || b.OutputToday != 24 || b.Util5h != 0.31 {
+		t.Errorf("B = %+v, want it active with the stream's usage and quota learned", b)
+	}
+}
+
+// The refresh-dead session: A's token is expired and its refresh is revoked, so
+// A is retired and B serves the request.
+func TestReplay_deadRefreshRetiresAccount(t *testing.T) {
+	rep, ts := replayServer(t, "refresh-dead")
+	mgr := NewManager([]Account{
+		{Label: "A", Dir: "a", AccessToken: "tok-A", RefreshToken: "ref-A", ExpiresAt: 1},
+		{Label: "B", Dir: "b", AccessToken: "tok-B"},
+	}, 0.98)
+	srv := NewServer(mgr, "proxy-key", ts.URL, WithTokenEndpoint(ts.URL+"/v1/oauth/token"), WithSleep(func(time.Duration) {}))
+
+	if got := doConversation(t, srv, "replay"); got.Code != 200 {
+		t.Fatalf("status = %d, want B to serve the request", got.Code)
+	}
+	if rep.Remaining() != 0 {
+		t.Errorf("Remaining = %d, want the refresh and the message both played", rep.Remaining())
+	}
+	if st := mgr.Status(time.Now()); !st.Accounts[0].Errored || st.Accounts[1].Errored {
+		t.Errorf("errored = %v/%v, want only A retired", st.Accounts[0].Errored, st.Accounts[1].Errored)
+	}
+}
+
+func TestLoadCassettes_ordersBySeqAndSkipsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, c := range []Cassette{{Seq: 10}, {Seq: 2}} {
+		data, _ := json.Marshal(c)
+		os.WriteFile(filepath.Join(dir, itoa(int64(c.Seq))+".json"), data, 0o600)
+	}
+	os.WriteFile(filepath.Join(dir, "notes.json"), []byte("{"), 0o600)
+	cs, err := LoadCassettes(dir)
+	if err != nil || len(cs) != 2 || cs[0].Seq != 2 || cs[1].Seq != 10 {
+		t.Fatalf("LoadCassettes = %+v, %v", cs, err)
+	}
+	rec, err := NewRecorder(dir, nil)
+	if err != nil || rec.seq != 10 {
+		t.Errorf("recorder seq = %d, %v; want numbering to continue after 10", rec.seq, err)
+	}
+}
@@ -0,0 +1,258 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Shared-daemon files, kept in the per-user state dir (the accounts dir's
+// parent). One daemon serves every session so all of them share a single view
+// of each account's quota and a single token refresher.
+const (
+	DaemonFile     = "proxy-daemon.json" // discovery: pid, port, key, CA path
+	DaemonLockFile = "proxy-daemon.lock" // flock held for the daemon's lifetime
+	DaemonLogFile  = "proxy-daemon.log"
+)
+
+// AttachPath is the admin route a launcher POSTs its pid to so the daemon
+// knows the session references it. Answered locally, never forwarded.
+const AttachPath = "/_wisp/attach"
+
+// ErrDaemonRunning is returned by LockDaemon when another daemon holds the lock.
+var ErrDaemonRunning = errors.New("proxy daemon already running")
+
+// DaemonInfo is the discovery record a running daemon publishes. It holds the
+// proxy key, so it is written owner-only.
+type DaemonInfo struct {
+	PID  int    `json:"pid"`
+	Port int    `json:"port"`
+	Key  string `json:"key"`
+	CA   string `json:"ca,omitempty"`
+}
+
+// WriteDaemonInfo atomically publishes the discovery record.
+func WriteDaemonInfo(path string, info DaemonInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, data, 0o600)
+}
+
+// ReadDaemonInfo reads a discovery record. It does not check that the daemon
+// is still alive; callers probe it (FetchStatus) before trusting it.
+func ReadDaemonInfo(path string) (DaemonInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return DaemonInfo{}, err
+	}
+	var info DaemonInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return DaemonInfo{}, err
+	}
+	if info.Port == 0 || info.Key == "" {
+		return DaemonInfo{}, fmt.Errorf("incomplete daemon record %s", path)
+	}
+	return info, nil
+}
+
+// LockDaemon takes the per-user daemon lock without blocking. The returned
+// file must stay open for the daemon's lifetime; the kernel releases the lock
+// when the process exits, so a crashed daemon never leaves a stale lock.
+func LockDaemon(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, ErrDaemonRunning
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// Sessions tracks the launcher processes attached to a shared daemon. A
+// session stops referencing the daemon when its process exits, so a killed
+// wrapper needs no explicit detach. Each pid is kept with its process's start
+// time, so a pid the kernel reuses for an unrelated process does not keep the
+// daemon up.
+type Sessions struct {
+	mu         sync.Mutex
+	pids       map[int]string // pid → its start time when it attached
+	emptySince time.Time
+	started    func(pid int) string
+	alive      func(pid int) bool
+}
+
+// NewSessions returns an empty set whose idle clock starts at now.
+func NewSessions(now time.Time) *Sessions {
+	return &Sessions{pids: map[int]string{}, emptySince: now, started: processStart, alive: processAlive}
+}
+
+// Attach records that pid references the daemon.
+func (s *Sessions) Attach(pid int) {
+	start := s.started(pid)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pids[pid] = start
+	s.emptySince = time.Time{}
+}
+
+// IdleFor drops sessions whose process has exited (or whose pid now names a
+// process started since) and reports how long the daemon has had none; 0
+// while any session is live.
+func (s *Sessions) IdleFor(now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for pid, start := range s.pids {
+		if !s.alive(pid) || s.reused(pid, start) {
+			delete(s.pids, pid)
+		}
+	}
+	if len(s.pids) > 0 {
+		s.emptySince = time.Time{}
+		return 0
+	}
+	if s.emptySince.IsZero() {
+		s.emptySince = now
+	}
+	return now.Sub(s.emptySince)
+}
+
+// reused reports whether pid now names a different process than the one that
+// attached with start time start. An unknown start time on either side is not
+// taken as a change.
+func (s *Sessions) reused(pid int, start string) bool {
+	if start == "" {
+		return false
+	}
+	now := s.started(pid)
+	return now != "" && now != start
+}
+
+// processAlive reports whether pid is a running process (signal 0 probe).
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// processStart returns an opaque stamp of when pid started: the starttime
+// field of /proc/<pid>/stat on Linux, ps's lstart elsewhere. It is "" when the
+// process is gone or the stamp cannot be read, which Sessions treats as
+// unknown rather than changed.
+func processStart(pid int) string {
+	if pid <= 0 {
+		return ""
+	}
+	if data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid)); err == nil {
+		// The command name before the fields may hold spaces and parens, so
+		// count from its closing paren: starttime is the 20th field after it.
+		if i := bytes.LastIndexByte(data, ')'); i >= 0 {
+			if f := strings.Fields(string(data[i+1:])); len(f) > 19 {
+				return f[19]
+			}
+		}
+		return ""
+	}
+	out, err := exec.Command("ps", "-o", "lstart=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// WithSessions enables the AttachPath route, registering launchers in ss.
+func WithSessions(ss *Sessions) Option { return func(s *Server) { s.sessions = ss } }
+
+// AttachRequest is what a launcher POSTs to AttachPath: its pid, and the
+// rotation settings it was started with. The daemon is shared, so settings
+// that differ from the running pool's are applied to it (and logged) rather
+// than silently ignored until the daemon restarts; empty ones keep the pool's.
+type AttachRequest struct {
+	PID       int     `json:"pid"`
+	Policy    string  `json:"policy,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+// handleAttach registers a launcher pid and applies the settings it asks for.
+// The caller has already authenticated the request.
+func (s *Server) handleAttach(w http.ResponseWriter, r *http.Request) {
+	if s.sessions == nil {
+		s.writeError(w, http.StatusNotFound, "not_found_error", "Not a shared proxy daemon")
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		s.writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "Method not allowed")
+		return
+	}
+	var req AttachRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 4096)).Decode(&req); err != nil || req.PID <= 0 {
+		s.writeError(w, http.StatusBadRequest, "invalid_request_error", "Expected {\"pid\": N}")
+		return
+	}
+	var policy Policy
+	if req.Policy != "" {
+		p, err := ParsePolicy(req.Policy)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+			return
+		}
+		policy = p
+	}
+	if req.Threshold < 0 || req.Threshold > 1 {
+		s.writeError(w, http.StatusBadRequest, "invalid_request_error", "threshold must be between 0 and 1")
+		return
+	}
+	s.sessions.Attach(req.PID)
+	if policy != nil && policy.Name() != s.mgr.PolicyName() {
+		log.Printf("[wisp-deck-proxy] session %d changed the rotation policy from %s to %s", req.PID, s.mgr.PolicyName(), policy.Name())
+		s.mgr.SetPolicy(policy)
+	}
+	if req.Threshold > 0 && req.Threshold != s.mgr.Threshold() {
+		log.Printf("[wisp-deck-proxy] session %d changed the switch threshold from %g to %g", req.PID, s.mgr.Threshold(), req.Threshold)
+		s.mgr.SetThreshold(req.Threshold)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AttachSession registers a launcher with the daemon at baseURL (see
+// AttachRequest).
+func AttachSession(baseURL, key string, attach AttachRequest) error {
+	body, _ := json.Marshal(attach)
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(baseURL, "/")+AttachPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+key)
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{Timeout: 5 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+		return fmt.Errorf("attach failed (%d): %s", res.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
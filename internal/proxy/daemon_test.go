@@ -0,0 +1,152 @@
+package proxy
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDaemonInfo_roundTripsOwnerOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DaemonFile)
+	want := DaemonInfo{PID: 42, Port: 5555, Key: "wd-k", CA: "/ca.pem"}
+	if err := WriteDaemonInfo(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadDaemonInfo(path)
+	if err != nil || got != want {
+		t.Fatalf("ReadDaemonInfo = %+v, %v; want %+v", got, err, want)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600 (the record holds the proxy key)", info.Mode().Perm())
+	}
+}
+
+func TestReadDaemonInfo_rejectsIncompleteRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DaemonFile)
+	os.WriteFile(path, []byte(`{"pid":1}`), 0o600)
+	if _, err := ReadDaemonInfo(path); err == nil {
+		t.Error("a record without port/key should be rejected")
+	}
+}
+
+func TestLockDaemon_secondLockerIsRefused(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DaemonLockFile)
+	first, err := LockDaemon(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LockDaemon(path); !errors.Is(err, ErrDaemonRunning) {
+		t.Errorf("second lock err = %v, want ErrDaemonRunning", err)
+	}
+	first.Close()
+	again, err := LockDaemon(path)
+	if err != nil {
+		t.Fatalf("lock should be free once the holder closes it: %v", err)
+	}
+	again.Close()
+}
+
+func TestSessions_idleOnlyOnceAllAttachedProcessesExit(t *testing.T) {
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	alive := map[int]bool{10: true, 11: true}
+	ss := NewSessions(start)
+	ss.alive = func(pid int) bool { return alive[pid] }
+	ss.started = func(int) string { return "" }
+
+	if d := ss.IdleFor(start.Add(time.Minute)); d != time.Minute {
+		t.Errorf("idle before any attach = %s, want 1m from start", d)
+	}
+	ss.Attach(10)
+	ss.Attach(11)
+	if d := ss.IdleFor(start.Add(10 * time.Minute)); d != 0 {
+		t.Errorf("idle with live sessions = %s, want 0", d)
+	}
+	alive[10] = false
+	if d := ss.IdleFor(start.Add(11 * time.Minute)); d != 0 {
+		t.Errorf("idle with one live session = %s, want 0", d)
+	}
+	alive[11] = false
+	ss.IdleFor(start.Add(12 * time.Minute)) // last session gone: idle clock starts
+	if d := ss.IdleFor(start.Add(15 * time.Minute)); d != 3*time.Minute {
+		t.Errorf("idle = %s, want 3m since the last session exited", d)
+	}
+}
+
+// A pid the kernel hands to a new process once the launcher exits must not
+// keep the daemon up.
+func TestSessions_reusedPidDoesNotCountAsTheSession(t *testing.T) {
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	started := map[int]string{10: "100"}
+	ss := NewSessions(start)
+	ss.alive = func(int) bool { return true }
+	ss.started = func(pid int) string { return started[pid] }
+
+	ss.Attach(10)
+	if d := ss.IdleFor(start.Add(time.Minute)); d != 0 {
+		t.Fatalf("idle = %s with the launcher still running, want 0", d)
+	}
+	started[10] = "250" // the launcher exited and its pid was reused
+	ss.IdleFor(start.Add(2 * time.Minute))
+	if d := ss.IdleFor(start.Add(5 * time.Minute)); d != 3*time.Minute {
+		t.Errorf("idle = %s, want 3m since the pid stopped naming the launcher", d)
+	}
+}
+
+// A later launcher's policy and threshold reach the shared pool instead of
+// being ignored until the daemon restarts.
+func TestServer_attachAppliesRequestedSettings(t *testing.T) {
+	ss := NewSessions(time.Now())
+	mgr := NewManager([]Account{{AccessToken: "tok-A"}}, 0.98)
+	srv := NewServer(mgr, "proxy-key", "http://upstream.invalid", WithSessions(ss))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	if err := AttachSession(ts.URL, "proxy-key", AttachRequest{PID: os.Getpid(), Policy: PolicyRoundRobin, Threshold: 0.9}); err != nil {
+		t.Fatal(err)
+	}
+	if mgr.PolicyName() != PolicyRoundRobin || mgr.Threshold() != 0.9 {
+		t.Errorf("pool = %s at %g, want round-robin at 0.9", mgr.PolicyName(), mgr.Threshold())
+	}
+	// Unset settings keep the pool's; an unknown policy is refused.
+	if err := AttachSession(ts.URL, "proxy-key", AttachRequest{PID: os.Getpid()}); err != nil || mgr.PolicyName() != PolicyRoundRobin {
+		t.Errorf("bare attach: err %v, policy %s", err, mgr.PolicyName())
+	}
+	if err := AttachSession(ts.URL, "proxy-key", AttachRequest{PID: os.Getpid(), Policy: "bogus"}); err == nil {
+		t.Error("an unknown policy should be refused")
+	}
+}
+
+func TestServer_attachRegistersSession(t *testing.T) {
+	ss := NewSessions(time.Now())
+	mgr := NewManager([]Account{{AccessToken: "tok-A"}}, 0.98)
+	srv := NewServer(mgr, "proxy-key", "http://upstream.invalid", WithSessions(ss))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	if err := AttachSession(ts.URL, "proxy-key", AttachRequest{PID: os.Getpid()}); err != nil {
+		t.Fatal(err)
+	}
+	if d := ss.IdleFor(time.Now()); d != 0 {
+		t.Errorf("idle = %s after attaching a live pid, want 0", d)
+	}
+	if err := AttachSession(ts.URL, "wrong", AttachRequest{PID: os.Getpid()}); err == nil {
+		t.Error("attach with a bad key should fail")
+	}
+}
+
+func TestServer_attachRouteOnlyOnDaemon(t *testing.T) {
+	mgr := NewManager([]Account{{AccessToken: "tok-A"}}, 0.98)
+	srv := NewServer(mgr, "proxy-key", "http://upstream.invalid")
+	req := httptest.NewRequest(http.MethodPost, AttachPath, strings.NewReader(`{"pid":1}`))
+	req.Header.Set("x-api-key", "proxy-key")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404 outside daemon mode (never forwarded upstream)", rec.Code)
+	}
+}
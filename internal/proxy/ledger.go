@@ -0,0 +1,274 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LedgerFile is the append-only per-account usage ledger the proxy keeps in the
+// accounts dir: which pooled account actually served each call, which the
+// Claude transcripts (all written under one config dir while proxied) cannot
+// tell. Stats reads it as a source of its own (usage.SourceProxy), counting
+// only the calls no transcript already counts.
+const LedgerFile = "proxy-usage.jsonl"
+
+// LedgerArchiveDir is the dir next to LedgerFile that holds the ledgers rotated
+// out of it, each named for the moment it was rotated.
+const LedgerArchiveDir = "proxy-usage"
+
+// The live ledger is rotated into LedgerArchiveDir once an append would take it
+// past maxLedgerBytes, and only the newest maxLedgerArchives rotated files are
+// kept, so the ledger never grows without bound.
+const (
+	maxLedgerBytes    = 8 << 20
+	maxLedgerArchives = 8
+)
+
+// LedgerEntry is one billed Messages call as observed by the proxy. Token
+// fields use the same meaning as usage.ModelUsage: CacheWrite is the total
+// cache-creation count and CacheWrite1h its 1-hour-TTL subset.
+type LedgerEntry struct {
+	Time         time.Time `json:"ts"`
+	Account      string    `json:"account"` // display label
+	Dir          string    `json:"dir"`     // stable account dir
+	Model        string    `json:"model"`
+	MessageID    string    `json:"id,omitempty"`
+	Input        int64     `json:"input"`
+	Output       int64     `json:"output"`
+	CacheWrite   int64     `json:"cache_write"`
+	CacheWrite1h int64     `json:"cache_write_1h"`
+	CacheRead    int64     `json:"cache_read"`
+}
+
+// Ledger appends LedgerEntry lines to a JSONL file. Safe for concurrent use;
+// each entry is written with a single append so concurrent proxies sharing the
+// file never interleave partial lines.
+type Ledger struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	keep     int
+}
+
+// NewLedger returns a Ledger writing to path (created on first append).
+func NewLedger(path string) *Ledger {
+	return &Ledger{path: path, maxBytes: maxLedgerBytes, keep: maxLedgerArchives}
+}
+
+// Append writes one entry as a JSON line, rotating the file first when the
+// line would take it past its size cap.
+func (l *Ledger) Append(e LedgerEntry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+	if err := l.rotate(int64(len(line))); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// rotate moves the live ledger into LedgerArchiveDir when appending incoming
+// bytes would take it past maxBytes, then drops all but the newest keep
+// rotated files. A concurrent proxy that rotated first leaves nothing to move.
+func (l *Ledger) rotate(incoming int64) error {
+	info, err := os.Stat(l.path)
+	if err != nil || info.Size()+incoming <= l.maxBytes {
+		return nil
+	}
+	dir := filepath.Join(filepath.Dir(l.path), LedgerArchiveDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	name := time.Now().UTC().Format("20060102T150405.000000000") + ".jsonl"
+	if err := os.Rename(l.path, filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var rotated []string // oldest first: the names sort by time
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".jsonl") {
+			rotated = append(rotated, e.Name())
+		}
+	}
+	for _, old := range rotated[:max(0, len(rotated)-l.keep)] {
+		if err := os.Remove(filepath.Join(dir, old)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// sseUsage is the usage object carried by message_start (in message.usage) and
+// message_delta (top-level usage). Pointers distinguish "absent" from zero so a
+// delta only overrides the counts it actually reports.
+type sseUsage struct {
+	Input         *int64 `json:"input_tokens"`
+	Output        *int64 `json:"output_tokens"`
+	CacheWrite    *int64 `json:"cache_creation_input_tokens"`
+	CacheRead     *int64 `json:"cache_read_input_tokens"`
+	CacheCreation *struct {
+		Ephemeral5m int64 `json:"ephemeral_5m_input_tokens"`
+		Ephemeral1h int64 `json:"ephemeral_1h_input_tokens"`
+	} `json:"cache_creation"`
+}
+
+// sseEvent is the subset of a Messages stream event the tap needs.
+type sseEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		ID    string    `json:"id"`
+		Model string    `json:"model"`
+		Usage *sseUsage `json:"usage"`
+	} `json:"message"`
+	Usage *sseUsage `json:"usage"`
+}
+
+// usageTap observes a relayed Messages response after each chunk has already
+// been written to the client, so parsing never delays the stream. It handles
+// SSE (message_start / message_delta / message_stop) and, for non-streaming
+// calls, the final JSON body. On completion it reports one LedgerEntry.
+type usageTap struct {
+	stream bool
+	line   []byte       // partial SSE line carried across chunks
+	body   bytes.Buffer // non-streaming body (bounded)
+	entry  LedgerEntry
+	seen   bool // a message_start (or JSON message) was observed
+	done   bool
+	record func(LedgerEntry)
+}
+
+// maxTapBody bounds how much of a non-streaming body the tap buffers; larger
+// bodies are relayed normally but not accounted.
+const maxTapBody = 4 << 20
+
+func newUsageTap(stream bool, base LedgerEntry, record func(LedgerEntry)) *usageTap {
+	return &usageTap{stream: stream, entry: base, record: record}
+}
+
+// Write feeds relayed bytes to the tap. It never fails.
+func (t *usageTap) Write(p []byte) (int, error) {
+	if t.done {
+		return len(p), nil
+	}
+	if !t.stream {
+		if t.body.Len()+len(p) <= maxTapBody {
+			t.body.Write(p)
+		}
+		return len(p), nil
+	}
+	t.line = append(t.line, p...)
+	for {
+		i := bytes.IndexByte(t.line, '\n')
+		if i < 0 {
+			break
+		}
+		t.handleLine(bytes.TrimRight(t.line[:i], "\r"))
+		t.line = t.line[i+1:]
+		if t.done {
+			return len(p), nil
+		}
+	}
+	return len(p), nil
+}
+
+// messageEventPrefix starts the type of every event handleLine acts on
+// (message_start, message_delta, message_stop).
+var messageEventPrefix = []byte(`"message_`)
+
+func (t *usageTap) handleLine(line []byte) {
+	data, ok := bytes.CutPrefix(line, []byte("data:"))
+	if !ok {
+		return
+	}
+	// Only the message_* events carry usage; skip decoding the content deltas
+	// that make up nearly all of a stream.
+	if !bytes.Contains(data, messageEventPrefix) {
+		return
+	}
+	var ev sseEvent
+	if err := json.Unmarshal(bytes.TrimSpace(data), &ev); err != nil {
+		return
+	}
+	switch ev.Type {
+	case "message_start":
+		t.seen = true
+		t.entry.MessageID = ev.Message.ID
+		t.entry.Model = ev.Message.Model
+		t.apply(ev.Message.Usage)
+	case "message_delta":
+		t.apply(ev.Usage)
+	case "message_stop":
+		t.finish()
+	}
+}
+
+// apply overlays the counts present in u (message_delta counts are cumulative).
+func (t *usageTap) apply(u *sseUsage) {
+	if u == nil {
+		return
+	}
+	if u.Input != nil {
+		t.entry.Input = *u.Input
+	}
+	if u.Output != nil {
+		t.entry.Output = *u.Output
+	}
+	if u.CacheRead != nil {
+		t.entry.CacheRead = *u.CacheRead
+	}
+	if cc := u.CacheCreation; cc != nil {
+		t.entry.CacheWrite = cc.Ephemeral5m + cc.Ephemeral1h
+		t.entry.CacheWrite1h = cc.Ephemeral1h
+	} else if u.CacheWrite != nil {
+		t.entry.CacheWrite = *u.CacheWrite
+	}
+}
+
+// finish reports the accumulated usage once. Called on message_stop and again
+// (harmlessly) when the relay ends, which covers streams cut short after
+// message_start and non-streaming bodies.
+func (t *usageTap) finish() {
+	if t.done {
+		return
+	}
+	if !t.stream && t.body.Len() > 0 {
+		var msg struct {
+			Type  string    `json:"type"`
+			ID    string    `json:"id"`
+			Model string    `json:"model"`
+			Usage *sseUsage `json:"usage"`
+		}
+		if json.Unmarshal(t.body.Bytes(), &msg) == nil && msg.Type == "message" && msg.Usage != nil {
+			t.seen = true
+			t.entry.MessageID, t.entry.Model = msg.ID, msg.Model
+			t.apply(msg.Usage)
+		}
+	}
+	t.done = true
+	if t.seen && t.record != nil {
+		t.record(t.entry)
+	}
+}
@@ -0,0 +1,172 @@
+package proxy
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const sseStream = "event: message_start\n" +
+	`data: {"type":"message_start","message":{"id":"msg_1","model":"claude-opus-4-8","usage":{"input_tokens":12,"cache_creation_input_tokens":30,"cache_read_input_tokens":400,"cache_creation":{"ephemeral_5m_input_tokens":10,"ephemeral_1h_input_tokens":20},"output_tokens":1}}}` + "\n\n" +
+	"event: content_block_delta\n" +
+	`data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"hi"}}` + "\n\n" +
+	"event: message_delta\n" +
+	`data: {"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":57}}` + "\n\n" +
+	"event: message_stop\n" +
+	`data: {"type":"message_stop"}` + "\n\n"
+
+func readLedger(t *testing.T, path string) []LedgerEntry {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var out []LedgerEntry
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e LedgerEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("bad ledger line %q: %v", sc.Text(), err)
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+func TestUsageTap_parsesSplitSSEChunks(t *testing.T) {
+	var got []LedgerEntry
+	tap := newUsageTap(true, LedgerEntry{Account: "A"}, func(e LedgerEntry) { got = append(got, e) })
+	// Feed one byte at a time: events must be reassembled across chunk borders.
+	for i := 0; i < len(sseStream); i++ {
+		tap.Write([]byte{sseStream[i]})
+	}
+	tap.finish() // relay end after message_stop must not double-record
+	if len(got) != 1 {
+		t.Fatalf("recorded %d entries, want 1", len(got))
+	}
+	e := got[0]
+	if e.Account != "A" || e.Model != "claude-opus-4-8" || e.MessageID != "msg_1" {
+		t.Errorf("entry = %+v", e)
+	}
+	if e.Input != 12 || e.Output != 57 || e.CacheRead != 400 || e.CacheWrite != 30 || e.CacheWrite1h != 20 {
+		t.Errorf("tokens = %+v, want in12 out57 cr400 cw30 cw1h20", e)
+	}
+}
+
+func TestUsageTap_recordsTruncatedStreamOnFinish(t *testing.T) {
+	var got []LedgerEntry
+	tap := newUsageTap(true, LedgerEntry{}, func(e LedgerEntry) { got = append(got, e) })
+	tap.Write([]byte(`data: {"type":"message_start","message":{"model":"m","usage":{"input_tokens":5,"output_tokens":1}}}` + "\n"))
+	tap.finish()
+	if len(got) != 1 || got[0].Input != 5 || got[0].Output != 1 {
+		t.Errorf("got %+v, want one entry with the message_start counts", got)
+	}
+}
+
+func TestUsageTap_nonStreamingJSONBody(t *testing.T) {
+	var got []LedgerEntry
+	tap := newUsageTap(false, LedgerEntry{}, func(e LedgerEntry) { got = append(got, e) })
+	tap.Write([]byte(`{"type":"message","id":"msg_9","model":"claude-haiku-4-5",`))
+	tap.Write([]byte(`"usage":{"input_tokens":3,"output_tokens":4,"cache_read_input_tokens":2,"cache_creation_input_tokens":1}}`))
+	tap.finish()
+	if len(got) != 1 {
+		t.Fatalf("recorded %d entries, want 1", len(got))
+	}
+	if e := got[0]; e.Model != "claude-haiku-4-5" || e.Input != 3 || e.Output != 4 || e.CacheRead != 2 || e.CacheWrite != 1 {
+		t.Errorf("entry = %+v", e)
+	}
+}
+
+func TestUsageTap_ignoresBodiesWithoutUsage(t *testing.T) {
+	called := false
+	tap := newUsageTap(false, LedgerEntry{}, func(LedgerEntry) { called = true })
+	tap.Write([]byte(`{"input_tokens":42}`)) // count_tokens response
+	tap.finish()
+	if called {
+		t.Error("a non-message body must not be recorded")
+	}
+}
+
+func TestServer_recordsStreamedUsageToLedger(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(200)
+		io.WriteString(w, sseStream)
+	}))
+	defer upstream.Close()
+
+	path := filepath.Join(t.TempDir(), LedgerFile)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	mgr := NewManager([]Account{{Label: "work", Dir: "acct-1", AccessToken: "tok-A"}}, 0.98)
+	srv := NewServer(mgr, "proxy-key", upstream.URL, WithLedger(NewLedger(path)),
+		WithNow(func() time.Time { return now }), WithSleep(func(time.Duration) {}))
+
+	rec := doRequest(t, srv, "proxy-key", `{"stream":true}`)
+	if rec.Code != 200 || rec.Body.String() != sseStream {
+		t.Fatalf("stream must be relayed unchanged: status %d body %q", rec.Code, rec.Body.String())
+	}
+	entries := readLedger(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("ledger has %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Account != "work" || e.Dir != "acct-1" || !e.Time.Equal(now) || e.Output != 57 {
+		t.Errorf("entry = %+v", e)
+	}
+
+	// A second call appends rather than rewriting.
+	doRequest(t, srv, "proxy-key", `{"stream":true}`)
+	if n := len(readLedger(t, path)); n != 2 {
+		t.Errorf("ledger has %d entries after two calls, want 2", n)
+	}
+}
+
+func TestServer_doesNotRecordErrorResponses(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(400)
+		io.WriteString(w, `{"type":"error","error":{"type":"invalid_request_error","message":"bad"}}`)
+	}))
+	defer upstream.Close()
+
+	path := filepath.Join(t.TempDir(), LedgerFile)
+	mgr := NewManager([]Account{{Label: "work", AccessToken: "tok-A"}}, 0.98)
+	srv := NewServer(mgr, "proxy-key", upstream.URL, WithLedger(NewLedger(path)), WithSleep(func(time.Duration) {}))
+	doRequest(t, srv, "proxy-key", `{}`)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("ledger should not be written for an error response (stat err=%v)", err)
+	}
+}
+
+func TestLedger_rotatesPastItsCapAndKeepsTheNewest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, LedgerFile)
+	l := NewLedger(path)
+	l.maxBytes, l.keep = 200, 2
+	for i := range 12 {
+		if err := l.Append(LedgerEntry{Account: "work", Dir: "acct-1", Model: "m", MessageID: fmt.Sprintf("msg_%d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() > 200 {
+		t.Fatalf("live ledger = %v, %v; want it kept under the cap", info, err)
+	}
+	rotated, _ := os.ReadDir(filepath.Join(dir, LedgerArchiveDir))
+	if len(rotated) != 2 {
+		t.Fatalf("rotated = %d files, want the newest 2", len(rotated))
+	}
+	// Nothing newer than what is kept was lost: the last entries are all there.
+	last := readLedger(t, filepath.Join(dir, LedgerArchiveDir, rotated[1].Name()))
+	live := readLedger(t, path)
+	if got := live[len(live)-1].MessageID; got != "msg_11" || last[len(last)-1].MessageID != fmt.Sprintf("msg_%d", 11-len(live)) {
+		t.Errorf("live ends at %s, newest rotated at %s", got, last[len(last)-1].MessageID)
+	}
+}
@@ -1,7 +1,6 @@
 package proxy
 
 import (
-	"math"
 	"net/http"
 	"strconv"
 	"sync"
@@ -26,6 +25,13 @@ type acctState struct {
 	q              quota
 	throttledUntil time.Time
 	errored        bool
+	// removed marks an account dropped from the list by a Reload. Its slot is
+	// kept (never served) so indices held by in-flight requests and pins stay
+	// valid; re-adding the same dir revives it.
+	removed bool
+	// Output tokens served on day (local YYYY-MM-DD), for Caps.DailyOutput.
+	day       string
+	dayOutput int64
 }
 
 // Manager holds the account pool and chooses the active account, rotating on
@@ -35,6 +41,15 @@ type Manager struct {
 	accounts  []*acctState
 	current   int
 	threshold float64
+	policy    Policy
+	affinity  map[string]pin // conversation key → pinned account
+
+	statePath string     // quota snapshot target; "" disables persistence
+	saveMu    sync.Mutex // serializes snapshot writes
+	saved     []byte     // last snapshot written/loaded, to skip no-op writes
+
+	flushMu      sync.Mutex
+	flushPending bool // a debounced FlushState is scheduled
 }
 
 // NewManager builds a Manager over the pool with the given switch threshold
@@ -44,11 +59,21 @@ func NewManager(accounts []Account, threshold float64) *Manager {
 	for i, a := range accounts {
 		states[i] = &acctState{Account: a, q: quota{u5h: -1, u7d: -1}}
 	}
-	return &Manager{accounts: states, threshold: threshold}
+	return &Manager{accounts: states, threshold: threshold, policy: DrainPolicy{}}
 }
 
 // Len reports the number of accounts in the pool.
-func (m *Manager) Len() int { return len(m.accounts) }
+func (m *Manager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, a := range m.accounts {
+		if !a.removed {
+			n++
+		}
+	}
+	return n
+}
 
 // ActiveIndex returns the index of the currently selected account.
 func (m *Manager) ActiveIndex() int {
@@ -57,10 +82,14 @@ func (m *Manager) ActiveIndex() int {
 	return m.current
 }
 
-// Active returns a copy of the currently selected account.
+// Active returns a copy of the currently selected account (zero when the pool
+// is empty).
 func (m *Manager) Active() Account {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if m.current >= len(m.accounts) {
+		return Account{}
+	}
 	return m.accounts[m.current].Account
 }
 
@@ -73,6 +102,7 @@ func (m *Manager) AccountAt(idx int) Account {
 
 // UpdateQuota records unified rate-limit utilization from response headers.
 func (m *Manager) UpdateQuota(idx int, h http.Header) {
+	defer m.persist() // runs after the unlock below
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if idx < 0 || idx >= len(m.accounts) {
@@ -146,13 +176,13 @@ func (m *Manager) clearExpired(idx int, now time.Time) {
 func (m *Manager) isAvailable(idx int, now time.Time) bool {
 	m.clearExpired(idx, now)
 	a := m.accounts[idx]
-	if a.errored {
+	if a.errored || a.removed {
 		return false
 	}
 	if !a.throttledUntil.IsZero() && now.Before(a.throttledUntil) {
 		return false
 	}
-	return !m.nearQuota(idx)
+	return !m.nearQuota(idx) && m.capReason(idx, now) == ""
 }
 
 // HasAvailable reports whether any account can currently serve a request.
@@ -167,44 +197,89 @@ func (m *Manager) HasAvailable(now time.Time) bool {
 	return false
 }
 
-// pickBest returns the index of the best available account, skipping any in
-// exclude, or -1 if none are available. Selection order mirrors teamclaude's
-// _pickBestAvailable: an account with no known weekly reset sorts first (so its
-// quota gets discovered), then the account whose weekly window resets soonest —
-// that quota is closest to refreshing, so spending it first preserves accounts
-// whose windows reset later. Ties break by index.
-func (m *Manager) pickBest(exclude map[int]bool, now time.Time) int {
-	best := -1
-	var bestReset int64
-	for i := range m.accounts {
+// HasAvailableExcept reports whether any account outside exclude can serve a
+// request right now.
+func (m *Manager) HasAvailableExcept(exclude map[int]bool, now time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.candidates(exclude, now)) > 0
+}
+
+// candidates lists the accounts a Policy may choose from: available and not in
+// exclude, in index order.
+func (m *Manager) candidates(exclude map[int]bool, now time.Time) []Candidate {
+	var cands []Candidate
+	for i, a := range m.accounts {
 		if exclude[i] || !m.isAvailable(i, now) {
 			continue
 		}
-		// Unknown weekly reset sorts first (math.MinInt64 sentinel).
-		reset := int64(math.MinInt64)
-		if r := m.accounts[i].q.u7dReset; !r.IsZero() {
-			reset = r.Unix()
+		util := -1.0
+		if a.q.u5h >= 0 || a.q.u7d >= 0 {
+			util = m.util(i)
 		}
-		if best < 0 || reset < bestReset {
-			bestReset = reset
-			best = i
+		weight := a.Weight
+		if weight < 1 {
+			weight = 1
 		}
+		var served int64
+		if a.day == dayKey(now) {
+			served = a.dayOutput
+		}
+		cands = append(cands, Candidate{Index: i, Util: util, Reset7d: a.q.u7dReset, Priority: a.Priority, Weight: weight, Served: served})
+	}
+	return cands
+}
+
+// pickBest asks the policy for the best available account, skipping any in
+// exclude, or returns -1 if none are available. current is the account the
+// policy may keep (-1 to choose from scratch).
+func (m *Manager) pickBest(current int, exclude map[int]bool, now time.Time) int {
+	cands := m.candidates(exclude, now)
+	if len(cands) == 0 {
+		return -1
 	}
-	return best
+	return m.policy.Pick(current, cands)
+}
+
+// SetPolicy replaces the rotation policy (DrainPolicy by default).
+func (m *Manager) SetPolicy(p Policy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.policy = p
+}
+
+// SetThreshold replaces the utilization (0-1) at which the pool switches
+// accounts.
+func (m *Manager) SetThreshold(t float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.threshold = t
+}
+
+// Threshold reports the utilization at which the pool switches accounts.
+func (m *Manager) Threshold() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.threshold
+}
+
+// PolicyName reports the active rotation policy's name.
+func (m *Manager) PolicyName() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.policy.Name()
 }
 
 // GetActiveAccount selects the account to serve a request, excluding any indices
 // already tried this request. Mirrors teamclaude's getActiveAccount(tried): the
-// current account is kept while it is available and not excluded; otherwise the
-// best available account is chosen. Returns (index, true) or (-1, false) when
-// every account is exhausted.
+// policy sees the current account among the candidates while it is available
+// and not excluded (the default policy keeps it), otherwise it chooses among
+// the rest. Returns (index, true) or (-1, false) when every account is
+// exhausted.
 func (m *Manager) GetActiveAccount(exclude map[int]bool, now time.Time) (int, bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	if !exclude[m.current] && m.isAvailable(m.current, now) {
-		return m.current, true
-	}
-	best := m.pickBest(exclude, now)
+	best := m.pickBest(m.current, exclude, now)
 	if best < 0 {
 		return -1, false
 	}
@@ -212,8 +287,9 @@ func (m *Manager) GetActiveAccount(exclude map[int]bool, now time.Time) (int, bo
 	return best, true
 }
 
-// Rotate selects the best account. If the current account is still available it
-// stays put; otherwise it switches to the best available account (see pickBest).
+// Rotate selects the best account per the policy. Under the default policy the
+// current account stays put while available; otherwise it switches to the best
+// available account.
 // Returns the selected index and whether it changed.
 func (m *Manager) Rotate(now time.Time) (int, bool) {
 	prev := m.ActiveIndex()
@@ -224,13 +300,13 @@ func (m *Manager) Rotate(now time.Time) (int, bool) {
 	return idx, idx != prev
 }
 
-// SelectBest picks the best account up front (e.g. at startup), preferring the
-// least-utilized available one but never failing — it leaves current in place
-// when nothing is available.
+// SelectBest picks the best account up front (e.g. at startup), choosing from
+// scratch per the policy but never failing — it leaves current in place when
+// nothing is available.
 func (m *Manager) SelectBest(now time.Time) int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	if best := m.pickBest(nil, now); best >= 0 {
+	if best := m.pickBest(-1, nil, now); best >= 0 {
 		m.current = best
 	}
 	return m.current
@@ -238,6 +314,7 @@ func (m *Manager) SelectBest(now time.Time) int {
 
 // MarkThrottled records that an account is rate-limited until the given time.
 func (m *Manager) MarkThrottled(idx int, until time.Time) {
+	defer m.persist()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if idx >= 0 && idx < len(m.accounts) {
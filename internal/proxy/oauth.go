@@ -27,6 +27,12 @@ type Tokens struct {
 // response is returned as an error so the caller can decide whether to retire
 // the account.
 func RefreshToken(endpoint, refreshToken string) (Tokens, error) {
+	return refreshTokenVia(nil, endpoint, refreshToken)
+}
+
+// refreshTokenVia is RefreshToken over the given transport (nil for the
+// default), so a recording proxy captures refreshes alongside API traffic.
+func refreshTokenVia(transport http.RoundTripper, endpoint, refreshToken string) (Tokens, error) {
 	reqBody, _ := json.Marshal(map[string]string{
 		"grant_type":    "refresh_token",
 		"refresh_token": refreshToken,
@@ -39,7 +45,7 @@ func RefreshToken(endpoint, refreshToken string) (Tokens, error) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := &http.Client{Timeout: 30 * time.Second, Transport: transport}
 	res, err := client.Do(req)
 	if err != nil {
 		return Tokens{}, err
@@ -0,0 +1,182 @@
+package proxy
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jackuait/wisp-deck/internal/claudeaccount"
+)
+
+// Candidate is an account a Policy may choose: available (not errored,
+// throttled or near quota) and not already tried for the current request.
+type Candidate struct {
+	Index    int       // position in the pool
+	Util     float64   // highest known utilization 0-1; -1 when nothing is known
+	Reset7d  time.Time // weekly window reset; zero when unknown
+	Priority int       // user-assigned; lower runs first
+	Weight   int       // user-assigned share of its priority tier; at least 1
+	Served   int64     // output tokens served today (local day)
+}
+
+// Policy decides which account serves the next request. Pick is called with
+// the current account index (-1 when choosing from scratch, e.g. at startup)
+// and a non-empty, index-ordered list of candidates; it returns the chosen
+// candidate's Index. The current account appears in cands only when it is
+// still usable, so a policy that wants to "stay put" checks for it there.
+type Policy interface {
+	Name() string
+	Pick(current int, cands []Candidate) int
+}
+
+// Built-in policy names accepted by ParsePolicy (and the --policy flag). They
+// are defined with the rest of the rotation settings in claudeaccount, which
+// the Settings menu reads without depending on the proxy.
+const (
+	PolicyDrain         = claudeaccount.PolicyDrain
+	PolicyRoundRobin    = claudeaccount.PolicyRoundRobin
+	PolicyLeastUtilized = claudeaccount.PolicyLeastUtilized
+	PolicyPriority      = claudeaccount.PolicyPriority
+)
+
+// PolicyNames lists the built-in policies in menu order (default first).
+var PolicyNames = claudeaccount.PolicyNames
+
+// ParsePolicy returns the built-in policy with the given name ("" selects the
+// default drain policy).
+func ParsePolicy(name string) (Policy, error) {
+	switch strings.TrimSpace(name) {
+	case "", PolicyDrain:
+		return DrainPolicy{}, nil
+	case PolicyRoundRobin:
+		return RoundRobinPolicy{}, nil
+	case PolicyLeastUtilized:
+		return LeastUtilizedPolicy{}, nil
+	case PolicyPriority:
+		return PriorityPolicy{}, nil
+	}
+	return nil, fmt.Errorf("unknown rotation policy %q (want one of %s)", name, strings.Join(PolicyNames, ", "))
+}
+
+// containsIndex reports whether idx is among cands.
+func containsIndex(cands []Candidate, idx int) bool {
+	for _, c := range cands {
+		if c.Index == idx {
+			return true
+		}
+	}
+	return false
+}
+
+// drainOrder returns the candidate whose weekly window resets soonest, with an
+// unknown reset sorting first (so its quota gets discovered). Ties break by
+// index, which cands are already ordered by.
+func drainOrder(cands []Candidate) int {
+	best := -1
+	var bestReset int64
+	for _, c := range cands {
+		reset := int64(math.MinInt64)
+		if !c.Reset7d.IsZero() {
+			reset = c.Reset7d.Unix()
+		}
+		if best < 0 || reset < bestReset {
+			best, bestReset = c.Index, reset
+		}
+	}
+	return best
+}
+
+// DrainPolicy is the default, mirroring teamclaude's _pickBestAvailable: stay
+// on the current account until it nears the threshold, then move to the account
+// whose weekly window resets soonest — that quota is closest to refreshing, so
+// spending it first preserves accounts whose windows reset later.
+type DrainPolicy struct{}
+
+func (DrainPolicy) Name() string { return PolicyDrain }
+
+func (DrainPolicy) Pick(current int, cands []Candidate) int {
+	if containsIndex(cands, current) {
+		return current
+	}
+	return drainOrder(cands)
+}
+
+// RoundRobinPolicy advances to the next usable account on every request,
+// spreading load evenly at the cost of prompt-cache reuse.
+type RoundRobinPolicy struct{}
+
+func (RoundRobinPolicy) Name() string { return PolicyRoundRobin }
+
+func (RoundRobinPolicy) Pick(current int, cands []Candidate) int {
+	for _, c := range cands {
+		if c.Index > current {
+			return c.Index
+		}
+	}
+	return cands[0].Index
+}
+
+// LeastUtilizedPolicy sends each request to the account with the most headroom.
+// Unknown utilization counts as zero; ties keep the current account, then break
+// by index.
+type LeastUtilizedPolicy struct{}
+
+func (LeastUtilizedPolicy) Name() string { return PolicyLeastUtilized }
+
+func (LeastUtilizedPolicy) Pick(current int, cands []Candidate) int {
+	best := cands[0]
+	for _, c := range cands[1:] {
+		cu, bu := math.Max(c.Util, 0), math.Max(best.Util, 0)
+		if cu < bu || (cu == bu && c.Index == current) {
+			best = c
+		}
+	}
+	return best.Index
+}
+
+// PriorityPolicy honors user-assigned priorities: accounts in the lowest
+// priority tier are used first and higher tiers only once every account ahead
+// of them is unusable — e.g. a personal account as a last resort. It returns
+// to a better tier as soon as one recovers. Within a tier of equal weights it
+// behaves like DrainPolicy; unequal weights split the tier's traffic instead,
+// sending each request to the account furthest below its weighted share of
+// today's output (weights 2 and 1 serve about two thirds and one third).
+type PriorityPolicy struct{}
+
+func (PriorityPolicy) Name() string { return PolicyPriority }
+
+func (PriorityPolicy) Pick(current int, cands []Candidate) int {
+	top := cands[0].Priority
+	for _, c := range cands[1:] {
+		if c.Priority < top {
+			top = c.Priority
+		}
+	}
+	var tier []Candidate
+	weighted := false
+	for _, c := range cands {
+		if c.Priority == top {
+			weighted = weighted || (len(tier) > 0 && c.Weight != tier[0].Weight)
+			tier = append(tier, c)
+		}
+	}
+	if !weighted {
+		return DrainPolicy{}.Pick(current, tier)
+	}
+	return weightedShare(current, tier)
+}
+
+// weightedShare returns the candidate with the least output served per unit
+// of weight, keeping the current account on a tie, then breaking by index.
+func weightedShare(current int, cands []Candidate) int {
+	best := cands[0]
+	for _, c := range cands[1:] {
+		// c.Served/c.Weight vs best.Served/best.Weight, cross-multiplied.
+		l, r := c.Served*int64(best.Weight), best.Served*int64(c.Weight)
+		if l < r || (l == r && c.Index == current) {
+			best = c
+		}
+	}
+	return best.Index
+}
@@ -0,0 +1,128 @@
+package proxy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePolicy_knownAndUnknownNames(t *testing.T) {
+	for _, name := range append([]string{""}, PolicyNames...) {
+		p, err := ParsePolicy(name)
+		if err != nil {
+			t.Fatalf("ParsePolicy(%q): %v", name, err)
+		}
+		if name != "" && p.Name() != name {
+			t.Errorf("ParsePolicy(%q).Name() = %q", name, p.Name())
+		}
+	}
+	if p, _ := ParsePolicy(""); p.Name() != PolicyDrain {
+		t.Errorf("empty name should select %q, got %q", PolicyDrain, p.Name())
+	}
+	if _, err := ParsePolicy("random"); err == nil {
+		t.Error("unknown policy should be an error")
+	}
+}
+
+func TestRoundRobinPolicy_advancesEveryRequestAndWraps(t *testing.T) {
+	m := NewManager(testAccounts(3), 0.98)
+	m.SetPolicy(RoundRobinPolicy{})
+	now := time.Now()
+	var got []int
+	for i := 0; i < 4; i++ {
+		idx, _ := m.GetActiveAccount(nil, now)
+		got = append(got, idx)
+	}
+	want := []int{1, 2, 0, 1}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sequence = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestRoundRobinPolicy_skipsUnavailable(t *testing.T) {
+	m := NewManager(testAccounts(3), 0.98)
+	m.SetPolicy(RoundRobinPolicy{})
+	now := time.Now()
+	m.MarkThrottled(1, now.Add(time.Hour))
+	if idx, _ := m.GetActiveAccount(nil, now); idx != 2 {
+		t.Errorf("got %d, want 2 (1 is throttled)", idx)
+	}
+}
+
+func TestLeastUtilizedPolicy_picksMostHeadroom(t *testing.T) {
+	m := NewManager(testAccounts(3), 0.98)
+	m.SetPolicy(LeastUtilizedPolicy{})
+	m.UpdateQuota(0, hdr("anthropic-ratelimit-unified-5h-utilization", "0.50"))
+	m.UpdateQuota(1, hdr("anthropic-ratelimit-unified-5h-utilization", "0.20"))
+	m.UpdateQuota(2, hdr("anthropic-ratelimit-unified-7d-utilization", "0.30"))
+	idx, switched := m.Rotate(time.Now())
+	if !switched || idx != 1 {
+		t.Errorf("got (idx=%d switched=%v), want (1, true)", idx, switched)
+	}
+}
+
+func TestLeastUtilizedPolicy_tieKeepsCurrent(t *testing.T) {
+	m := NewManager(testAccounts(2), 0.98)
+	m.SetPolicy(LeastUtilizedPolicy{})
+	m.UpdateQuota(0, hdr("anthropic-ratelimit-unified-5h-utilization", "0.20"))
+	m.UpdateQuota(1, hdr("anthropic-ratelimit-unified-5h-utilization", "0.20"))
+	if idx, switched := m.Rotate(time.Now()); switched || idx != 0 {
+		t.Errorf("got (idx=%d switched=%v), want (0, false) on a tie", idx, switched)
+	}
+}
+
+func TestPriorityPolicy_keepsLastResortUntilOthersExhausted(t *testing.T) {
+	accts := testAccounts(3)
+	accts[0].Priority = 9 // personal: last resort
+	m := NewManager(accts, 0.98)
+	m.SetPolicy(PriorityPolicy{})
+	now := time.Now()
+
+	if idx := m.SelectBest(now); idx != 1 {
+		t.Fatalf("SelectBest = %d, want 1 (first account in the top tier)", idx)
+	}
+	m.MarkThrottled(1, now.Add(time.Hour))
+	if idx, _ := m.GetActiveAccount(nil, now); idx != 2 {
+		t.Fatalf("got %d, want 2 (same tier before the last resort)", idx)
+	}
+	m.MarkThrottled(2, now.Add(time.Hour))
+	if idx, _ := m.GetActiveAccount(nil, now); idx != 0 {
+		t.Fatalf("got %d, want 0 (last resort once the tier is exhausted)", idx)
+	}
+	// As soon as a preferred account recovers, rotation leaves the last resort.
+	if idx, _ := m.GetActiveAccount(nil, now.Add(2*time.Hour)); idx != 1 {
+		t.Errorf("got %d, want 1 (back to the preferred tier)", idx)
+	}
+}
+
+func TestPriorityPolicy_weightsSplitATier(t *testing.T) {
+	accts := testAccounts(3)
+	accts[0].Weight = 2
+	accts[2].Priority = 9 // outside the tier: never picked while it has room
+	m := NewManager(accts, 0.98)
+	m.SetPolicy(PriorityPolicy{})
+	now := time.Now()
+
+	served := map[int]int64{}
+	for range 30 {
+		idx, ok := m.GetActiveAccount(nil, now)
+		if !ok {
+			t.Fatal("pool should have room")
+		}
+		served[idx] += 100
+		m.RecordOutput(idx, 100, now)
+	}
+	if served[2] != 0 {
+		t.Errorf("the priority-9 account served %d, want 0", served[2])
+	}
+	if served[0] != 2000 || served[1] != 1000 {
+		t.Errorf("served = %v, want a 2:1 split of 3000 between 0 and 1", served)
+	}
+}
+
+func TestManager_defaultPolicyIsDrain(t *testing.T) {
+	if got := NewManager(testAccounts(1), 0.98).PolicyName(); got != PolicyDrain {
+		t.Errorf("default policy = %q, want %q", got, PolicyDrain)
+	}
+}
@@ -0,0 +1,142 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jackuait/wisp-deck/internal/claudeaccount"
+)
+
+// Reload merges a freshly loaded account list into the live pool, matching
+// accounts by Dir. Accounts still listed keep their learned quota and throttle
+// state and pick up a new label, priority, weight, caps, and — when the
+// credentials file holds a token that expires no earlier than the one in
+// memory — externally refreshed tokens (which also clears a prior refresh
+// failure). New dirs are appended; dirs no longer listed are retired in place
+// rather than deleted, so an in-flight request or a pinned conversation never
+// sees an index shift: a conversation on a retired account simply fails over
+// on its next turn.
+// Returns how many accounts were added and removed.
+func (m *Manager) Reload(accounts []Account) (added, removed int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	byDir := make(map[string]*acctState, len(m.accounts))
+	for _, a := range m.accounts {
+		byDir[a.Dir] = a
+	}
+	listed := make(map[string]bool, len(accounts))
+	for _, acc := range accounts {
+		listed[acc.Dir] = true
+		a, ok := byDir[acc.Dir]
+		if !ok {
+			m.accounts = append(m.accounts, &acctState{Account: acc, q: quota{u5h: -1, u7d: -1}})
+			added++
+			continue
+		}
+		if a.removed {
+			a.removed = false
+			added++
+		}
+		a.Label, a.Priority, a.Weight, a.Caps = acc.Label, acc.Priority, acc.Weight, acc.Caps
+		if acc.AccessToken != a.AccessToken && acc.ExpiresAt >= a.ExpiresAt {
+			a.AccessToken, a.RefreshToken, a.ExpiresAt = acc.AccessToken, acc.RefreshToken, acc.ExpiresAt
+			a.errored = false
+		}
+	}
+	for _, a := range m.accounts {
+		if !a.removed && !listed[a.Dir] {
+			a.removed = true
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// PoolWatcher keeps a Manager in sync with the on-disk accounts list, its caps
+// sidecar and each listed account's .credentials.json, so logins (and their
+// rotation and caps) added, edited or removed from the Settings › Login menu
+// reach already-running sessions.
+// Files are polled (stat only) rather than watched, which works the same on
+// every platform and survives the atomic-rename writes used for credentials.
+type PoolWatcher struct {
+	mgr         *Manager
+	accountsDir string
+	listFile    string
+	last        string // fingerprint of the watched files at the last load
+}
+
+// NewPoolWatcher returns a watcher for mgr, treating the files' current state
+// as already loaded.
+func NewPoolWatcher(mgr *Manager, accountsDir, listFile string) *PoolWatcher {
+	w := &PoolWatcher{mgr: mgr, accountsDir: accountsDir, listFile: listFile}
+	w.last = w.fingerprint()
+	return w
+}
+
+// Poll reloads the pool when any watched file changed since the last load and
+// reports whether it did. A list that fails to load leaves the pool untouched
+// and is retried on the next poll.
+func (w *PoolWatcher) Poll() (bool, error) {
+	fp := w.fingerprint()
+	if fp == w.last {
+		return false, nil
+	}
+	accounts, err := LoadAccounts(w.accountsDir, w.listFile)
+	if err != nil {
+		return false, err
+	}
+	w.last = fp
+	added, removed := w.mgr.Reload(accounts)
+	if added > 0 || removed > 0 {
+		log.Printf("[wisp-deck-proxy] account pool reloaded: %d added, %d removed, %d in rotation", added, removed, w.mgr.Len())
+	}
+	return true, nil
+}
+
+// Run polls every interval until ctx is done.
+func (w *PoolWatcher) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if _, err := w.Poll(); err != nil {
+				log.Printf("[wisp-deck-proxy] account reload failed: %v", err)
+			}
+		}
+	}
+}
+
+// fingerprint summarizes the size and mtime of every watched file: the list,
+// its caps and rotation sidecars, and the credentials of each dir it names.
+func (w *PoolWatcher) fingerprint() string {
+	paths := []string{w.listFile, claudeaccount.CapsFile(w.listFile), claudeaccount.RotationFile(w.listFile)}
+	if f, err := os.Open(w.listFile); err == nil {
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			if a, ok := claudeaccount.ParseLine(sc.Text()); ok && strings.TrimSpace(a.Dir) != "" {
+				paths = append(paths, filepath.Join(w.accountsDir, strings.TrimSpace(a.Dir), ".credentials.json"))
+			}
+		}
+		f.Close()
+	}
+	var b strings.Builder
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			fmt.Fprintf(&b, "%s:-\n", p)
+			continue
+		}
+		fmt.Fprintf(&b, "%s:%d:%d\n", p, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String()
+}
@@ -0,0 +1,131 @@
+package proxy
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestManager_reloadKeepsQuotaAddsAndRetires(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	m := NewManager([]Account{
+		{Label: "A", Dir: "a", AccessToken: "tok-A"},
+		{Label: "B", Dir: "b", AccessToken: "tok-B"},
+	}, 0.98)
+	h := http.Header{}
+	h.Set("anthropic-ratelimit-unified-5h-utilization", "0.4")
+	m.UpdateQuota(0, h)
+
+	added, removed := m.Reload([]Account{
+		{Label: "A renamed", Dir: "a", AccessToken: "tok-A"},
+		{Label: "C", Dir: "c", AccessToken: "tok-C"},
+	})
+	if added != 1 || removed != 1 {
+		t.Errorf("added, removed = %d, %d; want 1, 1", added, removed)
+	}
+	if m.Len() != 2 {
+		t.Errorf("Len = %d, want 2", m.Len())
+	}
+	if u := m.Utilization(0); u < 0.39 || u > 0.41 {
+		t.Errorf("learned quota for A lost across reload: %v", u)
+	}
+	if got := m.AccountAt(0).Label; got != "A renamed" {
+		t.Errorf("label = %q, want the reloaded label", got)
+	}
+	// The retired slot keeps its index (an in-flight request may still hold it)
+	// but is never served.
+	if got := m.AccountAt(1).AccessToken; got != "tok-B" {
+		t.Errorf("retired slot = %q, want tok-B kept in place", got)
+	}
+	st := m.Status(now)
+	if len(st.Accounts) != 2 || st.Accounts[1].Label != "C" {
+		t.Errorf("status accounts = %+v, want A and C only", st.Accounts)
+	}
+	if idx, ok := m.GetActiveAccount(map[int]bool{0: true}, now); !ok || idx != 2 {
+		t.Errorf("GetActiveAccount excluding A = %d, %v; want the new account C", idx, ok)
+	}
+
+	// Re-adding B revives its old slot instead of growing the pool.
+	if added, _ := m.Reload([]Account{{Dir: "a", AccessToken: "tok-A"}, {Dir: "b", AccessToken: "tok-B"}, {Dir: "c", AccessToken: "tok-C"}}); added != 1 {
+		t.Errorf("re-adding b: added = %d, want 1", added)
+	}
+	if m.Len() != 3 || len(m.accounts) != 3 {
+		t.Errorf("Len = %d slots = %d, want 3 and 3", m.Len(), len(m.accounts))
+	}
+}
+
+func TestManager_reloadAdoptsOnlyNewerTokens(t *testing.T) {
+	m := NewManager([]Account{{Dir: "a", AccessToken: "tok-old", RefreshToken: "r-old", ExpiresAt: 1000}}, 0.98)
+	m.MarkErrored(0)
+
+	// A stale file (older expiry, e.g. read mid-write of our own refresh) is ignored.
+	m.Reload([]Account{{Dir: "a", AccessToken: "tok-stale", ExpiresAt: 500}})
+	if got := m.AccountAt(0).AccessToken; got != "tok-old" {
+		t.Errorf("token = %q, a stale file must not roll it back", got)
+	}
+
+	// An externally refreshed login replaces the token and clears the error.
+	m.Reload([]Account{{Dir: "a", AccessToken: "tok-new", RefreshToken: "r-new", ExpiresAt: 2000}})
+	a := m.AccountAt(0)
+	if a.AccessToken != "tok-new" || a.RefreshToken != "r-new" || a.ExpiresAt != 2000 {
+		t.Errorf("account = %+v, want the refreshed tokens", a)
+	}
+	if !m.HasAvailable(time.Now()) {
+		t.Error("a fresh login should clear the errored flag")
+	}
+}
+
+func TestManager_reloadMovesPinnedConversationOffRemovedAccount(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	m := NewManager([]Account{{Dir: "a", AccessToken: "tok-A"}, {Dir: "b", AccessToken: "tok-B"}}, 0.98)
+	if idx, _ := m.GetAccountFor("session:s1", nil, now); idx != 0 {
+		t.Fatalf("pinned to %d, want 0", idx)
+	}
+	m.Reload([]Account{{Dir: "b", AccessToken: "tok-B"}})
+	idx, ok := m.GetAccountFor("session:s1", nil, now)
+	if !ok || idx != 1 {
+		t.Errorf("conversation on a removed account = %d, %v; want it to continue on b", idx, ok)
+	}
+}
+
+func TestPoolWatcher_reloadsOnListAndCredentialChanges(t *testing.T) {
+	root := t.TempDir()
+	accountsDir := filepath.Join(root, "claude-accounts")
+	list := filepath.Join(root, "claude-accounts.list")
+	writeCreds(t, accountsDir, "a", "tok-A", "r-A", 1000)
+	writeList(t, list, "A:a\n")
+
+	accounts, err := LoadAccounts(accountsDir, list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager(accounts, 0.98)
+	w := NewPoolWatcher(m, accountsDir, list)
+	if changed, err := w.Poll(); err != nil || changed {
+		t.Fatalf("Poll with nothing changed = %v, %v; want false", changed, err)
+	}
+
+	// A login added from the Settings menu joins the running pool.
+	writeCreds(t, accountsDir, "b", "tok-B", "r-B", 1000)
+	writeList(t, list, "A:a\nB:b\n")
+	if changed, err := w.Poll(); err != nil || !changed {
+		t.Fatalf("Poll after adding b = %v, %v; want true", changed, err)
+	}
+	if m.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", m.Len())
+	}
+
+	// A token refreshed by another process is picked up.
+	credPath := filepath.Join(accountsDir, "a", ".credentials.json")
+	writeCreds(t, accountsDir, "a", "tok-A2", "r-A2", 2000)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(credPath, later, later)
+	if changed, _ := w.Poll(); !changed {
+		t.Fatal("credentials change should trigger a reload")
+	}
+	if got := m.AccountAt(0).AccessToken; got != "tok-A2" {
+		t.Errorf("token = %q, want tok-A2", got)
+	}
+}
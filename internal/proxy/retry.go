@@ -0,0 +1,85 @@
+package proxy
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// RetryClass says how the proxy reacts to an upstream status before relaying it.
+type RetryClass int
+
+const (
+	// RetryNone relays the response as-is.
+	RetryNone RetryClass = iota
+	// RetryBackoff waits a jittered, exponentially growing delay and retries on
+	// the same account — for transient upstream-wide conditions (529 overloaded)
+	// where switching accounts would only throw away the prompt cache.
+	RetryBackoff
+	// RetryFailover retries immediately on another account, as for a transport
+	// error. The failing account is only skipped for the current request.
+	RetryFailover
+)
+
+// statusOverloaded is Anthropic's non-standard 529 overloaded_error status.
+const statusOverloaded = 529
+
+// RetryPolicy configures how non-429 upstream errors are retried. All retries
+// happen before any response bytes reach the client; once the budget is spent
+// the last upstream response is relayed unchanged.
+type RetryPolicy struct {
+	// Classes maps an upstream status code to its retry class; unlisted codes
+	// are RetryNone. 429 is always handled by the quota/throttle path instead.
+	Classes map[int]RetryClass
+	// MaxAttempts bounds the attempts one request makes through RetryBackoff
+	// (the first attempt included). It is its own budget: RetryFailover, like
+	// a transport error or 429, is bounded by the pool size instead.
+	MaxAttempts int
+	// BaseDelay is the first backoff; each further retry doubles it, capped at
+	// MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultRetryPolicy backs off on 529, allowing four attempts per request, and
+// fails over on 500/502/503.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Classes: map[int]RetryClass{
+			statusOverloaded:               RetryBackoff,
+			http.StatusInternalServerError: RetryFailover,
+			http.StatusBadGateway:          RetryFailover,
+			http.StatusServiceUnavailable:  RetryFailover,
+		},
+		MaxAttempts: 4,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    8 * time.Second,
+	}
+}
+
+// WithRetryPolicy overrides DefaultRetryPolicy.
+func WithRetryPolicy(p RetryPolicy) Option { return func(s *Server) { s.retry = p } }
+
+// classify returns the retry class for an upstream status.
+func (p RetryPolicy) classify(status int) RetryClass {
+	return p.Classes[status]
+}
+
+// backoff returns the delay before the nth same-account retry (n >= 1):
+// BaseDelay·2^(n-1) capped at MaxDelay, with "equal jitter" — half fixed, half
+// random — so concurrent sessions hitting the same overload don't retry in
+// lockstep while still waiting at least half the nominal delay.
+func (p RetryPolicy) backoff(n int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < n && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
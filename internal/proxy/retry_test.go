@@ -0,0 +1,188 @@
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// retryServer builds a Server whose sleeps are recorded instead of waited.
+func retryServer(t *testing.T, mgr *Manager, upstream string, opts ...Option) (*Server, *[]time.Duration) {
+	t.Helper()
+	var slept []time.Duration
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	opts = append([]Option{
+		WithSleep(func(d time.Duration) { slept = append(slept, d) }),
+		WithNow(func() time.Time { return now }),
+	}, opts...)
+	return NewServer(mgr, "proxy-key", upstream, opts...), &slept
+}
+
+func overloaded(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusOverloaded)
+	io.WriteString(w, `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`)
+}
+
+func TestServer_retriesOverloadedOnSameAccountWithBackoff(t *testing.T) {
+	var tokens []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		if len(tokens) <= 2 {
+			overloaded(w)
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer upstream.Close()
+
+	mgr := NewManager([]Account{{Label: "A", AccessToken: "tok-A"}, {Label: "B", AccessToken: "tok-B"}}, 0.98)
+	srv, slept := retryServer(t, mgr, upstream.URL)
+	rec := doRequest(t, srv, "proxy-key", `{}`)
+
+	if rec.Code != 200 || rec.Body.String() != "ok" {
+		t.Fatalf("status = %d body = %q, want the retried 200 only", rec.Code, rec.Body.String())
+	}
+	for i, tok := range tokens {
+		if tok != "Bearer tok-A" {
+			t.Errorf("attempt %d used %q, want the same account tok-A", i+1, tok)
+		}
+	}
+	if len(*slept) != 2 {
+		t.Fatalf("slept %d times, want 2 backoffs", len(*slept))
+	}
+	p := DefaultRetryPolicy()
+	if d := (*slept)[0]; d < p.BaseDelay/2 || d > p.BaseDelay {
+		t.Errorf("first backoff = %s, want within [%s, %s]", d, p.BaseDelay/2, p.BaseDelay)
+	}
+	if d := (*slept)[1]; d < p.BaseDelay || d > 2*p.BaseDelay {
+		t.Errorf("second backoff = %s, want within [%s, %s]", d, p.BaseDelay, 2*p.BaseDelay)
+	}
+}
+
+func TestServer_overloadedRelayedOnceBudgetIsSpent(t *testing.T) {
+	calls := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		overloaded(w)
+	}))
+	defer upstream.Close()
+
+	mgr := NewManager([]Account{{Label: "A", AccessToken: "tok-A"}}, 0.98)
+	srv, slept := retryServer(t, mgr, upstream.URL)
+	rec := doRequest(t, srv, "proxy-key", `{}`)
+
+	if rec.Code != statusOverloaded {
+		t.Errorf("status = %d, want the final 529 relayed", rec.Code)
+	}
+	if max := DefaultRetryPolicy().MaxAttempts; calls != max || len(*slept) != max-1 {
+		t.Errorf("calls = %d sleeps = %d, want %d attempts and %d backoffs", calls, len(*slept), max, max-1)
+	}
+}
+
+func TestServer_failsOverOn5xxWithoutWaiting(t *testing.T) {
+	var tokens []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") == "Bearer tok-A" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, "from-B")
+	}))
+	defer upstream.Close()
+
+	mgr := NewManager([]Account{{Label: "A", AccessToken: "tok-A"}, {Label: "B", AccessToken: "tok-B"}}, 0.98)
+	srv, slept := retryServer(t, mgr, upstream.URL)
+	rec := doRequest(t, srv, "proxy-key", `{}`)
+
+	if rec.Code != 200 || rec.Body.String() != "from-B" {
+		t.Fatalf("status = %d body = %q, want B's response", rec.Code, rec.Body.String())
+	}
+	if len(tokens) != 2 || tokens[1] != "Bearer tok-B" {
+		t.Errorf("attempts = %v, want A then B", tokens)
+	}
+	if len(*slept) != 0 {
+		t.Errorf("failover should not wait, slept %v", *slept)
+	}
+	// A 5xx is not a quota signal: A must not be throttled or errored.
+	if st := mgr.Status(time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)); !st.Accounts[0].Available {
+		t.Errorf("account A should stay available after a 5xx: %+v", st.Accounts[0])
+	}
+}
+
+func TestServer_relays5xxWhenNoOtherAccount(t *testing.T) {
+	calls := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+		io.WriteString(w, "bad gateway")
+	}))
+	defer upstream.Close()
+
+	mgr := NewManager([]Account{{Label: "A", AccessToken: "tok-A"}}, 0.98)
+	srv, _ := retryServer(t, mgr, upstream.URL)
+	rec := doRequest(t, srv, "proxy-key", `{}`)
+	if rec.Code != http.StatusBadGateway || rec.Body.String() != "bad gateway" || calls != 1 {
+		t.Errorf("status = %d body = %q calls = %d, want the upstream 502 relayed after one call",
+			rec.Code, rec.Body.String(), calls)
+	}
+}
+
+func TestServer_retryClassesAreConfigurable(t *testing.T) {
+	calls := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		overloaded(w)
+	}))
+	defer upstream.Close()
+
+	mgr := NewManager([]Account{{Label: "A", AccessToken: "tok-A"}}, 0.98)
+	srv, slept := retryServer(t, mgr, upstream.URL, WithRetryPolicy(RetryPolicy{MaxAttempts: 4}))
+	rec := doRequest(t, srv, "proxy-key", `{}`)
+	if rec.Code != statusOverloaded || calls != 1 || len(*slept) != 0 {
+		t.Errorf("status = %d calls = %d sleeps = %d, want 529 relayed at once with no classes",
+			rec.Code, calls, len(*slept))
+	}
+}
+
+func TestRetryPolicy_backoffIsCapped(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 4 * time.Second}
+	for n := 1; n <= 10; n++ {
+		if d := p.backoff(n); d > p.MaxDelay {
+			t.Errorf("backoff(%d) = %s, exceeds cap %s", n, d, p.MaxDelay)
+		}
+	}
+	if d := p.backoff(10); d < p.MaxDelay/2 {
+		t.Errorf("backoff(10) = %s, want at least half the cap", d)
+	}
+}
+
+func TestServer_backoffsLeaveTheFailoverBudget(t *testing.T) {
+	var tokens []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		switch {
+		case r.Header.Get("Authorization") == "Bearer tok-B":
+			io.WriteString(w, "from-B")
+		case len(tokens) < DefaultRetryPolicy().MaxAttempts:
+			overloaded(w)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer upstream.Close()
+
+	mgr := NewManager([]Account{{Label: "A", AccessToken: "tok-A"}, {Label: "B", AccessToken: "tok-B"}}, 0.98)
+	srv, slept := retryServer(t, mgr, upstream.URL)
+	rec := doRequest(t, srv, "proxy-key", `{}`)
+
+	if rec.Code != 200 || rec.Body.String() != "from-B" {
+		t.Fatalf("status = %d body = %q, want the 503 after a full run of backoffs to fail over to B", rec.Code, rec.Body.String())
+	}
+	if want := DefaultRetryPolicy().MaxAttempts - 1; len(*slept) != want {
+		t.Errorf("slept %d times, want %d backoffs", len(*slept), want)
+	}
+}
@@ -32,7 +32,7 @@ var hopByHopHeaders = map[string]bool{
 // Server is the account-rotation reverse proxy: it authenticates the local
 // client, injects the active account's OAuth token, forwards to the Anthropic
 // upstream, learns quota from the response, and fails over to another account
-// on 429.
+// on 429. Overloaded and 5xx responses are retried per its RetryPolicy.
 type Server struct {
 	mgr           *Manager
 	proxyKey      string
@@ -42,6 +42,9 @@ type Server struct {
 	client        *http.Client
 	now           func() time.Time
 	sleep         func(time.Duration)
+	ledger        *Ledger // nil disables per-account usage accounting
+	retry         RetryPolicy
+	sessions      *Sessions // non-nil when running as the shared daemon
 
 	mitmHost string      // upstream hostname intercepted on CONNECT
 	certs    *Certs      // non-nil when MITM forward-proxy mode is enabled
@@ -64,6 +67,20 @@ func WithNow(now func() time.Time) Option { return func(s *Server) { s.now = now
 // WithSleep overrides the retry-after wait function (used in tests).
 func WithSleep(sleep func(time.Duration)) Option { return func(s *Server) { s.sleep = sleep } }
 
+// WithLedger records the usage of every relayed Messages response to l.
+func WithLedger(l *Ledger) Option { return func(s *Server) { s.ledger = l } }
+
+// WithTransport sends upstream API calls and token refreshes through rt, e.g.
+// a cassette Recorder.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(s *Server) { s.client.Transport = rt }
+}
+
+// WithMITMHost sets the hostname intercepted on CONNECT when it differs from
+// the upstream's, as when replaying cassettes from a local stand-in upstream
+// while clients still dial api.anthropic.com.
+func WithMITMHost(host string) Option { return func(s *Server) { s.mitmHost = host } }
+
 // NewServer builds a Server over the account manager.
 func NewServer(mgr *Manager, proxyKey, upstream string, opts ...Option) *Server {
 	s := &Server{
@@ -79,6 +96,7 @@ func NewServer(mgr *Manager, proxyKey, upstream string, opts ...Option) *Server
 		},
 		now:   time.Now,
 		sleep: time.Sleep,
+		retry: DefaultRetryPolicy(),
 	}
 	if h, err := hostOf(s.upstream); err == nil {
 		s.mitmHost = h
@@ -131,6 +149,15 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		s.writeError(w, http.StatusUnauthorized, "authentication_error", "Invalid proxy API key")
 		return
 	}
+	// Admin routes are answered locally, never forwarded upstream.
+	switch r.URL.Path {
+	case StatusPath:
+		s.handleStatus(w, r)
+		return
+	case AttachPath:
+		s.handleAttach(w, r)
+		return
+	}
 	s.proxyRequest(w, r)
 }
 
@@ -154,17 +181,32 @@ func (s *Server) proxyRequest(w http.ResponseWriter, r *http.Request) {
 	}
 	r.Body.Close()
 
-	// Bound total attempts by the pool size (teamclaude's maxRetries), and track
+	// Bound failovers by the pool size (teamclaude's maxRetries), and track
 	// accounts already tried/failed this request so failover skips them.
 	maxRetries := s.mgr.Len()
 	tried := map[int]bool{}
+	// Keep each conversation on one account so its prompt cache survives;
+	// failover below re-pins it when that account is throttled or exhausted.
+	convKey := conversationKey(r.Header, body)
+	// Same-account 529 backoffs have their own budget (the retry policy's
+	// MaxAttempts), so waiting out an overload never eats into failover.
+	retryCount, backoffs := 0, 0
+	stay := -1 // account a same-account (backoff) retry must reuse
 
-	for retryCount := 0; ; retryCount++ {
+	for {
 		now := s.now()
-		idx, ok := s.mgr.GetActiveAccount(tried, now)
-		if !ok {
-			s.writeExhausted(w)
-			return
+		idx := stay
+		stay = -1
+		if idx < 0 {
+			var ok bool
+			if idx, ok = s.mgr.GetAccountFor(convKey, tried, now); !ok {
+				if capped, lifts := s.mgr.AllCapped(now); capped {
+					s.writeCapped(w, now, lifts)
+					return
+				}
+				s.writeExhausted(w)
+				return
+			}
 		}
 
 		// Refresh a near-expiry token; a hard failure (dead refresh token) takes
@@ -172,6 +214,7 @@ func (s *Server) proxyRequest(w http.ResponseWriter, r *http.Request) {
 		if !s.ensureFreshToken(idx, now) {
 			tried[idx] = true
 			if retryCount < maxRetries {
+				retryCount++
 				continue
 			}
 			s.writeExhausted(w)
@@ -187,6 +230,7 @@ func (s *Server) proxyRequest(w http.ResponseWriter, r *http.Request) {
 			log.Printf("[wisp-deck-proxy] upstream error on %q: %v", acct.Label, err)
 			tried[idx] = true
 			if retryCount < maxRetries {
+				retryCount++
 				continue
 			}
 			s.writeError(w, http.StatusBadGateway, "proxy_error", "Upstream error: "+err.Error())
@@ -209,6 +253,7 @@ func (s *Server) proxyRequest(w http.ResponseWriter, r *http.Request) {
 				// Another account can serve this request — switch to it now, with
 				// no wait, so the session keeps working seamlessly.
 				log.Printf("[wisp-deck-proxy] 429 on %q — throttling %s and switching accounts", acct.Label, retryAfter)
+				retryCount++
 				continue
 			}
 			// No other account is available. Wait out a bounded retry-after on the
@@ -223,14 +268,65 @@ func (s *Server) proxyRequest(w http.ResponseWriter, r *http.Request) {
 			if r.Context().Err() != nil { // client disconnected during the wait
 				return
 			}
+			retryCount++
 			continue
 		}
 
-		s.relay(w, resp)
+		// Overloaded and 5xx responses are retried per the retry policy while
+		// their budget lasts — backoffs up to MaxAttempts, failovers like any
+		// other; nothing has been written to the client yet, so a retry is
+		// invisible to it. Once the budget is spent the response is relayed as-is.
+		switch s.retry.classify(resp.StatusCode) {
+		case RetryBackoff:
+			if backoffs+1 < s.retry.MaxAttempts {
+				backoffs++
+				wait := s.retry.backoff(backoffs)
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				log.Printf("[wisp-deck-proxy] %d on %q — retrying the same account in %s", resp.StatusCode, acct.Label, wait)
+				s.sleep(wait)
+				if r.Context().Err() != nil { // client disconnected during the wait
+					return
+				}
+				stay = idx
+				continue
+			}
+		case RetryFailover:
+			tried[idx] = true
+			if retryCount < maxRetries && s.mgr.HasAvailableExcept(tried, now) {
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				log.Printf("[wisp-deck-proxy] %d on %q — failing over to another account", resp.StatusCode, acct.Label)
+				retryCount++
+				continue
+			}
+		}
+
+		s.relay(w, resp, s.usageTap(resp, idx, acct))
 		return
 	}
 }
 
+// usageTap returns a tap that counts the response's output tokens toward the
+// account's daily cap and appends its usage to the ledger (when enabled), or nil
+// when the response cannot carry usage.
+func (s *Server) usageTap(resp *http.Response, idx int, acct Account) *usageTap {
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+	stream := strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream")
+	base := LedgerEntry{Time: s.now().UTC(), Account: acct.Label, Dir: acct.Dir}
+	return newUsageTap(stream, base, func(e LedgerEntry) {
+		s.mgr.RecordOutput(idx, e.Output, s.now())
+		if s.ledger == nil {
+			return
+		}
+		if err := s.ledger.Append(e); err != nil {
+			log.Printf("[wisp-deck-proxy] could not record usage for %q: %v", acct.Label, err)
+		}
+	})
+}
+
 // writeExhausted reports that every account is rate-limited, mirroring
 // teamclaude's structured rate_limit_error response.
 func (s *Server) writeExhausted(w http.ResponseWriter) {
@@ -239,6 +335,20 @@ func (s *Server) writeExhausted(w http.ResponseWriter) {
 		fmt.Sprintf("All %d accounts exhausted. Retry in 60s.", s.mgr.Len()))
 }
 
+// writeCapped reports that every account has reached one of its configured
+// caps. It is shaped like the exhausted error so clients back off the same way,
+// with retry-after pointing at the earliest time a cap lifts.
+func (s *Server) writeCapped(w http.ResponseWriter, now, lifts time.Time) {
+	retry, when := 60, "later"
+	if !lifts.IsZero() && lifts.After(now) {
+		retry = int(lifts.Sub(now).Seconds()) + 1
+		when = "in " + lifts.Sub(now).Round(time.Minute).String()
+	}
+	w.Header().Set("retry-after", strconv.Itoa(retry))
+	s.writeError(w, http.StatusTooManyRequests, "rate_limit_error",
+		fmt.Sprintf("All %d accounts have reached their configured usage caps. The first cap lifts %s.", s.mgr.Len(), when))
+}
+
 // writeError writes an Anthropic-style error envelope.
 func (s *Server) writeError(w http.ResponseWriter, status int, errType, message string) {
 	w.Header().Set("Content-Type", "application/json")
@@ -297,7 +407,7 @@ func (s *Server) ensureFreshToken(idx int, now time.Time) bool {
 		return true
 	}
 	acct := s.mgr.AccountAt(idx)
-	tok, err := RefreshToken(s.tokenEndpoint, acct.RefreshToken)
+	tok, err := refreshTokenVia(s.client.Transport, s.tokenEndpoint, acct.RefreshToken)
 	if err != nil {
 		log.Printf("[wisp-deck-proxy] token refresh failed for %q: %v", acct.Label, err)
 		s.mgr.MarkErrored(idx)
@@ -340,7 +450,9 @@ func (s *Server) forward(r *http.Request, body []byte, token string) (*http.Resp
 }
 
 // relay copies an upstream response back to the client, streaming the body.
-func (s *Server) relay(w http.ResponseWriter, resp *http.Response) {
+// A non-nil tap sees each chunk only after it has been written and flushed, so
+// usage accounting never adds latency to the stream.
+func (s *Server) relay(w http.ResponseWriter, resp *http.Response, tap *usageTap) {
 	defer resp.Body.Close()
 	for k, vv := range resp.Header {
 		lk := strings.ToLower(k)
@@ -366,11 +478,17 @@ func (s *Server) relay(w http.ResponseWriter, resp *http.Response) {
 			if flusher != nil {
 				flusher.Flush()
 			}
+			if tap != nil {
+				tap.Write(buf[:n])
+			}
 		}
 		if err != nil {
 			break
 		}
 	}
+	if tap != nil {
+		tap.finish()
+	}
 }
 
 // relayRaw forwards a request to the upstream with no token rewriting — a pure
@@ -401,7 +519,7 @@ func (s *Server) relayRaw(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadGateway)
 		return
 	}
-	s.relay(w, resp)
+	s.relay(w, resp, nil)
 }
 
 // hostOf returns the hostname of a base URL (e.g. https://api.anthropic.com →
@@ -8,6 +8,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/jackuait/wisp-deck/internal/claudeaccount"
 )
 
 func newTestServer(t *testing.T, mgr *Manager, upstream string) *Server {
@@ -297,3 +299,134 @@ func TestServer_passesThrough429WhenAllExhausted(t *testing.T) {
 		t.Error("exhausted response should carry a retry-after header")
 	}
 }
+
+// doConversation sends a Messages request tagged with a Claude Code style
+// metadata.user_id, so the proxy can derive its conversation key.
+func doConversation(t *testing.T, srv *Server, session string) *httptest.ResponseRecorder {
+	t.Helper()
+	return doRequest(t, srv, "proxy-key", `{"metadata":{"user_id":"user_h_account_a_session_`+session+`"}}`)
+}
+
+func TestServer_conversationStaysOnItsAccountAfterAnotherFailsOver(t *testing.T) {
+	// conv-1 starts on A. conv-2 hits a 429 on A and fails over to B, moving the
+	// pool's current account. Once A's throttle lapses, conv-1 must go back to A
+	// (its prompt cache lives there) rather than follow the pool to B.
+	failA := true
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer tok-A" && failA {
+			w.Header().Set("retry-after", "10")
+			w.WriteHeader(429)
+			return
+		}
+		io.WriteString(w, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	}))
+	defer upstream.Close()
+
+	clk := time.Unix(1_700_000_000, 0)
+	mgr := NewManager([]Account{{Label: "A", AccessToken: "tok-A"}, {Label: "B", AccessToken: "tok-B"}}, 0.98)
+	srv := NewServer(mgr, "proxy-key", upstream.URL,
+		WithNow(func() time.Time { return clk }), WithSleep(func(time.Duration) {}))
+
+	failA = false
+	if rec := doConversation(t, srv, "one"); rec.Body.String() != "tok-A" {
+		t.Fatalf("conv-1 first turn served by %q, want tok-A", rec.Body.String())
+	}
+	failA = true
+	if rec := doConversation(t, srv, "two"); rec.Body.String() != "tok-B" {
+		t.Fatalf("conv-2 should fail over to tok-B, got %q", rec.Body.String())
+	}
+	failA = false
+	clk = clk.Add(11 * time.Second)
+	if rec := doConversation(t, srv, "one"); rec.Body.String() != "tok-A" {
+		t.Errorf("conv-1 should stay pinned to tok-A, got %q", rec.Body.String())
+	}
+	if rec := doConversation(t, srv, "two"); rec.Body.String() != "tok-B" {
+		t.Errorf("conv-2 should stay pinned to tok-B, got %q", rec.Body.String())
+	}
+}
+
+func TestServer_pinnedConversationMovesWhenItsAccountCrossesThreshold(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer tok-A" {
+			w.Header().Set("anthropic-ratelimit-unified-5h-utilization", "0.99")
+		}
+		io.WriteString(w, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	}))
+	defer upstream.Close()
+
+	mgr := NewManager([]Account{{Label: "A", AccessToken: "tok-A"}, {Label: "B", AccessToken: "tok-B"}}, 0.98)
+	srv := newTestServer(t, mgr, upstream.URL)
+
+	if rec := doConversation(t, srv, "one"); rec.Body.String() != "tok-A" {
+		t.Fatalf("first turn served by %q, want tok-A", rec.Body.String())
+	}
+	// The first response reported A at 99% — over the 98% threshold — so the
+	// conversation must move on rather than stay pinned to an exhausted account.
+	if rec := doConversation(t, srv, "one"); rec.Body.String() != "tok-B" {
+		t.Errorf("second turn served by %q, want tok-B once A is over threshold", rec.Body.String())
+	}
+}
+
+func TestServer_pinnedConversationFailsOverOn429(t *testing.T) {
+	var failA bool
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer tok-A" && failA {
+			w.Header().Set("retry-after", "30")
+			w.WriteHeader(429)
+			return
+		}
+		io.WriteString(w, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	}))
+	defer upstream.Close()
+
+	mgr := NewManager([]Account{{Label: "A", AccessToken: "tok-A"}, {Label: "B", AccessToken: "tok-B"}}, 0.98)
+	srv := newTestServer(t, mgr, upstream.URL)
+
+	doConversation(t, srv, "one") // pinned to A
+	failA = true
+	if rec := doConversation(t, srv, "one"); rec.Code != 200 || rec.Body.String() != "tok-B" {
+		t.Fatalf("got (%d, %q), want (200, tok-B) after failover", rec.Code, rec.Body.String())
+	}
+	failA = false
+	if rec := doConversation(t, srv, "one"); rec.Body.String() != "tok-B" {
+		t.Errorf("conversation should now be pinned to tok-B, got %q", rec.Body.String())
+	}
+}
+
+func TestServer_allAccountsCappedReturnsRateLimitWithLiftTime(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request should reach upstream while every account is capped")
+	}))
+	defer upstream.Close()
+
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.Local)
+	accts := []Account{{Label: "A", AccessToken: "tok-A", Caps: claudeaccount.Caps{DailyOutput: 10}}}
+	mgr := NewManager(accts, 0.98)
+	mgr.RecordOutput(0, 50, now)
+	srv := NewServer(mgr, "proxy-key", upstream.URL, WithNow(func() time.Time { return now }), WithSleep(func(time.Duration) {}))
+
+	rec := doRequest(t, srv, "proxy-key", `{}`)
+	if rec.Code != http.StatusTooManyRequests || !strings.Contains(rec.Body.String(), "configured usage caps") {
+		t.Fatalf("got %d %q, want a 429 naming the caps", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("retry-after"); got != "43201" {
+		t.Errorf("retry-after = %q, want seconds until local midnight (43201)", got)
+	}
+}
+
+func TestServer_countsStreamedOutputTowardDailyCap(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"type":"message","id":"msg_1","model":"claude-x","usage":{"input_tokens":5,"output_tokens":40}}`)
+	}))
+	defer upstream.Close()
+
+	mgr := NewManager([]Account{{Label: "A", AccessToken: "tok-A", Caps: claudeaccount.Caps{DailyOutput: 30}}}, 0.98)
+	srv := newTestServer(t, mgr, upstream.URL)
+	if rec := doRequest(t, srv, "proxy-key", `{}`); rec.Code != 200 {
+		t.Fatalf("first request status = %d", rec.Code)
+	}
+	if st := mgr.Status(time.Now()); st.Accounts[0].OutputToday != 40 || st.Accounts[0].Capped == "" {
+		t.Errorf("status = %+v, want 40 output tokens counted and the account capped", st.Accounts[0])
+	}
+}
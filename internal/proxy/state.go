@@ -0,0 +1,160 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"time"
+)
+
+// StateFile is the name of the quota/throttle snapshot the proxy keeps in the
+// accounts dir, so a freshly started proxy knows which accounts upstream has
+// already rejected instead of rediscovering it by burning requests.
+const StateFile = "proxy-state.json"
+
+// persistedState is the on-disk snapshot, keyed by account Dir (stable across
+// list reorders and relabels, unlike the pool index).
+type persistedState struct {
+	Accounts map[string]persistedAccount `json:"accounts"`
+}
+
+type persistedAccount struct {
+	U5h            float64   `json:"u5h"`
+	U7d            float64   `json:"u7d"`
+	U5hReset       time.Time `json:"u5hReset,omitzero"`
+	U7dReset       time.Time `json:"u7dReset,omitzero"`
+	Status         string    `json:"status,omitempty"`
+	ThrottledUntil time.Time `json:"throttledUntil,omitzero"`
+	Day            string    `json:"day,omitempty"` // local day DayOutput counts
+	DayOutput      int64     `json:"dayOutput,omitempty"`
+}
+
+// LoadState restores quota and throttle windows from the snapshot at path and
+// makes path the target of future saves. Windows that are already over (or
+// carry no reset time, so their age is unknown) are discarded. A missing file
+// is not an error — the pool simply starts unknown, as before.
+func (m *Manager) LoadState(path string, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statePath = path
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var ps persistedState
+	if err := json.Unmarshal(data, &ps); err != nil {
+		return err
+	}
+	for _, a := range m.accounts {
+		p, ok := ps.Accounts[a.Dir]
+		if !ok || a.Dir == "" {
+			continue
+		}
+		if p.U5hReset.After(now) {
+			a.q.u5h, a.q.u5hReset, a.q.status = p.U5h, p.U5hReset, p.Status
+		}
+		if p.U7dReset.After(now) {
+			a.q.u7d, a.q.u7dReset = p.U7d, p.U7dReset
+		}
+		if p.ThrottledUntil.After(now) {
+			a.throttledUntil = p.ThrottledUntil
+		}
+		if p.Day == dayKey(now) {
+			a.day, a.dayOutput = p.Day, p.DayOutput
+		}
+	}
+	m.saveMu.Lock()
+	m.saved = data
+	m.saveMu.Unlock()
+	return nil
+}
+
+// snapshotLocked renders the persistable part of the pool. Caller holds m.mu.
+func (m *Manager) snapshotLocked() ([]byte, error) {
+	ps := persistedState{Accounts: map[string]persistedAccount{}}
+	for _, a := range m.accounts {
+		if a.Dir == "" {
+			continue
+		}
+		ps.Accounts[a.Dir] = persistedAccount{
+			U5h:            a.q.u5h,
+			U7d:            a.q.u7d,
+			U5hReset:       a.q.u5hReset,
+			U7dReset:       a.q.u7dReset,
+			Status:         a.q.status,
+			ThrottledUntil: a.throttledUntil,
+			Day:            a.day,
+			DayOutput:      a.dayOutput,
+		}
+	}
+	return json.MarshalIndent(ps, "", "  ")
+}
+
+// SaveState writes the snapshot atomically when persistence is enabled (see
+// LoadState) and the state changed since the last write.
+func (m *Manager) SaveState() error {
+	m.mu.Lock()
+	path := m.statePath
+	if path == "" {
+		m.mu.Unlock()
+		return nil
+	}
+	data, err := m.snapshotLocked()
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Serialize writers so an older snapshot can never land after a newer one.
+	m.saveMu.Lock()
+	defer m.saveMu.Unlock()
+	if bytes.Equal(data, m.saved) {
+		return nil
+	}
+	if err := writeAtomic(path, data, 0o600); err != nil {
+		return err
+	}
+	m.saved = data
+	return nil
+}
+
+// stateFlushDelay is how long a mutation waits before the snapshot is written,
+// so a burst of responses costs one write instead of one each. A var so tests
+// can shorten it.
+var stateFlushDelay = 2 * time.Second
+
+// persist marks the state dirty after a mutation and schedules a flush
+// stateFlushDelay later, keeping the disk write off the request path. Mutations
+// landing while a flush is pending ride along with it. Must be called without
+// m.mu held.
+func (m *Manager) persist() {
+	m.mu.Lock()
+	enabled := m.statePath != ""
+	m.mu.Unlock()
+	if !enabled {
+		return
+	}
+	m.flushMu.Lock()
+	defer m.flushMu.Unlock()
+	if m.flushPending {
+		return
+	}
+	m.flushPending = true
+	time.AfterFunc(stateFlushDelay, m.FlushState)
+}
+
+// FlushState writes any pending snapshot now, logging rather than failing when
+// the write does not succeed. Call it on shutdown so the last mutations are not
+// lost with the pending timer.
+func (m *Manager) FlushState() {
+	m.flushMu.Lock()
+	m.flushPending = false
+	m.flushMu.Unlock()
+	if err := m.SaveState(); err != nil {
+		log.Printf("[wisp-deck-proxy] could not persist quota state: %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package proxy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestState_roundTripsQuotaAndThrottleByDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), StateFile)
+	now := time.Unix(1_700_000_000, 0)
+
+	m := NewManager(testAccounts(3), 0.98)
+	if err := m.LoadState(path, now); err != nil {
+		t.Fatalf("missing state file should not error: %v", err)
+	}
+	m.UpdateQuota(0, hdr(
+		"anthropic-ratelimit-unified-5h-utilization", "0.99",
+		"anthropic-ratelimit-unified-5h-reset", itoa(now.Add(time.Hour).Unix()),
+		"anthropic-ratelimit-unified-status", "rejected",
+	))
+	m.MarkThrottled(1, now.Add(10*time.Minute))
+	if _, err := os.Stat(path); err == nil {
+		t.Fatal("state should not be written on the request path")
+	}
+	m.FlushState()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("state should be written on flush: %v", err)
+	}
+
+	// A fresh proxy over the same accounts, listed in a different order.
+	accts := testAccounts(3)
+	accts[0], accts[2] = accts[2], accts[0]
+	m2 := NewManager(accts, 0.98)
+	if err := m2.LoadState(path, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if got := m2.SelectBest(now.Add(time.Minute)); got != 0 {
+		t.Errorf("SelectBest = %d, want 0 (the only account neither rejected nor throttled)", got)
+	}
+	if m2.isAvailable(2, now.Add(time.Minute)) {
+		t.Error("rejected account (dir a) should stay sidelined after restart")
+	}
+	if m2.isAvailable(1, now.Add(time.Minute)) {
+		t.Error("throttled account (dir b) should stay throttled after restart")
+	}
+}
+
+func TestState_discardsExpiredWindows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), StateFile)
+	now := time.Unix(1_700_000_000, 0)
+
+	m := NewManager(testAccounts(2), 0.98)
+	m.LoadState(path, now)
+	m.UpdateQuota(0, hdr(
+		"anthropic-ratelimit-unified-5h-utilization", "0.99",
+		"anthropic-ratelimit-unified-5h-reset", itoa(now.Add(time.Hour).Unix()),
+		"anthropic-ratelimit-unified-7d-utilization", "0.40",
+		"anthropic-ratelimit-unified-7d-reset", itoa(now.Add(72*time.Hour).Unix()),
+	))
+	m.MarkThrottled(1, now.Add(time.Minute))
+	m.FlushState()
+
+	m2 := NewManager(testAccounts(2), 0.98)
+	later := now.Add(2 * time.Hour)
+	if err := m2.LoadState(path, later); err != nil {
+		t.Fatal(err)
+	}
+	st := m2.Status(later)
+	if a := st.Accounts[0]; a.Util5h != -1 || a.Util7d != 0.40 {
+		t.Errorf("account 0 = %+v, want 5h discarded (reset passed) and 7d kept", a)
+	}
+	if b := st.Accounts[1]; !b.ThrottledUntil.IsZero() || !b.Available {
+		t.Errorf("account 1 = %+v, want throttle discarded", b)
+	}
+}
+
+func TestState_flushIsDebounced(t *testing.T) {
+	defer func(d time.Duration) { stateFlushDelay = d }(stateFlushDelay)
+	stateFlushDelay = 20 * time.Millisecond
+	path := filepath.Join(t.TempDir(), StateFile)
+
+	m := NewManager(testAccounts(2), 0.98)
+	m.LoadState(path, time.Now())
+	m.MarkThrottled(0, time.Now().Add(time.Hour))
+	m.MarkThrottled(1, time.Now().Add(time.Hour))
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("pending state was never flushed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	m2 := NewManager(testAccounts(2), 0.98)
+	m2.LoadState(path, time.Now())
+	if m2.isAvailable(0, time.Now()) || m2.isAvailable(1, time.Now()) {
+		t.Error("one flush should carry both mutations")
+	}
+}
+
+func TestState_disabledWithoutLoadState(t *testing.T) {
+	// Account dirs are real directories here, so a stray save would land
+	// next to them.
+	dir := t.TempDir()
+	accts := testAccounts(1)
+	accts[0].Dir = filepath.Join(dir, "a")
+	os.Mkdir(accts[0].Dir, 0o700)
+
+	m := NewManager(accts, 0.98)
+	m.MarkThrottled(0, time.Now().Add(time.Hour))
+	m.FlushState()
+	if m.statePath != "" {
+		t.Errorf("statePath = %q, want persistence off", m.statePath)
+	}
+	for _, d := range []string{dir, accts[0].Dir} {
+		if _, err := os.Stat(filepath.Join(d, StateFile)); err == nil {
+			t.Errorf("nothing should be written without a state path, found %s in %s", StateFile, d)
+		}
+	}
+	if m.flushPending {
+		t.Error("no flush should be scheduled without a state path")
+	}
+}
+
+func TestState_corruptFileIsAnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), StateFile)
+	os.WriteFile(path, []byte("{not json"), 0o600)
+	m := NewManager(testAccounts(1), 0.98)
+	if err := m.LoadState(path, time.Now()); err == nil {
+		t.Error("corrupt state should be reported")
+	}
+}
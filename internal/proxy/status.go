@@ -0,0 +1,130 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// StatusPath is the admin route that reports the live pool state. It is served
+// on the proxy's own listener and guarded by the same proxy key as API traffic.
+const StatusPath = "/_wisp/status"
+
+// AccountStatus is one account's observable state in a PoolStatus snapshot.
+// Utilization fields are -1 when upstream has not reported them yet; zero times
+// mean unknown / not set.
+type AccountStatus struct {
+	Label          string    `json:"label"`
+	Dir            string    `json:"dir"`
+	Active         bool      `json:"active"`
+	Available      bool      `json:"available"`
+	Util5h         float64   `json:"util5h"`
+	Util7d         float64   `json:"util7d"`
+	Reset5h        time.Time `json:"reset5h,omitzero"`
+	Reset7d        time.Time `json:"reset7d,omitzero"`
+	Status         string    `json:"status,omitempty"` // allowed | allowed_warning | rejected
+	ThrottledUntil time.Time `json:"throttledUntil,omitzero"`
+	Errored        bool      `json:"errored"`
+	TokenExpiresAt int64     `json:"tokenExpiresAt,omitempty"` // ms epoch
+	Conversations  int       `json:"conversations"`            // conversations pinned here
+	Caps           string    `json:"caps,omitempty"`           // configured caps, claudeaccount.ParseCaps form
+	Capped         string    `json:"capped,omitempty"`         // why a cap holds it back; "" if not
+	OutputToday    int64     `json:"outputToday"`              // output tokens served today
+}
+
+// PoolStatus is the JSON document served at StatusPath.
+type PoolStatus struct {
+	Now       time.Time       `json:"now"`
+	Threshold float64         `json:"threshold"`
+	Policy    string          `json:"policy"`
+	Active    int             `json:"active"` // index into Accounts; -1 when none
+	Accounts  []AccountStatus `json:"accounts"`
+}
+
+// Status snapshots every account's quota, throttle and error state as of now.
+// Expired windows are cleared first so the snapshot matches what the next
+// request would see.
+func (m *Manager) Status(now time.Time) PoolStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := PoolStatus{Now: now, Threshold: m.threshold, Policy: m.policy.Name(), Active: -1}
+	st.Accounts = make([]AccountStatus, 0, len(m.accounts))
+	m.pruneAffinity(now)
+	pinned := map[int]int{}
+	for _, p := range m.affinity {
+		pinned[p.idx]++
+	}
+	for i, a := range m.accounts {
+		if a.removed {
+			continue
+		}
+		avail := m.isAvailable(i, now)
+		var outToday int64
+		if a.day == dayKey(now) {
+			outToday = a.dayOutput
+		}
+		if i == m.current {
+			st.Active = len(st.Accounts)
+		}
+		st.Accounts = append(st.Accounts, AccountStatus{
+			Label:          a.Label,
+			Dir:            a.Dir,
+			Active:         i == m.current,
+			Available:      avail,
+			Util5h:         a.q.u5h,
+			Util7d:         a.q.u7d,
+			Reset5h:        a.q.u5hReset,
+			Reset7d:        a.q.u7dReset,
+			Status:         a.q.status,
+			ThrottledUntil: a.throttledUntil,
+			Errored:        a.errored,
+			TokenExpiresAt: a.ExpiresAt,
+			Conversations:  pinned[i],
+			Caps:           a.Caps.String(),
+			Capped:         m.capReason(i, now),
+			OutputToday:    outToday,
+		})
+	}
+	return st
+}
+
+// handleStatus serves the pool snapshot. Only GET is allowed; the caller has
+// already authenticated the request.
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		s.writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "Method not allowed")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(s.mgr.Status(s.now()))
+}
+
+// FetchStatus asks a running proxy at baseURL (e.g. http://127.0.0.1:PORT) for
+// its pool snapshot, authenticating with the proxy key.
+func FetchStatus(baseURL, key string) (PoolStatus, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(baseURL, "/")+StatusPath, nil)
+	if err != nil {
+		return PoolStatus{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+key)
+	client := &http.Client{Timeout: 5 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return PoolStatus{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+		return PoolStatus{}, fmt.Errorf("status request failed (%d): %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var st PoolStatus
+	if err := json.NewDecoder(res.Body).Decode(&st); err != nil {
+		return PoolStatus{}, err
+	}
+	return st, nil
+}
@@ -0,0 +1,97 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatus_reportsQuotaThrottleAndErrored(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	m := NewManager(testAccounts(3), 0.9)
+	m.UpdateQuota(0, hdr(
+		"anthropic-ratelimit-unified-5h-utilization", "0.95",
+		"anthropic-ratelimit-unified-7d-reset", itoa(now.Add(time.Hour).Unix()),
+		"anthropic-ratelimit-unified-status", "allowed_warning",
+	))
+	m.MarkThrottled(1, now.Add(time.Minute))
+	m.MarkErrored(2)
+
+	st := m.Status(now)
+	if st.Threshold != 0.9 || st.Active != 0 || len(st.Accounts) != 3 {
+		t.Fatalf("got %+v", st)
+	}
+	a := st.Accounts[0]
+	if !a.Active || a.Available || a.Util5h != 0.95 || a.Util7d != -1 || a.Status != "allowed_warning" {
+		t.Errorf("account 0 = %+v, want active, unavailable (over threshold), 5h=0.95, 7d unknown", a)
+	}
+	if !a.Reset7d.Equal(now.Add(time.Hour)) {
+		t.Errorf("Reset7d = %v", a.Reset7d)
+	}
+	if b := st.Accounts[1]; b.Available || !b.ThrottledUntil.Equal(now.Add(time.Minute)) {
+		t.Errorf("account 1 = %+v, want throttled", b)
+	}
+	if c := st.Accounts[2]; c.Available || !c.Errored {
+		t.Errorf("account 2 = %+v, want errored", c)
+	}
+}
+
+func TestStatus_clearsExpiredThrottle(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	m := NewManager(testAccounts(1), 0.98)
+	m.MarkThrottled(0, now.Add(time.Minute))
+	st := m.Status(now.Add(2 * time.Minute))
+	if a := st.Accounts[0]; !a.Available || !a.ThrottledUntil.IsZero() {
+		t.Errorf("expired throttle should be cleared, got %+v", a)
+	}
+}
+
+func TestServer_statusRouteRequiresKeyAndIsServedLocally(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("status route must not be forwarded upstream")
+	}))
+	defer upstream.Close()
+
+	mgr := NewManager([]Account{{Label: "A", Dir: "a", AccessToken: "tok-A"}, {Label: "B", Dir: "b", AccessToken: "tok-B"}}, 0.98)
+	srv := newTestServer(t, mgr, upstream.URL)
+
+	req := httptest.NewRequest(http.MethodGet, StatusPath, nil)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d without key, want 401", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, StatusPath, nil)
+	req.Header.Set("Authorization", "Bearer proxy-key")
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var st PoolStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &st); err != nil {
+		t.Fatalf("not valid JSON: %v (%s)", err, rec.Body.String())
+	}
+	if len(st.Accounts) != 2 || st.Accounts[0].Label != "A" || st.Accounts[1].Dir != "b" {
+		t.Errorf("accounts = %+v", st.Accounts)
+	}
+	if strings.Contains(rec.Body.String(), "tok-A") {
+		t.Error("status must never expose access tokens")
+	}
+}
+
+func TestServer_statusRouteRejectsNonGet(t *testing.T) {
+	mgr := NewManager([]Account{{AccessToken: "tok-A"}}, 0.98)
+	srv := newTestServer(t, mgr, "http://127.0.0.1:1")
+	req := httptest.NewRequest(http.MethodPost, StatusPath, strings.NewReader("{}"))
+	req.Header.Set("x-api-key", "proxy-key")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want 405", rec.Code)
+	}
+}
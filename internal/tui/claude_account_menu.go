@@ -18,7 +18,11 @@ import (
 // are addressed by accountMenuCursor: 0 = Default, 1..len = managed logins,
 // len+1 = the add row. Adding a login is fully inline — the label is typed in a
 // text input here, the account is registered in-process, and only the browser
-// `claude auth login` runs outside the alt-screen TUI (in wrapper.sh).
+// `claude auth login` runs outside the alt-screen TUI (in wrapper.sh). The same
+// input edits a managed login's rotation-proxy caps (claudeaccount.Caps), any
+// login's subscription plan for the Stats tab's plan-value view
+// (claudeaccount.Plan), and a managed login's rotation priority and weight
+// (claudeaccount.Rotation).
 
 // accountMenuAddRow returns the cursor index of the "Add new login…" row.
 func (m *MainMenuModel) accountMenuAddRow() int { return len(m.claudeAccounts) + 1 }
@@ -33,8 +37,60 @@ func (m *MainMenuModel) openAccountMenu() {
 	m.accountMenuConfirm = false
 	m.accountMenuInputMode = false
 	m.accountMenuRenameRow = -1
+	m.accountMenuCapsRow = -1
+	m.accountMenuPlanRow = -1
+	m.accountMenuRotRow = -1
 	m.accountMenuErr = nil
 	m.accountMenuHover = -1
+	m.loadAccountMenuCaps()
+	m.loadAccountMenuPlans()
+	m.loadAccountMenuRotations()
+}
+
+// loadAccountMenuCaps rereads the caps sidecar so the panel shows each login's
+// current proxy caps. A missing or unreadable sidecar shows none.
+func (m *MainMenuModel) loadAccountMenuCaps() {
+	m.accountMenuCaps = nil
+	if m.claudeAccountsList == "" {
+		return
+	}
+	if caps, err := claudeaccount.LoadCaps(claudeaccount.CapsFile(m.claudeAccountsList)); err == nil {
+		m.accountMenuCaps = caps
+	}
+}
+
+// loadAccountMenuPlans rereads the plans sidecar so the panel shows each
+// login's subscription. A missing or unreadable sidecar shows none.
+func (m *MainMenuModel) loadAccountMenuPlans() {
+	m.accountMenuPlans = nil
+	if m.claudeAccountsList == "" {
+		return
+	}
+	if plans, err := claudeaccount.LoadPlans(claudeaccount.PlansFile(m.claudeAccountsList)); err == nil {
+		m.accountMenuPlans = plans
+	}
+}
+
+// loadAccountMenuRotations rereads the rotation sidecar so the panel shows each
+// login's place in the proxy's rotation. A missing or unreadable sidecar shows
+// the default for all.
+func (m *MainMenuModel) loadAccountMenuRotations() {
+	m.accountMenuRots = nil
+	if m.claudeAccountsList == "" {
+		return
+	}
+	if rots, err := claudeaccount.LoadRotations(claudeaccount.RotationFile(m.claudeAccountsList)); err == nil {
+		m.accountMenuRots = rots
+	}
+}
+
+// accountMenuDir returns the plans-sidecar key of login row: "default" for the
+// implicit Default login (row 0), else the managed login's dir.
+func (m *MainMenuModel) accountMenuDir(row int) string {
+	if row == 0 {
+		return "default"
+	}
+	return m.claudeAccounts[row-1].Dir
 }
 
 // enterAccountAddInput opens the inline label text input for a new login.
@@ -46,6 +102,9 @@ func (m *MainMenuModel) enterAccountAddInput() tea.Cmd {
 	m.accountMenuInput = ti
 	m.accountMenuInputMode = true
 	m.accountMenuRenameRow = -1
+	m.accountMenuCapsRow = -1
+	m.accountMenuPlanRow = -1
+	m.accountMenuRotRow = -1
 	m.accountMenuConfirm = false
 	m.accountMenuErr = nil
 	return textinput.Blink
@@ -68,6 +127,72 @@ func (m *MainMenuModel) enterAccountRenameInput(row int) tea.Cmd {
 	m.accountMenuInput = ti
 	m.accountMenuInputMode = true
 	m.accountMenuRenameRow = row
+	m.accountMenuCapsRow = -1
+	m.accountMenuPlanRow = -1
+	m.accountMenuRotRow = -1
+	m.accountMenuConfirm = false
+	m.accountMenuErr = nil
+	return textinput.Blink
+}
+
+// enterAccountCapsInput opens the inline input prefilled with the managed login
+// at cursor row's caps spec (e.g. "7d=80,daily-out=200k"). Only managed logins
+// (1..len) rotate through the proxy, so Default has no caps.
+func (m *MainMenuModel) enterAccountCapsInput(row int) tea.Cmd {
+	if row < 1 || row > len(m.claudeAccounts) {
+		return nil
+	}
+	ti := textinput.New()
+	ti.SetValue(m.accountMenuCaps[m.claudeAccounts[row-1].Dir].String())
+	ti.Focus()
+	m.accountMenuInput = ti
+	m.accountMenuInputMode = true
+	m.accountMenuRenameRow = -1
+	m.accountMenuCapsRow = row
+	m.accountMenuPlanRow = -1
+	m.accountMenuRotRow = -1
+	m.accountMenuConfirm = false
+	m.accountMenuErr = nil
+	return textinput.Blink
+}
+
+// enterAccountPlanInput opens the inline input prefilled with the login at
+// cursor row's subscription plan (e.g. "max-20x $200"). Every login has a plan,
+// Default (row 0) included; only the add row does not.
+func (m *MainMenuModel) enterAccountPlanInput(row int) tea.Cmd {
+	if row < 0 || row > len(m.claudeAccounts) {
+		return nil
+	}
+	ti := textinput.New()
+	ti.SetValue(m.accountMenuPlans[m.accountMenuDir(row)].String())
+	ti.Focus()
+	m.accountMenuInput = ti
+	m.accountMenuInputMode = true
+	m.accountMenuRenameRow = -1
+	m.accountMenuCapsRow = -1
+	m.accountMenuPlanRow = row
+	m.accountMenuRotRow = -1
+	m.accountMenuConfirm = false
+	m.accountMenuErr = nil
+	return textinput.Blink
+}
+
+// enterAccountRotationInput opens the inline input prefilled with the managed
+// login at cursor row's rotation spec (e.g. "priority=9,weight=2"). Like caps,
+// only managed logins (1..len) rotate through the proxy.
+func (m *MainMenuModel) enterAccountRotationInput(row int) tea.Cmd {
+	if row < 1 || row > len(m.claudeAccounts) {
+		return nil
+	}
+	ti := textinput.New()
+	ti.SetValue(m.accountMenuRots[m.claudeAccounts[row-1].Dir].String())
+	ti.Focus()
+	m.accountMenuInput = ti
+	m.accountMenuInputMode = true
+	m.accountMenuRenameRow = -1
+	m.accountMenuCapsRow = -1
+	m.accountMenuPlanRow = -1
+	m.accountMenuRotRow = row
 	m.accountMenuConfirm = false
 	m.accountMenuErr = nil
 	return textinput.Blink
@@ -131,6 +256,14 @@ func (m *MainMenuModel) updateAccountMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				if m.accountMenuCursor <= len(m.claudeAccounts) {
 					return m, m.enterAccountRenameInput(m.accountMenuCursor)
 				}
+			case 'c':
+				// Caps apply to managed logins (1..len) only.
+				return m, m.enterAccountCapsInput(m.accountMenuCursor)
+			case 'p':
+				return m, m.enterAccountPlanInput(m.accountMenuCursor)
+			case 'o':
+				// Rotation order, like caps, applies to managed logins only.
+				return m, m.enterAccountRotationInput(m.accountMenuCursor)
 			case 'd':
 				// Only managed logins (1..len) are removable; Default is implicit.
 				if m.accountMenuCursor >= 1 && m.accountMenuCursor <= len(m.claudeAccounts) {
@@ -144,12 +277,15 @@ func (m *MainMenuModel) updateAccountMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 // updateAccountAddInput handles key events while typing a login label (add or
-// rename).
+// rename), caps spec, plan or rotation.
 func (m *MainMenuModel) updateAccountAddInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyEsc, tea.KeyCtrlC:
 		m.accountMenuInputMode = false
 		m.accountMenuRenameRow = -1
+		m.accountMenuCapsRow = -1
+		m.accountMenuPlanRow = -1
+		m.accountMenuRotRow = -1
 		m.accountMenuInput.Blur()
 		return m, nil
 	case tea.KeyEnter:
@@ -163,8 +299,18 @@ func (m *MainMenuModel) updateAccountAddInput(msg tea.KeyMsg) (tea.Model, tea.Cm
 
 // submitAccountInput commits the inline label field: a rename edits the label in
 // place (stays in the panel), an add registers the login and exits to run the
-// browser auth.
+// browser auth. A caps, plan or rotation edit saves the spec, where an empty
+// one clears it.
 func (m *MainMenuModel) submitAccountInput() (tea.Model, tea.Cmd) {
+	if m.accountMenuCapsRow >= 1 {
+		return m.submitAccountCaps(m.accountMenuInput.Value())
+	}
+	if m.accountMenuRotRow >= 1 {
+		return m.submitAccountRotation(m.accountMenuInput.Value())
+	}
+	if m.accountMenuPlanRow >= 0 {
+		return m.submitAccountPlan(m.accountMenuInput.Value())
+	}
 	label := strings.TrimSpace(m.accountMenuInput.Value())
 	if label == "" {
 		return m, nil // wait for a non-empty label
@@ -247,8 +393,100 @@ func (m *MainMenuModel) submitAccountRename(label string) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// submitAccountCaps parses and saves the caps for the login being edited. A spec
+// that does not parse keeps the input open with the error shown, so it can be
+// fixed in place; a running proxy picks the saved caps up on its next reload.
+func (m *MainMenuModel) submitAccountCaps(spec string) (tea.Model, tea.Cmd) {
+	idx := m.accountMenuCapsRow - 1
+	if idx < 0 || idx >= len(m.claudeAccounts) {
+		m.accountMenuInputMode = false
+		m.accountMenuCapsRow = -1
+		return m, nil
+	}
+	caps, err := claudeaccount.ParseCaps(spec)
+	if err != nil {
+		m.accountMenuErr = err
+		return m, nil
+	}
+	m.accountMenuInputMode = false
+	m.accountMenuCapsRow = -1
+	if m.claudeAccountsList == "" {
+		m.accountMenuErr = errors.New("login storage is not configured")
+		return m, nil
+	}
+	if err := claudeaccount.SaveCaps(claudeaccount.CapsFile(m.claudeAccountsList), m.claudeAccounts[idx].Dir, caps); err != nil {
+		m.accountMenuErr = err
+		return m, nil
+	}
+	m.accountMenuErr = nil
+	m.loadAccountMenuCaps()
+	return m, nil
+}
+
+// submitAccountPlan parses and saves the plan for the login being edited. A
+// spec that does not parse keeps the input open with the error shown, like a
+// caps edit.
+func (m *MainMenuModel) submitAccountPlan(spec string) (tea.Model, tea.Cmd) {
+	row := m.accountMenuPlanRow
+	if row < 0 || row > len(m.claudeAccounts) {
+		m.accountMenuInputMode = false
+		m.accountMenuPlanRow = -1
+		return m, nil
+	}
+	plan, err := claudeaccount.ParsePlan(spec)
+	if err != nil {
+		m.accountMenuErr = err
+		return m, nil
+	}
+	m.accountMenuInputMode = false
+	m.accountMenuPlanRow = -1
+	if m.claudeAccountsList == "" {
+		m.accountMenuErr = errors.New("login storage is not configured")
+		return m, nil
+	}
+	if err := claudeaccount.SavePlan(claudeaccount.PlansFile(m.claudeAccountsList), m.accountMenuDir(row), plan); err != nil {
+		m.accountMenuErr = err
+		return m, nil
+	}
+	m.accountMenuErr = nil
+	m.loadAccountMenuPlans()
+	return m, nil
+}
+
+// submitAccountRotation parses and saves the rotation for the login being
+// edited in the rotation sidecar. A spec that does not parse keeps the input open with
+// the error shown, like a caps edit; a running proxy picks the new order up on
+// its next reload.
+func (m *MainMenuModel) submitAccountRotation(spec string) (tea.Model, tea.Cmd) {
+	idx := m.accountMenuRotRow - 1
+	if idx < 0 || idx >= len(m.claudeAccounts) {
+		m.accountMenuInputMode = false
+		m.accountMenuRotRow = -1
+		return m, nil
+	}
+	rot, err := claudeaccount.ParseRotation(spec)
+	if err != nil {
+		m.accountMenuErr = err
+		return m, nil
+	}
+	m.accountMenuInputMode = false
+	m.accountMenuRotRow = -1
+	if m.claudeAccountsList == "" {
+		m.accountMenuErr = errors.New("login storage is not configured")
+		return m, nil
+	}
+	if err := claudeaccount.SaveRotation(claudeaccount.RotationFile(m.claudeAccountsList), m.claudeAccounts[idx].Dir, rot); err != nil {
+		m.accountMenuErr = err
+		return m, nil
+	}
+	m.accountMenuErr = nil
+	m.loadAccountMenuRotations()
+	return m, nil
+}
+
 // confirmRemoveAccount deletes the highlighted managed login (registry line +
-// config dir), reverting to Default if it was active, then reloads the list.
+// settings sidecar lines + config dir), reverting to Default if it was active,
+// then reloads the list.
 func (m *MainMenuModel) confirmRemoveAccount() {
 	m.accountMenuConfirm = false
 	idx := m.accountMenuCursor - 1
@@ -265,11 +503,27 @@ func (m *MainMenuModel) confirmRemoveAccount() {
 	}
 	m.SetClaudeAccounts(LoadClaudeAccountsList(m.claudeAccountsList))
 	m.SetActiveClaudeAccount(ReadActiveClaudeAccount(m.claudeAccountFile))
+	// Remove dropped the login's sidecar lines too.
+	m.loadAccountMenuCaps()
+	m.loadAccountMenuPlans()
+	m.loadAccountMenuRotations()
 	if m.accountMenuCursor > m.accountMenuAddRow() {
 		m.accountMenuCursor = m.accountMenuAddRow()
 	}
 }
 
+// rotationBadge is the login row's compact form of a rotation, e.g. "p9 ×2".
+func rotationBadge(r claudeaccount.Rotation) string {
+	var parts []string
+	if r.Priority != 0 {
+		parts = append(parts, fmt.Sprintf("p%d", r.Priority))
+	}
+	if w := r.EffectiveWeight(); w != 1 {
+		parts = append(parts, fmt.Sprintf("×%d", w))
+	}
+	return strings.Join(parts, " ")
+}
+
 // renderAccountMenuPanel draws the login-management box below the menu, using the
 // same chrome as the model-map panel.
 func (m *MainMenuModel) renderAccountMenuPanel() string {
@@ -336,6 +590,18 @@ func (m *MainMenuModel) renderAccountMenuPanel() string {
 		case dirName != "":
 			right = dimStyle.Render(dirName)
 		}
+		// Proxy caps, when set, sit just left of the status, and the plan left
+		// of them.
+		if c := m.accountMenuCaps[dirName]; !c.IsZero() {
+			right = helpStyle.Render("caps "+c.String()) + "  " + right
+		}
+		if p := m.accountMenuPlans[dirName]; !p.IsZero() {
+			right = helpStyle.Render(p.String()) + "  " + right
+		}
+		// A non-default rotation leads: the order logins are drawn from.
+		if rot := m.accountMenuRots[dirName]; !rot.IsZero() {
+			right = helpStyle.Render(rotationBadge(rot)) + "  " + right
+		}
 		return row(prefix+labelRendered, right)
 	}
 
@@ -365,15 +631,31 @@ func (m *MainMenuModel) renderAccountMenuPanel() string {
 		// to an inconsistent width that overflows the box; here the width is
 		// deterministic so pad() keeps the right border steady while typing.
 		promptStyle := lipgloss.NewStyle().Foreground(m.theme.Primary)
+		placeholder := "Work, Personal…"
+		switch {
+		case m.accountMenuCapsRow >= 1:
+			placeholder = "5h=90 7d=80 daily-out=200k"
+		case m.accountMenuRotRow >= 1:
+			placeholder = "priority=9 weight=2"
+		case m.accountMenuPlanRow >= 0:
+			placeholder = "pro, max-5x, max-20x or team $30"
+		}
 		var field string
 		if v := m.accountMenuInput.Value(); v == "" {
-			field = promptStyle.Render("❯ ") + lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Work, Personal…")
+			field = promptStyle.Render("❯ ") + lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(placeholder)
 		} else {
 			cursor := lipgloss.NewStyle().Reverse(true).Render(" ")
 			field = promptStyle.Render("❯ ") + lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render(v) + cursor
 		}
 		tag := "New login"
-		if m.accountMenuRenameRow >= 0 {
+		switch {
+		case m.accountMenuCapsRow >= 1:
+			tag = "Caps"
+		case m.accountMenuRotRow >= 1:
+			tag = "Order"
+		case m.accountMenuPlanRow >= 0:
+			tag = "Plan"
+		case m.accountMenuRenameRow >= 0:
 			tag = "Rename"
 		}
 		lines = append(lines, pad("    "+primaryBoldStyle.Render(tag)+"   "+field))
@@ -415,7 +697,14 @@ func (m *MainMenuModel) renderAccountMenuPanel() string {
 	switch {
 	case m.accountMenuInputMode:
 		verb := "⏎ create login"
-		if m.accountMenuRenameRow >= 0 {
+		switch {
+		case m.accountMenuCapsRow >= 1:
+			verb = "⏎ save caps (empty clears)"
+		case m.accountMenuRotRow >= 1:
+			verb = "⏎ save order (empty resets)"
+		case m.accountMenuPlanRow >= 0:
+			verb = "⏎ save plan (empty clears)"
+		case m.accountMenuRenameRow >= 0:
 			verb = "⏎ rename"
 		}
 		help = hints(verb, "Esc cancel")
@@ -424,11 +713,14 @@ func (m *MainMenuModel) renderAccountMenuPanel() string {
 	case m.accountMenuCursor == m.accountMenuAddRow():
 		help = hints("↑↓ move", "⏎ add login", "Esc close")
 	case m.accountMenuCursor >= 1 && m.accountMenuCursor <= len(m.claudeAccounts):
-		// On a managed login: switch, rename and remove.
-		help = hints("↑↓ move", "⏎ switch", "a add", "r rename", "d remove", "Esc close")
+		// On a managed login: switch, rename, caps, plan, order and remove ('a'
+		// still adds, ↑↓ still move and Esc still closes, but are left off to
+		// keep the hint line inside the panel).
+		help = hints("⏎ switch", "r rename", "c caps", "p plan", "o order", "d remove")
 	default:
-		// On Default: switch, add and rename (it has a label, but can't be removed).
-		help = hints("↑↓ move", "⏎ switch", "a add", "r rename", "Esc close")
+		// On Default: switch, add, rename and plan (it has a label, but can't be
+		// removed).
+		help = hints("↑↓ move", "⏎ switch", "a add", "r rename", "p plan", "Esc close")
 	}
 	lines = append(lines, pad(help))
 
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jackuait/wisp-deck/internal/claudeaccount"
 )
 
 // Enter on the focused LOGIN row opens the inline login-management panel (the
@@ -377,3 +378,174 @@ func TestAccountMenu_escCloses(t *testing.T) {
 		t.Errorf("Esc should not set a result, got %+v", r)
 	}
 }
+
+// 'c' on a managed login edits its proxy caps inline: the spec is saved to the
+// caps sidecar (the list itself is untouched), shown on the row, and an empty
+// spec clears it again.
+func TestAccountMenu_capsEditPersistsToSidecar(t *testing.T) {
+	dir := t.TempDir()
+	accountsDir := filepath.Join(dir, "claude-accounts")
+	listFile := filepath.Join(accountsDir, "claude-accounts.list")
+	if err := os.MkdirAll(filepath.Join(accountsDir, "work"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(listFile, []byte("Work:work\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	capsFile := filepath.Join(accountsDir, "claude-accounts.caps")
+
+	m := acctTestMenu("claude")
+	m.SetClaudeAccounts([]ClaudeAccount{{Label: "Work", Dir: "work"}})
+	m.SetClaudeAccountPaths(listFile, accountsDir)
+	m.openAccountMenu()
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyDown})                      // -> Work
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}}) // caps input
+	if !m.accountMenuInputMode || !strings.Contains(stripAnsi(m.renderAccountMenuPanel()), "Caps") {
+		t.Fatalf("'c' should open a caps field:\n%s", stripAnsi(m.renderAccountMenuPanel()))
+	}
+
+	m.accountMenuInput.SetValue("7d=bogus")
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.accountMenuInputMode || m.accountMenuErr == nil {
+		t.Fatalf("a bad spec should stay in the field with an error (input=%v err=%v)", m.accountMenuInputMode, m.accountMenuErr)
+	}
+
+	m.accountMenuInput.SetValue("7d=80 daily-out=200k")
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.accountMenuInputMode || m.accountMenuErr != nil {
+		t.Fatalf("a valid spec should save and leave input mode (input=%v err=%v)", m.accountMenuInputMode, m.accountMenuErr)
+	}
+	if b, _ := os.ReadFile(capsFile); string(b) != "work:7d=80,daily-out=200000\n" {
+		t.Errorf("caps sidecar = %q", string(b))
+	}
+	if b, _ := os.ReadFile(listFile); string(b) != "Work:work\n" {
+		t.Errorf("the accounts list must not change, got %q", string(b))
+	}
+	if out := stripAnsi(m.renderAccountMenuPanel()); !strings.Contains(out, "caps 7d=80,daily-out=200000") {
+		t.Errorf("the row should show its caps:\n%s", out)
+	}
+
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if got := m.accountMenuInput.Value(); got != "7d=80,daily-out=200000" {
+		t.Errorf("caps field should be prefilled, got %q", got)
+	}
+	m.accountMenuInput.SetValue("")
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyEnter})
+	if b, _ := os.ReadFile(capsFile); len(b) != 0 {
+		t.Errorf("an empty spec should clear the caps, sidecar = %q", string(b))
+	}
+}
+
+// Default never rotates through the proxy, so 'c' does nothing there.
+func TestAccountMenu_capsNotEditableOnDefault(t *testing.T) {
+	m := acctTestMenu("claude")
+	m.SetClaudeAccounts([]ClaudeAccount{{Label: "Work", Dir: "work"}})
+	m.openAccountMenu()
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if m.accountMenuInputMode {
+		t.Error("'c' on Default should not open the caps field")
+	}
+}
+
+func TestAccountMenu_planEditPersistsToSidecar(t *testing.T) {
+	dir := t.TempDir()
+	accountsDir := filepath.Join(dir, "claude-accounts")
+	listFile := filepath.Join(accountsDir, "claude-accounts.list")
+	if err := os.MkdirAll(filepath.Join(accountsDir, "work"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(listFile, []byte("Work:work\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	plansFile := filepath.Join(accountsDir, "claude-accounts.plans")
+
+	m := acctTestMenu("claude")
+	m.SetClaudeAccounts([]ClaudeAccount{{Label: "Work", Dir: "work"}})
+	m.SetClaudeAccountPaths(listFile, accountsDir)
+	m.openAccountMenu()
+	// Default has a plan too.
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	if !m.accountMenuInputMode || !strings.Contains(stripAnsi(m.renderAccountMenuPanel()), "Plan") {
+		t.Fatalf("'p' should open a plan field:\n%s", stripAnsi(m.renderAccountMenuPanel()))
+	}
+	m.accountMenuInput.SetValue("pro")
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyEnter})
+
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyDown}) // -> Work
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m.accountMenuInput.SetValue("enterprise")
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.accountMenuInputMode || m.accountMenuErr == nil {
+		t.Fatalf("an unknown plan without a price should stay in the field with an error (input=%v err=%v)", m.accountMenuInputMode, m.accountMenuErr)
+	}
+	m.accountMenuInput.SetValue("max-20x")
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.accountMenuInputMode || m.accountMenuErr != nil {
+		t.Fatalf("a valid plan should save and leave input mode (input=%v err=%v)", m.accountMenuInputMode, m.accountMenuErr)
+	}
+	if b, _ := os.ReadFile(plansFile); string(b) != "default:pro $20\nwork:max-20x $200\n" {
+		t.Errorf("plans sidecar = %q", string(b))
+	}
+	if b, _ := os.ReadFile(listFile); string(b) != "Work:work\n" {
+		t.Errorf("the accounts list must not change, got %q", string(b))
+	}
+	if out := stripAnsi(m.renderAccountMenuPanel()); !strings.Contains(out, "max-20x $200") {
+		t.Errorf("the row should show its plan:\n%s", out)
+	}
+}
+
+// 'o' on a managed login edits its rotation order inline: the spec rides on
+// the login's list line, shows on the row, and an empty spec resets it.
+func TestAccountMenu_orderEditPersistsToListLine(t *testing.T) {
+	dir := t.TempDir()
+	accountsDir := filepath.Join(dir, "claude-accounts")
+	listFile := filepath.Join(accountsDir, "claude-accounts.list")
+	if err := os.MkdirAll(filepath.Join(accountsDir, "work"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(listFile, []byte("Work:work\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := acctTestMenu("claude")
+	m.SetClaudeAccounts(LoadClaudeAccountsList(listFile))
+	m.SetClaudeAccountPaths(listFile, accountsDir)
+	m.openAccountMenu()
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	if m.accountMenuInputMode {
+		t.Fatal("'o' on Default should not open the order field")
+	}
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyDown}) // -> Work
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	if !m.accountMenuInputMode || !strings.Contains(stripAnsi(m.renderAccountMenuPanel()), "Order") {
+		t.Fatalf("'o' should open an order field:\n%s", stripAnsi(m.renderAccountMenuPanel()))
+	}
+
+	m.accountMenuInput.SetValue("weight=0")
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.accountMenuInputMode || m.accountMenuErr == nil {
+		t.Fatalf("a bad spec should stay in the field with an error (input=%v err=%v)", m.accountMenuInputMode, m.accountMenuErr)
+	}
+
+	m.accountMenuInput.SetValue("priority=9 weight=2")
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.accountMenuInputMode || m.accountMenuErr != nil {
+		t.Fatalf("a valid spec should save and leave input mode (input=%v err=%v)", m.accountMenuInputMode, m.accountMenuErr)
+	}
+	if b, _ := os.ReadFile(claudeaccount.RotationFile(listFile)); string(b) != "work:priority=9,weight=2\n" {
+		t.Errorf("rotation sidecar = %q", string(b))
+	}
+	if out := stripAnsi(m.renderAccountMenuPanel()); !strings.Contains(out, "p9 ×2") {
+		t.Errorf("the row should show its order:\n%s", out)
+	}
+
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	if got := m.accountMenuInput.Value(); got != "priority=9,weight=2" {
+		t.Errorf("order field should be prefilled, got %q", got)
+	}
+	m.accountMenuInput.SetValue("")
+	m.updateAccountMenu(tea.KeyMsg{Type: tea.KeyEnter})
+	if b, _ := os.ReadFile(claudeaccount.RotationFile(listFile)); string(b) != "" {
+		t.Errorf("an empty spec should reset the order, sidecar = %q", string(b))
+	}
+}
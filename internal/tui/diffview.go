@@ -285,7 +285,9 @@ const (
 // counting them toward width; counting each rune by its terminal cell width so a
 // double-width glyph can't overrun the band), pads the remainder with spaces,
 // and wraps the whole thing in bgSeq … reset so the band spans the row. s must
-// carry only foreground SGR (no \x1b[0m), so the band isn't cleared mid-line.
+// carry only foreground SGR (no \x1b[0m), so the band isn't cleared mid-line;
+// the one exception is emphasizeChanges' word bands, which end by re-opening
+// the row's own band.
 func tintColumn(s string, width int, bgSeq string) string {
 	if width < 0 {
 		width = 0
@@ -635,8 +637,9 @@ func ansiIsReset(seq string) bool {
 // the line short, so the rest of a long line shows up on a continuation row
 // rather than vanishing past the edge. The active foreground color escape (if
 // any) is tracked and re-opened at the start of each continuation row so a
-// mid-token wrap doesn't lose syntax highlighting. Returns one (possibly
-// empty) row for input that already fits, including the empty string.
+// mid-token wrap doesn't lose syntax highlighting; likewise an open word
+// emphasis band (see emphasizeChanges). Returns one (possibly empty) row for
+// input that already fits, including the empty string.
 func wrapColumns(s string, width int) []string {
 	if width < 1 {
 		width = 1
@@ -645,7 +648,7 @@ func wrapColumns(s string, width int) []string {
 	var rows []string
 	var b strings.Builder
 	vis := 0
-	active := ""
+	active, activeBg := "", ""
 	flush := func() {
 		rows = append(rows, b.String())
 		b.Reset()
@@ -661,9 +664,12 @@ func wrapColumns(s string, width int) []string {
 				j++
 			}
 			seq := string(rs[i:j])
-			if ansiIsReset(seq) {
+			switch {
+			case strings.HasPrefix(seq, "\x1b[48;"):
+				activeBg = seq
+			case ansiIsReset(seq):
 				active = ""
-			} else {
+			default:
 				active = seq
 			}
 			b.WriteString(seq)
@@ -673,9 +679,7 @@ func wrapColumns(s string, width int) []string {
 		rw := runewidth.RuneWidth(rs[i])
 		if vis > 0 && vis+rw > width { // wrap before a rune that would overflow this row
 			flush()
-			if active != "" {
-				b.WriteString(active)
-			}
+			b.WriteString(activeBg + active)
 		}
 		b.WriteRune(rs[i])
 		vis += rw
@@ -990,7 +994,8 @@ func diffStatus(content string) string {
 
 // NewDiffView builds the pager for the given title (the file path, shown in the
 // header) and content (the structural, uncolored diff body, which is
-// syntax-highlighted once here for display while m.content keeps the raw body).
+// syntax-highlighted and word-diffed once here for display while m.content
+// keeps the raw body).
 // The added/deleted line counts and the file status shown in the header are
 // derived from the raw content. A whole-file add/delete is shown in a single
 // (inline) view with no view switcher.
@@ -1003,7 +1008,7 @@ func NewDiffView(title, content string) DiffViewModel {
 	return DiffViewModel{
 		title:       title,
 		content:     content,
-		highlighted: highlightDiff(expanded, title),
+		highlighted: emphasizeChanges(highlightDiff(expanded, title), expanded),
 		added:       added,
 		deleted:     deleted,
 		status:      diffStatus(content),
@@ -92,6 +92,11 @@ type MainMenuResult struct {
 	TabTitle     string  `json:"tab_title,omitempty"`
 	SoundName    *string `json:"sound_name,omitempty"`
 	PanelMode    string  `json:"panel_mode,omitempty"`
+	// Session is the Claude conversation id to reopen for "resume-session";
+	// Path is then the directory the conversation was started in and Account
+	// the login whose config dir holds its transcript (usage.AccountKey).
+	Session string `json:"session,omitempty"`
+	Account string `json:"account,omitempty"`
 }
 
 // MenuTab identifies which top-level tab is active.
@@ -324,6 +329,18 @@ type MainMenuModel struct {
 	autoSwitch     string // "on" or "off"
 	autoSwitchFile string // flag file path for persistence
 
+	// Rotation policy the proxy uses to pick accounts (claudeaccount.PolicyNames),
+	// stored in its own single-value file read by wrapper.sh.
+	rotationPolicy     string
+	rotationPolicyFile string
+
+	// Monthly spend budget, the settings file's "budget" key (see
+	// usage.ParseBudget). budgetAlert is the most-spent cap past a threshold,
+	// shown in the header; budgetAlertOK is false while no cap has crossed one.
+	budget        usage.Budget
+	budgetAlert   usage.BudgetStatus
+	budgetAlertOK bool
+
 	// Login-management panel, opened from the LOGIN row (mirrors the model-map
 	// panel that Plan opens). Lists Default + managed logins + an add row.
 	accountMenuOpen      bool
@@ -331,7 +348,13 @@ type MainMenuModel struct {
 	accountMenuConfirm   bool // delete confirmation showing for the cursor login
 	accountMenuInputMode bool // inline label entry (add or rename) is showing
 	accountMenuInput     textinput.Model
-	accountMenuRenameRow int // -1 = adding a login; 0 = renaming Default; 1..len = renaming a managed login (cursor row)
+	accountMenuRenameRow int                               // -1 = adding a login; 0 = renaming Default; 1..len = renaming a managed login (cursor row)
+	accountMenuCapsRow   int                               // 1..len = editing that managed login's proxy caps; -1 otherwise
+	accountMenuCaps      map[string]claudeaccount.Caps     // dir → caps from the list's caps sidecar
+	accountMenuPlanRow   int                               // 0..len = editing that login's subscription plan; -1 otherwise
+	accountMenuRotRow    int                               // 1..len = editing that managed login's rotation priority/weight; -1 otherwise
+	accountMenuPlans     map[string]claudeaccount.Plan     // dir ("default" for Default) → plan from the plans sidecar
+	accountMenuRots      map[string]claudeaccount.Rotation // dir → rotation from the rotation sidecar
 	accountMenuErr       error
 
 	// Model mapping panel for non-Standard configs
@@ -354,12 +377,29 @@ type MainMenuModel struct {
 	// confirmation. -1 means no confirmation is active.
 	staleConfirmIdx int
 
-	// Cached stats data for the embedded Stats tab.
-	statsLoaded  bool
-	statsLoading bool
-	statsMonths  []usage.MonthlyUsage
-	statsErr     error
-	statsOffset  int
+	// stats is the Stats tab's live usage, the same model the standalone Stats
+	// screen runs: it loads on the tab's first showing and then rescans through
+	// its watcher every statsRefreshInterval while the tab is shown. Its cursor
+	// and crumbs drive the monthly view's drill-down. statsIdle is set when a
+	// rescan came due with the tab hidden, so showing the tab again resumes
+	// them.
+	stats     StatsModel
+	statsIdle bool
+	// statsView cycles the Stats body between the monthly table, the most
+	// expensive conversations and each account's rolling 5-hour window;
+	// statsSessionSel is the highlighted conversation.
+	statsView       statsView
+	statsSessionSel int
+	statsBlocks     []usage.BlockStatus
+	// statsAccounts is all usage grouped by account key, largest first;
+	// statsAccountLabels resolves the keys (see usage.AccountLabel).
+	statsAccounts      []usage.GroupUsage
+	statsAccountLabels map[string]string
+	statsTools         usage.ToolSummary
+	statsCache         statsCacheData
+	// statsValues is each subscribed account's API-equivalent value by month,
+	// newest first (see usage.PlanValues).
+	statsValues []usage.PlanValue
 }
 
 // NewMainMenu creates a new main menu model.
@@ -394,6 +434,10 @@ func NewMainMenu(projects []models.Project, aiTools []string, currentAI string,
 		modelMapHover:             -1,
 		modelMapSlotHover:         -1,
 		accountMenuHover:          -1,
+		accountMenuRenameRow:      -1,
+		accountMenuCapsRow:        -1,
+		accountMenuRotRow:         -1,
+		accountMenuPlanRow:        -1,
 	}
 }
 
@@ -1236,7 +1280,8 @@ func (m *MainMenuModel) CycleTab(direction string) {
 
 // settingsItemCount returns the number of settings rows: 6 base (Ghost, Tab,
 // Sound, Panel, Theme, Dir) + the Plan row when the Claude config control is
-// visible + the always-present Login row + the Auto-switch toggle.
+// visible + the always-present Login row + the Auto-switch toggle + the
+// Rotation policy picker.
 func (m *MainMenuModel) settingsItemCount() int {
 	n := 6
 	if m.ClaudeConfigVisible() {
@@ -1244,14 +1289,22 @@ func (m *MainMenuModel) settingsItemCount() int {
 	}
 	n++ // Login
 	n++ // Auto-switch accounts
+	n++ // Rotation policy
+	n++ // Monthly budget
 	return n
 }
 
 // loginRowIndex is the fixed index of the Login row (Plan is always present).
-func (m *MainMenuModel) loginRowIndex() int { return m.settingsItemCount() - 2 }
+func (m *MainMenuModel) loginRowIndex() int { return m.settingsItemCount() - 4 }
+
+// autoSwitchRowIndex is the index of the Auto-switch toggle.
+func (m *MainMenuModel) autoSwitchRowIndex() int { return m.settingsItemCount() - 3 }
+
+// rotationPolicyRowIndex is the index of the Rotation policy picker.
+func (m *MainMenuModel) rotationPolicyRowIndex() int { return m.settingsItemCount() - 2 }
 
-// autoSwitchRowIndex is the index of the Auto-switch toggle (last row).
-func (m *MainMenuModel) autoSwitchRowIndex() int { return m.settingsItemCount() - 1 }
+// budgetRowIndex is the index of the Monthly budget row (last row).
+func (m *MainMenuModel) budgetRowIndex() int { return m.settingsItemCount() - 1 }
 
 // SetAutoSwitchFile records the on/off flag file and loads its current value.
 func (m *MainMenuModel) SetAutoSwitchFile(path string) {
@@ -1275,6 +1328,55 @@ func (m *MainMenuModel) CycleAutoSwitch() {
 	}
 }
 
+// submitBudget parses the budget spec typed in the settings input, persists it
+// to the settings file's "budget" key (clearing it when empty) and re-evaluates
+// the header alert. A spec that does not parse keeps the input open.
+func (m *MainMenuModel) submitBudget(spec string) (tea.Model, tea.Cmd) {
+	b, err := usage.ParseBudget(spec)
+	if err != nil {
+		m.settingsInputErr = err
+		return m, nil
+	}
+	m.budget = b
+	m.persistSetting("budget", b.String())
+	m.settingsInputMode = false
+	m.settingsInput.Blur()
+	if b.IsZero() {
+		m.budgetAlert, m.budgetAlertOK = usage.BudgetStatus{}, false
+	}
+	return m, m.budgetCmd()
+}
+
+// budgetEvaluatedMsg carries this month's budget alert, if a cap has crossed
+// a threshold.
+type budgetEvaluatedMsg struct {
+	alert usage.BudgetStatus
+	ok    bool
+}
+
+// budgetCmd evaluates the budget against this month's usage in the background,
+// so the header can warn of overspend without opening the Stats tab. Returns
+// nil when no budget is set.
+func (m *MainMenuModel) budgetCmd() tea.Cmd {
+	if m.budget.IsZero() {
+		return nil
+	}
+	b := m.budget
+	return func() tea.Msg {
+		home, _ := os.UserHomeDir()
+		if err := usage.LoadPricing(usage.PricingPath(home)); err != nil {
+			return nil
+		}
+		_, _, cachePath := usage.DefaultPaths(home)
+		records, err := usage.Collect(usage.DefaultSources(home), cachePath)
+		if err != nil {
+			return nil
+		}
+		alert, ok := usage.BudgetAlert(usage.EvaluateBudget(records, b, time.Now().UTC().Format("2006-01")))
+		return budgetEvaluatedMsg{alert: alert, ok: ok}
+	}
+}
+
 // readAutoSwitch reads the on/off flag file; anything other than "on" is off.
 func readAutoSwitch(path string) string {
 	if path == "" {
@@ -1290,6 +1392,72 @@ func readAutoSwitch(path string) string {
 	return "off"
 }
 
+// rotationPolicyLabels maps proxy policy names to their settings-row labels.
+var rotationPolicyLabels = map[string]string{
+	claudeaccount.PolicyDrain:         "Drain soonest reset",
+	claudeaccount.PolicyRoundRobin:    "Round-robin",
+	claudeaccount.PolicyLeastUtilized: "Least utilized",
+	claudeaccount.PolicyPriority:      "Priority",
+}
+
+// SetRotationPolicyFile records the policy file and loads its current value.
+func (m *MainMenuModel) SetRotationPolicyFile(path string) {
+	m.rotationPolicyFile = path
+	m.rotationPolicy = readRotationPolicy(path)
+}
+
+// RotationPolicy returns the selected proxy rotation policy name.
+func (m *MainMenuModel) RotationPolicy() string {
+	if m.rotationPolicy == "" {
+		return claudeaccount.PolicyDrain
+	}
+	return m.rotationPolicy
+}
+
+// RotationPolicyLabel returns the display label of the selected policy.
+func (m *MainMenuModel) RotationPolicyLabel() string {
+	return rotationPolicyLabels[m.RotationPolicy()]
+}
+
+// CycleRotationPolicy steps through claudeaccount.PolicyNames ("prev" goes backwards,
+// anything else forwards) and persists the choice.
+func (m *MainMenuModel) CycleRotationPolicy(direction string) {
+	names := claudeaccount.PolicyNames
+	cur := 0
+	for i, n := range names {
+		if n == m.RotationPolicy() {
+			cur = i
+		}
+	}
+	if direction == "prev" {
+		cur = (cur - 1 + len(names)) % len(names)
+	} else {
+		cur = (cur + 1) % len(names)
+	}
+	m.rotationPolicy = names[cur]
+	if m.rotationPolicyFile != "" {
+		_ = os.MkdirAll(filepath.Dir(m.rotationPolicyFile), 0o755)
+		_ = os.WriteFile(m.rotationPolicyFile, []byte(m.rotationPolicy+"\n"), 0o644)
+	}
+}
+
+// readRotationPolicy reads the policy file; unknown or missing values read as
+// the default drain policy (matching lib/auto-switch.sh get_rotation_policy).
+func readRotationPolicy(path string) string {
+	if path == "" {
+		return claudeaccount.PolicyDrain
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return claudeaccount.PolicyDrain
+	}
+	v := strings.TrimSpace(string(data))
+	if _, ok := rotationPolicyLabels[v]; ok {
+		return v
+	}
+	return claudeaccount.PolicyDrain
+}
+
 // LoadClaudeConfigsList parses a name:file list file into ClaudeConfig entries.
 // It delegates to internal/claudeconfig (the single source of truth) and maps
 // to the local ClaudeConfig type.
@@ -1531,8 +1699,12 @@ func (m *MainMenuModel) SetAIToolFile(path string) { m.aiToolFile = path }
 // AIToolFile returns the file path for AI tool preference persistence.
 func (m *MainMenuModel) AIToolFile() string { return m.aiToolFile }
 
-// SetSettingsFile sets the file path for settings persistence.
-func (m *MainMenuModel) SetSettingsFile(path string) { m.settingsFile = path }
+// SetSettingsFile sets the file path for settings persistence and loads the
+// budget kept there; a budget that does not parse is treated as unset.
+func (m *MainMenuModel) SetSettingsFile(path string) {
+	m.settingsFile = path
+	m.budget, _ = usage.LoadBudget(path)
+}
 
 // SettingsFile returns the file path for settings persistence.
 func (m *MainMenuModel) SettingsFile() string { return m.settingsFile }
@@ -1819,22 +1991,80 @@ func (m *MainMenuModel) setActionResult(action string) {
 }
 
 // ensureStatsLoad kicks off the async usage aggregation the first time the
-// Stats tab is shown. Returns nil if already loaded or loading.
+// Stats tab is shown, and resumes the live rescans when the tab is shown again
+// after they went idle. Returns nil while they are running.
 func (m *MainMenuModel) ensureStatsLoad() tea.Cmd {
-	if m.statsLoaded || m.statsLoading {
+	if m.statsIdle {
+		m.statsIdle = false
+		return m.refreshStatsTabCmd()
+	}
+	if m.stats.watcher != nil {
 		return nil
 	}
-	m.statsLoading = true
 	home, _ := os.UserHomeDir()
-	_, opencodeDir, cachePath := usage.DefaultPaths(home)
-	claudeDirs := usage.ClaudeAccountProjectDirs(home)
-	return func() tea.Msg {
-		months, err := usage.AggregateAll(claudeDirs, opencodeDir, cachePath)
-		if err != nil {
+	_, _, cachePath := usage.DefaultPaths(home)
+	m.stats = StatsModel{loading: true, hover: -1, pricingPath: usage.PricingPath(home),
+		watcher: usage.NewWatcher(usage.DefaultSources(home), cachePath)}
+	w, pricingPath := m.stats.watcher, m.stats.pricingPath
+	return loadStatsCmd(func(progress func(usage.Progress)) tea.Msg {
+		if err := usage.LoadPricing(pricingPath); err != nil {
 			return statsErrMsg{err: err}
 		}
-		return statsLoadedMsg{months: months}
+		return refreshStatsTab(w, progress)
+	})
+}
+
+// statsTabLoadedMsg is one scan of the Stats tab: what StatsModel shows, plus
+// the figures of the tab's other views.
+type statsTabLoadedMsg struct {
+	statsLoadedMsg
+	blocks   []usage.BlockStatus
+	accounts []usage.GroupUsage
+	labels   map[string]string
+	tools    usage.ToolSummary
+	cache    statsCacheData
+	values   []usage.PlanValue
+}
+
+// refreshStatsTabCmd rescans through the tab's watcher.
+func (m *MainMenuModel) refreshStatsTabCmd() tea.Cmd {
+	w := m.stats.watcher
+	return func() tea.Msg { return refreshStatsTab(w, nil) }
+}
+
+// refreshStatsTab is refreshStats for the main menu's Stats tab: it rescans
+// through w, reporting to progress (which may be nil), and returns the tab's
+// figures.
+func refreshStatsTab(w *usage.Watcher, progress func(usage.Progress)) tea.Msg {
+	snap, err := w.Refresh(progress)
+	if err != nil {
+		return statsErrMsg{err: err}
 	}
+	home, _ := os.UserHomeDir()
+	now := time.Now()
+	return statsTabLoadedMsg{statsLoadedMsg: newStatsLoadedMsg(snap, now),
+		blocks:   usage.AccountBlocks(usage.ClaudeAccountProjectDirs(home), now),
+		accounts: usage.GroupRecords(snap.Records, usage.ByAccount),
+		labels:   usage.AccountLabels(home),
+		tools:    usage.SummarizeTools(snap.Tools),
+		cache:    newStatsCacheData(snap.Records),
+		values:   usage.PlanValues(snap.Records, usage.AccountPlans(home))}
+}
+
+// updateStats hands msg to the tab's StatsModel.
+func (m *MainMenuModel) updateStats(msg tea.Msg) tea.Cmd {
+	next, cmd := m.stats.Update(msg)
+	m.stats = next.(StatsModel)
+	return cmd
+}
+
+// topStatsSessions is the conversations view's list: the statsTopSessions
+// most expensive conversations.
+func (m *MainMenuModel) topStatsSessions() []usage.SessionUsage {
+	if len(m.stats.sessions) > statsTopSessions {
+		return m.stats.sessions[:statsTopSessions]
+	}
+	return m.stats.sessions
 }
 
 // bobTickCmd returns a command that sends a bobTickMsg at ~60fps.
@@ -1858,6 +2088,9 @@ func (m *MainMenuModel) Init() tea.Cmd {
 		cmds = append(cmds, m.bobTickCmd())
 		cmds = append(cmds, m.sleepTickCmd())
 	}
+	if cmd := m.budgetCmd(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
 	return tea.Batch(cmds...)
 }
 
@@ -1941,17 +2174,35 @@ func (m *MainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		models.PopulateWorktrees(m.projects)
 		return m, nil
 
-	case statsLoadedMsg:
-		m.statsMonths = msg.months
-		m.statsLoading = false
-		m.statsLoaded = true
+	case statsProgressMsg, statsLoadedMsg:
+		return m, m.updateStats(msg)
+
+	case statsTabLoadedMsg:
+		m.statsBlocks = msg.blocks
+		m.statsAccounts = msg.accounts
+		m.statsAccountLabels = msg.labels
+		m.statsTools = msg.tools
+		m.statsCache = msg.cache
+		m.statsValues = msg.values
+		if m.statsSessionSel >= len(msg.sessions) {
+			m.statsSessionSel = max(len(msg.sessions)-1, 0)
+		}
+		return m, m.updateStats(msg.statsLoadedMsg)
+
+	case statsTickMsg:
+		// Rescan only while the tab is shown; ensureStatsLoad resumes.
+		if m.activeTab != TabStats {
+			m.statsIdle = true
+			return m, nil
+		}
+		return m, m.refreshStatsTabCmd()
+
+	case budgetEvaluatedMsg:
+		m.budgetAlert, m.budgetAlertOK = msg.alert, msg.ok
 		return m, nil
 
 	case statsErrMsg:
-		m.statsErr = msg.err
-		m.statsLoading = false
-		m.statsLoaded = true
-		return m, nil
+		return m, m.updateStats(msg)
 
 	case tea.MouseMsg:
 		// Reset sleep state on any mouse activity
@@ -2087,10 +2338,16 @@ func (m *MainMenuModel) focusUp() tea.Cmd {
 				m.settingsSelected--
 			}
 		case TabStats:
-			if m.statsOffset <= 0 {
+			if m.statsView == statsViewSessions {
+				if m.statsSessionSel <= 0 {
+					m.focus = FocusTabs
+				} else {
+					m.statsSessionSel--
+				}
+			} else if m.statsView != statsViewMonths || m.stats.cursor <= 0 {
 				m.focus = FocusTabs
 			} else {
-				m.statsOffset--
+				m.stats.moveCursor(-1)
 			}
 		default: // projects
 			if m.selectedItem <= 0 {
@@ -2212,16 +2469,20 @@ func (m *MainMenuModel) focusEnter() (tea.Model, tea.Cmd) {
 		case TabSettings:
 			return m.settingsEnter()
 		case TabStats:
-			return m, nil
+			return m.statsEnter()
 		default: // projects
 			return m.projectsEnter()
 		}
 	}
 }
 
-// focusEsc backs out: from any non-Projects tab return to Projects; on Projects
-// it bubbles up to AppModel for the double-Esc quit flow.
+// focusEsc backs out: out of a drilled-in Stats level, else from any
+// non-Projects tab return to Projects; on Projects it bubbles up to AppModel
+// for the double-Esc quit flow.
 func (m *MainMenuModel) focusEsc() (tea.Model, tea.Cmd) {
+	if m.activeTab == TabStats && m.statsView == statsViewMonths && m.stats.climbOut() {
+		return m, nil
+	}
 	if m.activeTab != TabProjects {
 		m.SetActiveTab(TabProjects)
 		m.focus = FocusBody
@@ -2248,15 +2509,71 @@ func (m *MainMenuModel) projectsEnter() (tea.Model, tea.Cmd) {
 	return m, tea.Quit
 }
 
-// statsScrollDown advances the stats month window by one, bounded to the data.
+// statsScrollDown moves the monthly view's cursor down a row of the level
+// drilled into, scrolling to keep it visible; in the conversations view it
+// moves the selection down instead. The windows and tools views fit without
+// scrolling.
 func (m *MainMenuModel) statsScrollDown() {
-	max := len(m.statsMonths) - statsWindow
-	if max < 0 {
-		max = 0
+	switch m.statsView {
+	case statsViewSessions:
+		if m.statsSessionSel < len(m.topStatsSessions())-1 {
+			m.statsSessionSel++
+		}
+	case statsViewMonths:
+		m.stats.moveCursor(1)
+	}
+}
+
+// toggleStatsView cycles the Stats body from the monthly table to the most
+// expensive conversations (starting the list at the top), then to the rolling
+// 5-hour windows, then to the tool analytics, then to the cache efficiency,
+// then to the plan value, and back.
+func (m *MainMenuModel) toggleStatsView() {
+	switch m.statsView {
+	case statsViewMonths:
+		m.statsView = statsViewSessions
+		m.statsSessionSel = 0
+	case statsViewSessions:
+		m.statsView = statsViewBlocks
+	case statsViewBlocks:
+		m.statsView = statsViewTools
+	case statsViewTools:
+		m.statsView = statsViewCache
+	case statsViewCache:
+		m.statsView = statsViewValue
+	default:
+		m.statsView = statsViewMonths
+	}
+}
+
+// statsEnter drills the monthly view into the row under its cursor (see
+// StatsModel.drillIn), or resumes the highlighted conversation in a new
+// session; a conversation whose transcript or starting directory is gone
+// reports why instead of launching.
+func (m *MainMenuModel) statsEnter() (tea.Model, tea.Cmd) {
+	if m.statsView == statsViewMonths {
+		m.stats.drillIn()
+		return m, nil
 	}
-	if m.statsOffset < max {
-		m.statsOffset++
+	sessions := m.topStatsSessions()
+	if m.statsView != statsViewSessions || m.statsSessionSel >= len(sessions) {
+		return m, nil
+	}
+	s := sessions[m.statsSessionSel]
+	if !s.Resumable() {
+		m.setFeedback("Can't resume: the conversation's transcript is gone", "error")
+		return m, nil
 	}
+	if info, err := os.Stat(s.Project); err != nil || !info.IsDir() {
+		m.setFeedback("Can't resume: "+s.Project+" no longer exists", "error")
+		return m, nil
+	}
+	m.setActionResult("resume-session")
+	m.result.Name = filepath.Base(s.Project)
+	m.result.Path = s.Project
+	m.result.Session = s.ID
+	m.result.Account = s.Account
+	return m, tea.Quit
 }
 
 // handleRune processes a single rune keypress.
@@ -2307,6 +2624,11 @@ func (m *MainMenuModel) handleRune(r rune) (tea.Model, tea.Cmd) {
 		m.SetActiveTab(TabStats)
 		m.focus = FocusBody
 		return m, m.ensureStatsLoad()
+	case 'v', 'V':
+		if m.activeTab == TabStats {
+			m.toggleStatsView()
+		}
+		return m, nil
 	case '1', '2', '3', '4', '5', '6', '7', '8', '9':
 		n := int(r - '0')
 		if n > len(m.projects) {
@@ -2341,8 +2663,12 @@ func (m *MainMenuModel) runeMoveUp() {
 		n := m.settingsItemCount()
 		m.settingsSelected = (m.settingsSelected - 1 + n) % n
 	case TabStats:
-		if m.statsOffset > 0 {
-			m.statsOffset--
+		if m.statsView == statsViewSessions {
+			if m.statsSessionSel > 0 {
+				m.statsSessionSel--
+			}
+		} else if m.statsView == statsViewMonths {
+			m.stats.moveCursor(-1)
 		}
 	default:
 		m.MoveUp()
@@ -2384,6 +2710,19 @@ func (m *MainMenuModel) settingsEnter() (tea.Model, tea.Cmd) {
 		return m, nil
 	case m.autoSwitchRowIndex():
 		m.CycleAutoSwitch()
+	case m.rotationPolicyRowIndex():
+		m.CycleRotationPolicy("next")
+	case m.budgetRowIndex():
+		// Open text input for the budget spec
+		m.settingsInputMode = true
+		si := textinput.New()
+		si.Placeholder = "e.g., 200,project:app=50,account:work=100"
+		si.Width = menuContentWidth - 11
+		si.SetValue(m.budget.String())
+		si.Focus()
+		m.settingsInput = si
+		m.settingsInputErr = nil
+		return m, textinput.Blink
 	}
 	return m, nil
 }
@@ -2407,6 +2746,8 @@ func (m *MainMenuModel) settingsValueRight() {
 		m.CycleAccount("next")
 	case m.autoSwitchRowIndex():
 		m.CycleAutoSwitch()
+	case m.rotationPolicyRowIndex():
+		m.CycleRotationPolicy("next")
 	}
 }
 
@@ -2429,10 +2770,13 @@ func (m *MainMenuModel) settingsValueLeft() {
 		m.CycleAccount("prev")
 	case m.autoSwitchRowIndex():
 		m.CycleAutoSwitch()
+	case m.rotationPolicyRowIndex():
+		m.CycleRotationPolicy("prev")
 	}
 }
 
-// updateSettingsInput handles key events while in settings input mode (editing projects root).
+// updateSettingsInput handles key events while in settings input mode (editing
+// the projects root or the budget).
 func (m *MainMenuModel) updateSettingsInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyEsc, tea.KeyCtrlC:
@@ -2441,6 +2785,9 @@ func (m *MainMenuModel) updateSettingsInput(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		return m, nil
 	case tea.KeyEnter:
 		val := strings.TrimSpace(m.settingsInput.Value())
+		if m.settingsSelected == m.budgetRowIndex() {
+			return m.submitBudget(val)
+		}
 		if val != "" {
 			expanded := util.ExpandPath(val)
 			if _, err := os.Stat(expanded); err != nil {
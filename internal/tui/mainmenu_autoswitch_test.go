@@ -88,3 +88,54 @@ func TestSettings_accountSwitchingArrowKeysToggle(t *testing.T) {
 		t.Error("left arrow should toggle the row off")
 	}
 }
+
+func TestSettings_rotationPolicyDefaultsToDrain(t *testing.T) {
+	m := NewMainMenu(nil, []string{"claude"}, "claude", "animated")
+	m.SetRotationPolicyFile(filepath.Join(t.TempDir(), "rotation-policy"))
+	if m.RotationPolicy() != "drain" {
+		t.Errorf("policy = %q, want drain", m.RotationPolicy())
+	}
+	m.SetActiveTab(TabSettings)
+	out := m.renderSettingsForTest()
+	if !strings.Contains(out, "Rotation policy") || !strings.Contains(out, "[Drain soonest reset]") {
+		t.Errorf("settings panel should show the Rotation policy row:\n%s", out)
+	}
+}
+
+func TestSettings_rotationPolicyCyclesAndPersists(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "rotation-policy")
+	m := NewMainMenu(nil, []string{"claude"}, "claude", "animated")
+	m.SetRotationPolicyFile(file)
+	m.SetActiveTab(TabSettings)
+	m.settingsSelected = m.rotationPolicyRowIndex()
+
+	m.settingsValueRight()
+	if m.RotationPolicy() != "round-robin" {
+		t.Fatalf("right should advance to round-robin, got %q", m.RotationPolicy())
+	}
+	data, err := os.ReadFile(file)
+	if err != nil || strings.TrimSpace(string(data)) != "round-robin" {
+		t.Fatalf("policy file = %q err=%v, want round-robin", string(data), err)
+	}
+	m.settingsValueLeft()
+	m.settingsValueLeft()
+	if m.RotationPolicy() != "priority" {
+		t.Errorf("left should wrap back to priority, got %q", m.RotationPolicy())
+	}
+	// A fresh menu reads the persisted value.
+	m2 := NewMainMenu(nil, []string{"claude"}, "claude", "animated")
+	m2.SetRotationPolicyFile(file)
+	if m2.RotationPolicy() != "priority" {
+		t.Errorf("reloaded policy = %q, want priority", m2.RotationPolicy())
+	}
+}
+
+func TestSettings_rotationPolicyUnknownValueReadsAsDrain(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "rotation-policy")
+	os.WriteFile(file, []byte("bogus\n"), 0o644)
+	m := NewMainMenu(nil, []string{"claude"}, "claude", "animated")
+	m.SetRotationPolicyFile(file)
+	if m.RotationPolicy() != "drain" {
+		t.Errorf("policy = %q, want drain", m.RotationPolicy())
+	}
+}
@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jackuait/wisp-deck/internal/usage"
+)
+
+func TestSettings_budgetEditPersistsToSettingsFile(t *testing.T) {
+	settings := filepath.Join(t.TempDir(), "settings")
+	if err := os.WriteFile(settings, []byte("theme=auto\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := NewMainMenu(nil, []string{"claude"}, "claude", "animated")
+	m.SetSettingsFile(settings)
+	m.SetActiveTab(TabSettings)
+	if !strings.Contains(m.renderSettingsForTest(), "Monthly budget") {
+		t.Fatalf("settings panel should show a Monthly budget row:\n%s", m.renderSettingsForTest())
+	}
+
+	m.settingsSelected = m.budgetRowIndex()
+	m.settingsEnter()
+	if !m.settingsInputMode {
+		t.Fatal("enter on the budget row should open the inline input")
+	}
+	m.settingsInput.SetValue("lots")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.settingsInputMode || m.settingsInputErr == nil {
+		t.Fatal("an unparsable budget should keep the input open with an error")
+	}
+	for _, line := range strings.Split(m.renderSettingsForTest(), "\n") {
+		if strings.Contains(line, "│") && visibleWidth(line) > menuInnerWidth+2 {
+			t.Errorf("line exceeds box width: %q", line)
+		}
+	}
+
+	m.settingsInput.SetValue("200, project:app=50")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.settingsInputMode {
+		t.Fatalf("a valid budget should close the input (err %v)", m.settingsInputErr)
+	}
+	data, _ := os.ReadFile(settings)
+	if got, want := string(data), "theme=auto\nbudget=200,project:app=50\n"; got != want {
+		t.Errorf("settings = %q, want %q", got, want)
+	}
+	if !strings.Contains(m.renderSettingsForTest(), "$200/mo +1 cap") {
+		t.Errorf("budget row should summarize the caps:\n%s", m.renderSettingsForTest())
+	}
+
+	// A fresh menu loads the saved budget.
+	m2 := NewMainMenu(nil, []string{"claude"}, "claude", "animated")
+	m2.SetSettingsFile(settings)
+	if m2.budget.MonthlyUSD != 200 || m2.budget.Projects["app"] != 50 {
+		t.Errorf("reloaded budget = %+v", m2.budget)
+	}
+}
+
+func TestMainMenu_budgetAlertShowsInEveryTabHeader(t *testing.T) {
+	m := NewMainMenu(nil, []string{"claude"}, "claude", "animated")
+	m.Update(budgetEvaluatedMsg{alert: usage.BudgetStatus{Scope: "project", Key: "app", LimitUSD: 50, SpentUSD: 41, Priced: true}, ok: true})
+
+	for _, tab := range []MenuTab{TabProjects, TabSettings, TabStats} {
+		m.SetActiveTab(tab)
+		out := m.View()
+		if !strings.Contains(out, "82% of project app ($41/$50)") {
+			t.Errorf("tab %d header missing the budget alert:\n%s", tab, out)
+		}
+		for _, line := range strings.Split(out, "\n") {
+			if strings.Contains(line, "│") && visibleWidth(line) > menuInnerWidth+2 {
+				t.Errorf("tab %d line exceeds box width: %q", tab, line)
+			}
+		}
+	}
+
+	m.Update(budgetEvaluatedMsg{})
+	m.SetActiveTab(TabProjects)
+	if strings.Contains(m.View(), "of project app") {
+		t.Error("a cleared alert should leave the header spacer blank")
+	}
+}
@@ -57,8 +57,8 @@ func TestHelpRow_focusBodyStats(t *testing.T) {
 	m.SetActiveTab(TabStats)
 	m.SetFocus(FocusBody)
 	help := stripAnsi(m.renderHelpRow())
-	if !strings.Contains(help, "scroll") {
-		t.Errorf("stats-body help should mention scroll, got %q", help)
+	if !strings.Contains(help, "move") || !strings.Contains(help, "days") {
+		t.Errorf("stats-body help should mention moving and opening days, got %q", help)
 	}
 }
 
@@ -107,11 +107,12 @@ func TestRenderSettingsBox_panelRowColor_yellowForLazygit(t *testing.T) {
 
 func TestSettingsItemCount_includesPanelRow(t *testing.T) {
 	m := NewMainMenu(nil, []string{"opencode"}, "opencode", "animated")
-	// The Plan + Login + Auto-switch rows are shared across agents, so
-	// opencode also has 9 items (ghost, tab title, sound, panel, projects dir,
-	// plan, login, auto-switch accounts).
-	if m.settingsItemCount() != 9 {
-		t.Errorf("settingsItemCount = %d, want 9", m.settingsItemCount())
+	// The Plan + Login + Auto-switch + Rotation policy + Budget rows are shared
+	// across agents, so opencode also has 11 items (ghost, tab title, sound,
+	// panel, theme, projects dir, plan, login, auto-switch accounts, rotation
+	// policy, monthly budget).
+	if m.settingsItemCount() != 11 {
+		t.Errorf("settingsItemCount = %d, want 11", m.settingsItemCount())
 	}
 }
 
@@ -120,8 +121,8 @@ func TestSettingsItemCount_withClaudeConfig_includesPanelRow(t *testing.T) {
 	m.SetClaudeConfigs([]ClaudeConfig{{Name: "Pro", File: "pro.json"}})
 	m.SetActiveClaudeConfig("pro.json")
 	// With Claude config: 7 items (incl. Plan + Login)
-	if m.settingsItemCount() != 9 {
-		t.Errorf("settingsItemCount = %d, want 9", m.settingsItemCount())
+	if m.settingsItemCount() != 11 {
+		t.Errorf("settingsItemCount = %d, want 11", m.settingsItemCount())
 	}
 }
 
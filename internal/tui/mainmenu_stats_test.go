@@ -1,7 +1,14 @@
 package tui
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jackuait/wisp-deck/internal/claudeaccount"
+	"github.com/jackuait/wisp-deck/internal/usage"
 )
 
 func TestMainMenu_TKeySwitchesToStatsTab(t *testing.T) {
@@ -30,3 +37,344 @@ func TestMainMenu_hasStatsActionLabel(t *testing.T) {
 		t.Errorf("actionLabels missing {T, Stats}: %+v", actionLabels)
 	}
 }
+
+// statsSessionsMenu is a main menu on the Stats tab's conversations view with
+// two loaded conversations: an expensive one in dir and one whose transcript is
+// gone.
+func statsSessionsMenu(t *testing.T, dir string) *MainMenuModel {
+	t.Helper()
+	m := NewMainMenu(nil, []string{"claude"}, "claude", "none")
+	m.handleRune('t')
+	m.Update(statsLoadedMsg{sessions: []usage.SessionUsage{
+		{ID: "3f2a1b9c-aaaa", Project: dir, Path: filepath.Join(dir, "t.jsonl"), Account: "work",
+			First: time.Now().Add(-2 * time.Hour), Last: time.Now(), Output: 9_000_000,
+			Models: []usage.ModelUsage{{Model: "claude-opus-4-7", Output: 9_000_000}}},
+		{ID: "gone", Project: dir, Models: []usage.ModelUsage{{Model: "claude-opus-4-7", Input: 10}}, Input: 10},
+	}})
+	m.handleRune('v')
+	return m
+}
+
+func TestMainMenu_statsConversationsResumeSelected(t *testing.T) {
+	dir := t.TempDir()
+	m := statsSessionsMenu(t, dir)
+	out := m.renderStatsBox()
+	usd, _ := m.topStatsSessions()[0].CostUSD()
+	if !strings.Contains(out, "Most expensive conversations") || !strings.Contains(out, filepath.Base(dir)) ||
+		!strings.Contains(out, dollarFmt(usd)) || !strings.Contains(out, "3f2a1b9c") {
+		t.Fatalf("conversations view missing the top conversation:\n%s", out)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "│") && visibleWidth(line) > menuInnerWidth+2 {
+			t.Errorf("line exceeds box width: %q", line)
+		}
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	r := m.Result()
+	if cmd == nil || r == nil || r.Action != "resume-session" || r.Path != dir || r.Session != "3f2a1b9c-aaaa" || r.Name != filepath.Base(dir) || r.Account != "work" {
+		t.Fatalf("result = %+v, want resume-session of the selected conversation under its login", r)
+	}
+}
+
+func TestMainMenu_statsConversationsRefusesGoneTranscript(t *testing.T) {
+	m := statsSessionsMenu(t, t.TempDir())
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.Result() != nil {
+		t.Fatalf("result = %+v, want no launch for a conversation without a transcript", m.Result())
+	}
+	if !strings.Contains(m.FeedbackMsg(), "transcript is gone") {
+		t.Errorf("feedback = %q, want the reason", m.FeedbackMsg())
+	}
+	for range 5 {
+		m.handleRune('v')
+	}
+	if m.statsView != statsViewMonths {
+		t.Error("'v' should cycle back to the monthly view")
+	}
+}
+
+func TestMainMenu_statsWindowsShowEachAccountsActiveBlock(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.Local)
+	active := &usage.Block{Start: now.Add(-time.Hour), End: now.Add(4 * time.Hour), First: now.Add(-time.Hour), Last: now,
+		Input: 300_000, Calls: 3, Models: []usage.ModelUsage{{Model: "claude-opus-4-7", Input: 300_000}}}
+	m := NewMainMenu(nil, []string{"claude"}, "claude", "none")
+	m.handleRune('t')
+	m.Update(statsTabLoadedMsg{blocks: []usage.BlockStatus{
+		{Account: usage.DefaultAccount, At: now, Active: active, Limit: 1_000_000, TokensPerMin: 5000,
+			ProjectedTotal: 1_500_000, ExhaustsAt: now.Add(140 * time.Minute)},
+		{Account: "work", At: now},
+	}, labels: map[string]string{usage.DefaultAccount: "Personal", "work": "Work Max"}})
+	m.handleRune('v')
+	m.handleRune('v')
+	if m.statsView != statsViewBlocks {
+		t.Fatalf("view = %v, want the windows view after conversations", m.statsView)
+	}
+	out := m.renderStatsBox()
+	for _, want := range []string{"Rolling 5-hour windows", "Personal", "resets 16:00", "300.0K tokens", "5.0K/min",
+		"30%", "limit ~14:20", "Work Max", "idle"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("windows view missing %q:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "│") && visibleWidth(line) > menuInnerWidth+2 {
+			t.Errorf("line exceeds box width: %q", line)
+		}
+	}
+}
+
+func TestMainMenu_statsMonthsBreakDownByAccount(t *testing.T) {
+	records := []usage.Record{
+		{Day: "2026-05-01", Account: usage.DefaultAccount, ModelUsage: usage.ModelUsage{Model: "claude-opus-4-7", Input: 250_000}},
+		{Day: "2026-05-02", Account: "work", ModelUsage: usage.ModelUsage{Model: "claude-opus-4-7", Input: 750_000}},
+	}
+	m := NewMainMenu(nil, []string{"claude"}, "claude", "none")
+	m.handleRune('t')
+	m.Update(statsTabLoadedMsg{statsLoadedMsg: statsLoadedMsg{months: usage.Months(records)}, accounts: usage.GroupRecords(records, usage.ByAccount),
+		labels: map[string]string{usage.DefaultAccount: "Personal", "work": "Work Max"}})
+	out := m.renderStatsBox()
+	if !strings.Contains(out, "By account") {
+		t.Fatalf("monthly view missing the account breakdown:\n%s", out)
+	}
+	work, personal := strings.Index(out, "Work Max"), strings.Index(out, "Personal")
+	if work < 0 || personal < 0 || work > personal {
+		t.Errorf("want Work Max (the larger) listed before Personal:\n%s", out)
+	}
+	if !strings.Contains(out, "75%") || !strings.Contains(out, "25%") {
+		t.Errorf("want each account's share of the total:\n%s", out)
+	}
+
+	// A single account has nothing to break down.
+	m.Update(statsTabLoadedMsg{statsLoadedMsg: statsLoadedMsg{months: usage.Months(records[:1])}, accounts: usage.GroupRecords(records[:1], usage.ByAccount)})
+	if out := m.renderStatsBox(); strings.Contains(out, "By account") {
+		t.Errorf("single-account usage should not get a breakdown:\n%s", out)
+	}
+}
+
+func TestLoadStatsCmd_relaysProgressThenResult(t *testing.T) {
+	release := make(chan struct{})
+	cmd := loadStatsCmd(func(progress func(usage.Progress)) tea.Msg {
+		progress(usage.Progress{FilesDone: 12, FilesTotal: 40, BytesDone: 3_000_000, BytesTotal: 12_000_000})
+		<-release
+		return statsLoadedMsg{}
+	})
+	first := cmd()
+	msg, ok := first.(statsProgressMsg)
+	if !ok {
+		t.Fatalf("first message = %T, want progress", first)
+	}
+
+	m := NewMainMenu(nil, []string{"claude"}, "claude", "none")
+	m.handleRune('t')
+	m.stats.loading = true
+	_, next := m.Update(msg)
+	out := m.renderStatsBox()
+	if !strings.Contains(out, "12 of 40 files · 3.0 MB of 12.0 MB") || !strings.Contains(out, "25%") {
+		t.Errorf("loading state missing the progress:\n%s", out)
+	}
+	close(release)
+	if next == nil {
+		t.Fatal("progress should keep listening for the result")
+	}
+	if _, ok := next().(statsLoadedMsg); !ok {
+		t.Error("want the result after the progress")
+	}
+}
+
+// The tab stays live like the Stats screen: each rescan is followed by the
+// next while the tab is shown, goes idle once it is hidden, and resumes when
+// it is shown again.
+func TestMainMenu_statsTabRescansWhileShown(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	m := NewMainMenu(nil, []string{"claude"}, "claude", "none")
+	m.handleRune('t')
+	m.stats.watcher = usage.NewWatcher(nil, filepath.Join(home, "cache.json"))
+	at := time.Date(2026, 5, 1, 9, 30, 0, 0, time.Local)
+	_, cmd := m.Update(statsTabLoadedMsg{statsLoadedMsg: statsLoadedMsg{
+		months: []usage.MonthlyUsage{{Month: "2026-05", Input: 2_000_000}}, at: at}})
+	if cmd == nil {
+		t.Fatal("a load should schedule the next rescan")
+	}
+	if out := m.renderStatsBox(); !strings.Contains(out, "live, updated 09:30:00") {
+		t.Errorf("months view should say when it was scanned:\n%s", out)
+	}
+	_, cmd = m.Update(statsTickMsg{})
+	if cmd == nil {
+		t.Fatal("a tick on the tab should rescan")
+	}
+	if _, ok := cmd().(statsTabLoadedMsg); !ok {
+		t.Error("the rescan should return the tab's figures")
+	}
+
+	m.SetActiveTab(TabProjects)
+	if _, cmd := m.Update(statsTickMsg{}); cmd != nil || !m.statsIdle {
+		t.Error("a tick with the tab hidden should go idle")
+	}
+	if _, cmd := m.handleRune('t'); cmd == nil || m.statsIdle {
+		t.Error("showing the tab again should resume the rescans")
+	}
+	if _, cmd := m.handleRune('t'); cmd != nil {
+		t.Error("a running tab should not start another load")
+	}
+}
+
+// The tab's monthly view drills down and charts like the Stats screen, inside
+// the menu box.
+func TestMainMenu_statsMonthsDrillDownWithCharts(t *testing.T) {
+	m := NewMainMenu(nil, []string{"claude"}, "claude", "none")
+	m.handleRune('t')
+	base := drillModel()
+	m.Update(statsLoadedMsg{months: base.months, records: base.records, sessions: base.sessions})
+	key := func(k tea.KeyType) string {
+		t.Helper()
+		m.Update(tea.KeyMsg{Type: k})
+		return stripANSI(m.renderStatsBox())
+	}
+	box := stripANSI(m.renderStatsBox())
+	if !strings.Contains(box, "Daily tokens · Jun 2026") || !strings.Contains(box, "Model mix · Jun 2026") {
+		t.Errorf("months view should chart the month under the cursor:\n%s", box)
+	}
+
+	key(tea.KeyEnter)
+	key(tea.KeyEnter)
+	box = key(tea.KeyEnter)
+	if !strings.Contains(box, "Jun 2026 › Sun Jun 14 › app") || !strings.Contains(box, "3f9c2a71") ||
+		!strings.Contains(box, "(no live conversation)") {
+		t.Fatalf("three enters should reach app's conversations on the 14th:\n%s", box)
+	}
+	for _, line := range strings.Split(m.renderStatsBox(), "\n") {
+		if strings.Contains(line, "│") && visibleWidth(line) > menuInnerWidth+2 {
+			t.Errorf("line exceeds box width: %q", line)
+		}
+	}
+
+	for range 3 {
+		key(tea.KeyEsc)
+	}
+	if m.ActiveTab() != TabStats || m.stats.level() != statsLevelMonths {
+		t.Fatalf("esc should climb back to the months (tab %v, level %d)", m.ActiveTab(), m.stats.level())
+	}
+	if key(tea.KeyEsc); m.ActiveTab() != TabProjects {
+		t.Error("esc on the months should leave the tab")
+	}
+}
+
+func TestMainMenu_statsToolsShowCallsFailuresAndServers(t *testing.T) {
+	m := NewMainMenu(nil, []string{"claude"}, "claude", "none")
+	m.handleRune('t')
+	m.Update(statsTabLoadedMsg{tools: usage.SummarizeTools([]usage.ToolRecord{
+		{Day: "2026-05-01", Tool: "Bash", Calls: 1200, Errors: 12, Tokens: 600},
+		{Day: "2026-05-01", Tool: "mcp__github__create_issue", Calls: 40, Errors: 10, Tokens: 300},
+		{Day: "2026-05-01", Tool: "mcp__github__get_issue", Calls: 10, Tokens: 50},
+		{Day: "2026-05-01", Tool: "Task", Agent: "Explore", Calls: 3, Tokens: 50},
+	})})
+	for range 3 {
+		m.handleRune('v')
+	}
+	if m.statsView != statsViewTools {
+		t.Fatalf("view = %v, want the tools view after the windows", m.statsView)
+	}
+	out := m.renderStatsBox()
+	for _, want := range []string{"Tools", "Bash", "1,200", "1.0%", "60%", "MCP servers", "github", "20.0%", "35%",
+		"Sub-agents", "Explore 3", "1,253 calls"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("tools view missing %q:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "│") && visibleWidth(line) > menuInnerWidth+2 {
+			t.Errorf("line exceeds box width: %q", line)
+		}
+	}
+}
+
+func TestMainMenu_statsCacheShowsHitRatioSavingsAndWaste(t *testing.T) {
+	m := NewMainMenu(nil, []string{"claude"}, "claude", "none")
+	m.handleRune('t')
+	m.Update(statsTabLoadedMsg{cache: newStatsCacheData([]usage.Record{
+		{Day: "2026-05-01", Project: "/src/app", ModelUsage: usage.ModelUsage{Model: "claude-sonnet-4-5",
+			Input: 1_000_000, CacheWrite: 1_000_000, CacheWrite1h: 500_000, CacheRead: 8_000_000, CacheUnread: 250_000}},
+		{Day: "2026-04-01", Project: "/src/lib", ModelUsage: usage.ModelUsage{Model: "claude-sonnet-4-5",
+			Input: 1_000_000, CacheRead: 1_000_000}},
+	})})
+	for range 4 {
+		m.handleRune('v')
+	}
+	if m.statsView != statsViewCache {
+		t.Fatalf("view = %v, want the cache view after the tools", m.statsView)
+	}
+	// 9M of 12M input read from the cache at $0.30 instead of $3 saves $24.30;
+	// 250K unread 5-minute writes at $3.75 wasted $0.94; the 500K 1-hour writes
+	// cost $3 of the $4.88 written.
+	out := m.renderStatsBox()
+	for _, want := range []string{"Prompt cache", "75% hit", "saved $24", "25% never read $0.94",
+		"May 2026", "80%", "Apr 2026", "50%", "claude-sonnet-4-5", "app", "lib", "writes cost $5 (1-hour $3)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("cache view missing %q:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "│") && visibleWidth(line) > menuInnerWidth+2 {
+			t.Errorf("line exceeds box width: %q", line)
+		}
+	}
+	m.handleRune('v')
+	if m.statsView != statsViewValue {
+		t.Error("'v' should cycle from the cache view to the plan value")
+	}
+}
+
+func TestMainMenu_statsValueWeighsEachPlanAgainstAPIRates(t *testing.T) {
+	m := NewMainMenu(nil, []string{"claude"}, "claude", "none")
+	m.handleRune('t')
+	sonnet := func(day, account string, in int64) usage.Record {
+		return usage.Record{Day: day, Account: account, ModelUsage: usage.ModelUsage{Model: "claude-sonnet-4-5", Input: in}}
+	}
+	m.Update(statsTabLoadedMsg{
+		labels: map[string]string{"work": "Work Max", usage.DefaultAccount: "Personal"},
+		values: usage.PlanValues([]usage.Record{
+			sonnet("2026-05-02", "work", 100_000_000),             // $300 on a $200 plan
+			sonnet("2026-05-02", usage.DefaultAccount, 1_000_000), // $3 on a $20 plan
+			sonnet("2026-04-02", "work", 50_000_000),              // $150 on a $200 plan
+		}, map[string]claudeaccount.Plan{
+			"work":               {Name: "max-20x", MonthlyUSD: 200},
+			usage.DefaultAccount: {Name: "pro", MonthlyUSD: 20},
+		}),
+	})
+	for range 5 {
+		m.handleRune('v')
+	}
+	if m.statsView != statsViewValue {
+		t.Fatalf("view = %v, want the plan value after the cache", m.statsView)
+	}
+	out := m.renderStatsBox()
+	for _, want := range []string{"Plan value", "May 2026", "Work Max", "max-20x $200", "$300", "1.5× pays off",
+		"Personal", "pro $20", "0.1× over-provisioned", "Apr 2026", "0.8× below break-even"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("value view missing %q:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "│") && visibleWidth(line) > menuInnerWidth+2 {
+			t.Errorf("line exceeds box width: %q", line)
+		}
+	}
+	m.handleRune('v')
+	if m.statsView != statsViewMonths {
+		t.Error("'v' should cycle from the plan value back to the months")
+	}
+}
+
+func TestMainMenu_statsValueWithoutPlansSaysHowToAddOne(t *testing.T) {
+	m := NewMainMenu(nil, []string{"claude"}, "claude", "none")
+	m.handleRune('t')
+	m.Update(statsLoadedMsg{})
+	m.statsView = statsViewValue
+	if out := m.renderStatsBox(); !strings.Contains(out, "No subscription plans recorded yet.") {
+		t.Errorf("empty value view:\n%s", out)
+	}
+}
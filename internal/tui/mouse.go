@@ -374,8 +374,8 @@ func (m *MainMenuModel) scrollBody(delta int) {
 	case TabStats:
 		if delta > 0 {
 			m.statsScrollDown()
-		} else if m.statsOffset > 0 {
-			m.statsOffset--
+		} else {
+			m.runeMoveUp()
 		}
 	}
 }
@@ -14,10 +14,11 @@ import (
 // Font and render as tofu). All three are one-cell glyphs at the same byte
 // length, so the captions are the same width and the chevrons line up vertically.
 const (
-	iconLogin = "\U000F0004" // nf-md-account — which Claude login is active
-	iconAgent = "\U000F06A9" // nf-md-robot   — the AI agent (Claude Code / OpenCode)
-	iconPlan  = "\U000F0148" // nf-md-crown   — the Claude subscription tier
-	iconGhost = "\U000F02A0" // nf-md-ghost   — captions the "Wisp Deck" wordmark
+	iconLogin  = "\U000F0004" // nf-md-account — which Claude login is active
+	iconAgent  = "\U000F06A9" // nf-md-robot   — the AI agent (Claude Code / OpenCode)
+	iconPlan   = "\U000F0148" // nf-md-crown   — the Claude subscription tier
+	iconGhost  = "\U000F02A0" // nf-md-ghost   — captions the "Wisp Deck" wordmark
+	iconBudget = "\U000F0114" // nf-md-cash    — marks a monthly budget alert
 
 	// Switcher prev/next buttons. Material Design chevrons from the same nerd-font
 	// family as the caption icons above; both are one-cell glyphs so the
@@ -92,6 +93,23 @@ func (m *MainMenuModel) headerRow(content, title, leftBorder, rightBorder string
 	return leftBorder + " " + content + strings.Repeat(" ", pad) + title + rightBorder
 }
 
+// renderSwitcherGap renders the spacer between the header switchers and the
+// tab bar. It stays blank unless this month's spend has crossed a budget
+// threshold (see budgetCmd), when it carries the alert right-aligned: yellow
+// at 50/80%, red at 100%. Reusing the spacer keeps the header height, and so
+// every layout and click-mapping offset, unchanged.
+func (m *MainMenuModel) renderSwitcherGap(leftBorder, rightBorder string) string {
+	if !m.budgetAlertOK {
+		return m.emptyMenuRow(leftBorder, rightBorder)
+	}
+	color := lipgloss.Color("220") // yellow
+	if m.budgetAlert.Threshold() >= 100 {
+		color = lipgloss.Color("196") // red
+	}
+	alert := lipgloss.NewStyle().Foreground(color).Render(TruncateMiddle(iconBudget+" "+m.budgetAlert.String(), menuContentWidth-3))
+	return m.headerRow("", alert, leftBorder, rightBorder)
+}
+
 // renderTitleRow renders the left-aligned AGENT tool chooser. It carries the
 // right-aligned "Wisp Deck" wordmark only when no account row sits above it;
 // otherwise the wordmark lives on that top account row.
@@ -643,7 +661,25 @@ func (m *MainMenuModel) focusHint() string {
 		case TabSettings:
 			return "↑↓ move · ←→ change · ↵ edit · ↑ sections"
 		case TabStats:
-			return "↑↓ scroll · ↑ sections"
+			switch m.statsView {
+			case statsViewSessions:
+				return "↑↓ move · ↵ resume · V 5h windows · ↑ sections"
+			case statsViewBlocks:
+				return "V tools · ↑ sections"
+			case statsViewTools:
+				return "V cache · ↑ sections"
+			case statsViewCache:
+				return "V plan value · ↑ sections"
+			case statsViewValue:
+				return "V months · ↑ sections"
+			}
+			if m.stats.level() > statsLevelMonths {
+				if next := m.stats.drillNext(); next != "" {
+					return "↑↓ move · ↵ " + next + " · esc back"
+				}
+				return "↑↓ move · esc back"
+			}
+			return "↑↓ move · ↵ days · V conversations · ↑ sections"
 		default: // projects
 			return "↑↓ move · ↵ open · ↑ sections · O open once · P plain · L login"
 		}
@@ -678,8 +714,9 @@ func (m *MainMenuModel) renderMenuBox() string {
 		lines = append(lines, m.renderSubscriptionRow(leftBorder, rightBorder))
 	}
 
-	// Blank spacer separating the agent/plan switchers from the tab bar.
-	lines = append(lines, m.emptyMenuRow(leftBorder, rightBorder))
+	// Spacer separating the agent/plan switchers from the tab bar; it carries
+	// the budget alert when one is raised.
+	lines = append(lines, m.renderSwitcherGap(leftBorder, rightBorder))
 
 	// Tab bar
 	lines = append(lines, m.renderTabBar(leftBorder, rightBorder))
@@ -1,9 +1,12 @@
 package tui
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jackuait/wisp-deck/internal/usage"
 )
 
 // renderSettingsItem renders a single settings item row with state right-aligned.
@@ -54,6 +57,46 @@ func (m *MainMenuModel) renderSettingsItem(index int, label, stateText string, s
 	return leftBorder + prefix + strings.Repeat(" ", gap) + stateRendered + " " + rightBorder
 }
 
+// renderSettingsInputRows renders the inline text input that replaces the
+// row being edited, followed by its validation error, if any.
+func (m *MainMenuModel) renderSettingsInputRows(leftBorder, rightBorder string) []string {
+	inputView := m.settingsInput.View()
+	inputPadding := menuContentWidth - lipgloss.Width(inputView) - 1
+	if inputPadding < 0 {
+		inputPadding = 0
+	}
+	rows := []string{leftBorder + " " + inputView + strings.Repeat(" ", inputPadding) + rightBorder}
+	if m.settingsInputErr != nil {
+		errText := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(TruncateMiddle(m.settingsInputErr.Error(), menuContentWidth-2))
+		errPadding := menuContentWidth - lipgloss.Width(errText) - 1
+		if errPadding < 0 {
+			errPadding = 0
+		}
+		rows = append(rows, leftBorder+" "+errText+strings.Repeat(" ", errPadding)+rightBorder)
+	}
+	return rows
+}
+
+// budgetLabel summarizes a budget for its settings row: the overall cap and
+// how many project/account caps come with it, e.g. "$200/mo +2 caps".
+func budgetLabel(b usage.Budget) string {
+	caps := ""
+	switch n := len(b.Projects) + len(b.Accounts); {
+	case n == 1:
+		caps = "1 cap"
+	case n > 1:
+		caps = fmt.Sprintf("%d caps", n)
+	}
+	if b.MonthlyUSD <= 0 {
+		return caps
+	}
+	label := "$" + strconv.FormatFloat(b.MonthlyUSD, 'f', -1, 64) + "/mo"
+	if caps != "" {
+		label += " +" + caps
+	}
+	return label
+}
+
 // renderSettingsBox builds the Settings tab box string: shared chrome (top border +
 // title row + tab bar + separator) followed by the existing settings item rows and
 // help row.
@@ -89,7 +132,7 @@ func (m *MainMenuModel) renderSettingsBox() string {
 	if m.subscriptionRowCount() > 0 {
 		lines = append(lines, m.renderSubscriptionRow(leftBorder, rightBorder))
 	}
-	lines = append(lines, m.emptyMenuRow(leftBorder, rightBorder))
+	lines = append(lines, m.renderSwitcherGap(leftBorder, rightBorder))
 	lines = append(lines, m.renderTabBar(leftBorder, rightBorder))
 	lines = append(lines, separator)
 
@@ -163,24 +206,7 @@ func (m *MainMenuModel) renderSettingsBox() string {
 	}
 	rootStyle := lipgloss.NewStyle().Foreground(rootColor)
 	if m.settingsInputMode && m.settingsSelected == 5 {
-		// Render inline text input
-		inputView := m.settingsInput.View()
-		inputWidth := lipgloss.Width(inputView)
-		inputPadding := menuContentWidth - inputWidth - 1
-		if inputPadding < 0 {
-			inputPadding = 0
-		}
-		inputRow := leftBorder + " " + inputView + strings.Repeat(" ", inputPadding) + rightBorder
-		lines = append(lines, inputRow)
-		if m.settingsInputErr != nil {
-			errText := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.settingsInputErr.Error())
-			errPadding := menuContentWidth - lipgloss.Width(errText) - 1
-			if errPadding < 0 {
-				errPadding = 0
-			}
-			errRow := leftBorder + " " + errText + strings.Repeat(" ", errPadding) + rightBorder
-			lines = append(lines, errRow)
-		}
+		lines = append(lines, m.renderSettingsInputRows(leftBorder, rightBorder)...)
 	} else {
 		lines = append(lines, m.renderSettingsItem(5, "Projects folder", rootState, rootStyle, primaryBoldStyle, leftBorder, rightBorder))
 	}
@@ -231,6 +257,29 @@ func (m *MainMenuModel) renderSettingsBox() string {
 	autoStyle := lipgloss.NewStyle().Foreground(autoColor)
 	lines = append(lines, m.renderSettingsItem(m.autoSwitchRowIndex(), "Auto-switch accounts", autoState, autoStyle, primaryBoldStyle, leftBorder, rightBorder))
 
+	// Rotation policy item: how the proxy picks among pooled accounts. Dimmed
+	// while auto-switch is off, since it only applies to the proxy.
+	policyColor := lipgloss.Color("241")
+	if m.AutoSwitchEnabled() {
+		policyColor = lipgloss.Color("114")
+	}
+	policyStyle := lipgloss.NewStyle().Foreground(policyColor)
+	lines = append(lines, m.renderSettingsItem(m.rotationPolicyRowIndex(), "Rotation policy", "["+m.RotationPolicyLabel()+"]", policyStyle, primaryBoldStyle, leftBorder, rightBorder))
+
+	// Monthly budget item: the settings file's budget spec, edited inline like
+	// the projects folder.
+	if m.settingsInputMode && m.settingsSelected == m.budgetRowIndex() {
+		lines = append(lines, m.renderSettingsInputRows(leftBorder, rightBorder)...)
+	} else {
+		budgetState := "(not set)"
+		budgetColor := lipgloss.Color("241") // gray when not set
+		if !m.budget.IsZero() {
+			budgetState = budgetLabel(m.budget)
+			budgetColor = lipgloss.Color("114") // green when set
+		}
+		lines = append(lines, m.renderSettingsItem(m.budgetRowIndex(), "Monthly budget", budgetState, lipgloss.NewStyle().Foreground(budgetColor), primaryBoldStyle, leftBorder, rightBorder))
+	}
+
 	// Empty row
 	lines = append(lines, emptyRow)
 
@@ -242,7 +291,7 @@ func (m *MainMenuModel) renderSettingsBox() string {
 	sep := dimStyle.Render(" · ")
 	var cycleOrEdit string
 	switch {
-	case m.settingsSelected == 5:
+	case m.settingsSelected == 5 || m.settingsSelected == m.budgetRowIndex():
 		cycleOrEdit = helpStyle.Render("⏎ edit")
 	case m.settingsSelected == 6 && m.ClaudeConfigVisible():
 		if m.selectedConfig > 0 {
@@ -250,7 +299,7 @@ func (m *MainMenuModel) renderSettingsBox() string {
 		} else {
 			cycleOrEdit = helpStyle.Render("←→ cycle")
 		}
-	case m.settingsSelected == m.settingsItemCount()-1:
+	case m.settingsSelected == m.loginRowIndex():
 		if m.accountFocusable() {
 			cycleOrEdit = helpStyle.Render("←→ switch") + sep + helpStyle.Render("⏎ manage")
 		} else {
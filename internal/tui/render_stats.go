@@ -2,7 +2,9 @@ package tui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jackuait/wisp-deck/internal/usage"
@@ -46,8 +48,11 @@ func (m *MainMenuModel) renderStatsRows(leftBorder, rightBorder string) []string
 	rows = append(rows, emptyRow)
 
 	// Loading state.
-	if m.statsLoading {
+	if m.stats.loading {
 		rows = append(rows, textRow(primaryBoldStyle.Render("Crunching token usage…")))
+		for _, r := range statsProgressRows(m.stats.progress, lipgloss.NewStyle().Foreground(m.theme.Primary), dimStyle, faint) {
+			rows = append(rows, textRow(r))
+		}
 		rows = append(rows, emptyRow)
 		rows = append(rows, textRow(faint.Render("Usage data is read from ~/.claude/usage/")))
 		rows = append(rows, emptyRow)
@@ -55,14 +60,27 @@ func (m *MainMenuModel) renderStatsRows(leftBorder, rightBorder string) []string
 	}
 
 	// Error state.
-	if m.statsErr != nil {
-		rows = append(rows, textRow(errStyle.Render("Failed to load usage: "+m.statsErr.Error())))
+	if m.stats.err != nil {
+		rows = append(rows, textRow(errStyle.Render("Failed to load usage: "+m.stats.err.Error())))
 		rows = append(rows, emptyRow)
 		return rows
 	}
 
+	switch m.statsView {
+	case statsViewSessions:
+		return append(rows, m.renderStatsSessionRows(leftBorder, rightBorder)...)
+	case statsViewBlocks:
+		return append(rows, m.renderStatsBlockRows(leftBorder, rightBorder)...)
+	case statsViewTools:
+		return append(rows, m.renderStatsToolRows(leftBorder, rightBorder)...)
+	case statsViewCache:
+		return append(rows, m.renderStatsCacheRows(leftBorder, rightBorder)...)
+	case statsViewValue:
+		return append(rows, m.renderStatsValueRows(leftBorder, rightBorder)...)
+	}
+
 	// Empty state (loaded but no data).
-	if len(m.statsMonths) == 0 {
+	if len(m.stats.months) == 0 {
 		rows = append(rows, textRow(muted.Render("No usage data found yet.")))
 		rows = append(rows, emptyRow)
 		rows = append(rows, textRow(faint.Render("Usage data is read from ~/.claude/usage/")))
@@ -70,6 +88,19 @@ func (m *MainMenuModel) renderStatsRows(leftBorder, rightBorder string) []string
 		return rows
 	}
 
+	// The drill-down and the charts are StatsModel's, boxed like the tab.
+	_, separator, _, _, _ := m.boxBorders()
+	drill := statsRows{box: func(content string) string {
+		gap := max(menuContentWidth-lipgloss.Width(content), 0)
+		return leftBorder + content + strings.Repeat(" ", gap) + rightBorder
+	}, sep: separator, add: func(line string, _ int) { rows = append(rows, line) }}
+	items := m.stats.statsItems()
+	if m.stats.level() > statsLevelMonths {
+		m.stats.drillRows(items, drill)
+		m.stats.chartRows(items, drill)
+		return append(rows, emptyRow)
+	}
+
 	// Column header row.
 	header := lipgloss.NewStyle().Foreground(m.theme.Dim).Bold(true)
 	hdr := "  " + header.Render(fmt.Sprintf("%-8s %7s   %7s   %7s   %7s         %9s",
@@ -84,14 +115,14 @@ func (m *MainMenuModel) renderStatsRows(leftBorder, rightBorder string) []string
 	sepRow := leftBorder + strings.Repeat(" ", menuContentWidth) + rightBorder
 	rows = append(rows, sepRow)
 
-	// Visible window of months.
-	end := m.statsOffset + statsWindow
-	if end > len(m.statsMonths) {
-		end = len(m.statsMonths)
+	// Visible window of months, scrolled to keep the cursor in it.
+	end := m.stats.offset + statsWindow
+	if end > len(m.stats.months) {
+		end = len(m.stats.months)
 	}
-	visibleMonths := m.statsMonths[m.statsOffset:end]
+	visibleMonths := m.stats.months[m.stats.offset:end]
 
-	grandTotal := statsGrandTotal(m.statsMonths)
+	grandTotal := statsGrandTotal(m.stats.months)
 	allTotal := grandTotal.Total()
 	if allTotal < 1 {
 		allTotal = 1
@@ -112,7 +143,12 @@ func (m *MainMenuModel) renderStatsRows(leftBorder, rightBorder string) []string
 			costStr = "~" + costStr
 		}
 
-		dataLine := "  " + numStyle.Render(fmt.Sprintf("%-8s %7s   %7s   %7s   %7s",
+		// The cursor marker takes the row's two-space indent.
+		marker := "  "
+		if m.focus == FocusBody {
+			marker = m.stats.statsItemMarker(m.stats.offset + i)
+		}
+		dataLine := marker + numStyle.Render(fmt.Sprintf("%-8s %7s   %7s   %7s   %7s",
 			monthLabel(mu.Month),
 			humanizeTokens(mu.Input),
 			humanizeTokens(mu.Output),
@@ -167,7 +203,7 @@ func (m *MainMenuModel) renderStatsRows(leftBorder, rightBorder string) []string
 	g := grandTotal
 	grandCost := 0.0
 	grandAllPriced := true
-	for _, mu := range m.statsMonths {
+	for _, mu := range m.stats.months {
 		c, ap := mu.CostUSD()
 		grandCost += c
 		if !ap {
@@ -181,8 +217,461 @@ func (m *MainMenuModel) renderStatsRows(leftBorder, rightBorder string) []string
 
 	rows = append(rows, itemRow("Total", humanizeTokens(g.Total()), primaryBoldStyle, primaryBoldStyle))
 	rows = append(rows, itemRow("Est. cost", grandCostStr, header, primaryBoldStyle))
+	if !m.stats.updated.IsZero() {
+		rows = append(rows, textRow(faint.Render("live, updated "+m.stats.updated.Local().Format("15:04:05"))))
+	}
+	rows = append(rows, emptyRow)
+
+	// Per-account breakdown: which login carried the load, as a share of the
+	// grand total. Only worth a section when usage spans more than one account.
+	if len(m.statsAccounts) > 1 {
+		rows = append(rows, textRow(header.Render("By account")))
+		branchStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+		for j, a := range m.statsAccounts {
+			connector := "├─"
+			if j == len(m.statsAccounts)-1 {
+				connector = "└─"
+			}
+			label := usage.AccountLabel(m.statsAccountLabels, a.Key)
+			if len(label) > 16 {
+				label = label[:15] + "…"
+			}
+			pct := int(float64(a.Total())/float64(allTotal)*100 + 0.5)
+			usd, priced := a.CostUSD()
+			cost := dollarFmt(usd)
+			if !priced {
+				cost = "~" + cost
+			}
+			line := "  " + branchStyle.Render(connector) + " " + numStyle.Render(fmt.Sprintf("%-16s %8s", label, humanizeTokens(a.Total()))) +
+				" " + faint.Render(fmt.Sprintf("%4d%%", pct))
+			pad := menuContentWidth - lipgloss.Width(line) - lipgloss.Width(cost)
+			if pad < 1 {
+				pad = 1
+			}
+			rows = append(rows, leftBorder+line+strings.Repeat(" ", pad)+dimStyle.Render(cost)+rightBorder)
+		}
+		rows = append(rows, emptyRow)
+	}
+
+	// The daily chart of the month under the cursor and its model mix.
+	n := len(rows)
+	m.stats.chartRows(items, drill)
+	if len(rows) > n {
+		rows = append(rows, emptyRow)
+	}
+	return rows
+}
+
+// statsDuration renders how long a conversation ran: 45s, 12m, 3h 05m, 2d 4h.
+func statsDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// renderStatsSessionRows renders the conversations view of the Stats tab: the
+// most expensive Claude conversations, two rows each — project, last activity,
+// tokens and cost, then the models, how long it ran and its short id — in a
+// window that scrolls to keep the selection visible.
+func (m *MainMenuModel) renderStatsSessionRows(leftBorder, rightBorder string) []string {
+	primaryStyle := lipgloss.NewStyle().Foreground(m.theme.Primary)
+	primaryBoldStyle := primaryStyle.Bold(true)
+	header := lipgloss.NewStyle().Foreground(m.theme.Dim).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(m.theme.Dim)
+	numStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	faint := lipgloss.NewStyle().Faint(true)
+
+	emptyRow := leftBorder + strings.Repeat(" ", menuContentWidth) + rightBorder
+	// row pads left and right chunks apart so the right chunk's edge lands on
+	// menuContentWidth, sharing the monthly view's money column.
+	row := func(left, right string) string {
+		gap := menuContentWidth - lipgloss.Width(left) - lipgloss.Width(right)
+		if gap < 1 {
+			gap = 1
+		}
+		return leftBorder + left + strings.Repeat(" ", gap) + right + rightBorder
+	}
+
+	sessions := m.topStatsSessions()
+	var rows []string
+	if len(sessions) == 0 {
+		rows = append(rows, row("  "+muted.Render("No conversations found yet."), ""))
+		rows = append(rows, emptyRow)
+		return rows
+	}
+	rows = append(rows, row("  "+header.Render("Most expensive conversations"),
+		faint.Render(fmt.Sprintf("top %d", len(sessions)))))
+	rows = append(rows, emptyRow)
+
+	start := 0
+	if m.statsSessionSel >= statsSessionWindow {
+		start = m.statsSessionSel - statsSessionWindow + 1
+	}
+	end := start + statsSessionWindow
+	if end > len(sessions) {
+		end = len(sessions)
+	}
+	for i := start; i < end; i++ {
+		s := sessions[i]
+		selected := i == m.statsSessionSel && m.focus == FocusBody
+		marker, nameStyle := " ", numStyle
+		if selected {
+			marker, nameStyle = primaryBoldStyle.Render("▸"), primaryBoldStyle
+		}
+		name := filepath.Base(s.Project)
+		if s.Project == "" {
+			name = "(unknown project)"
+		}
+		if len(name) > 22 {
+			name = name[:21] + "…"
+		}
+		usd, priced := s.CostUSD()
+		cost := dollarFmt(usd)
+		if !priced {
+			cost = "~" + cost
+		}
+		left := "  " + marker + " " + faint.Render(fmt.Sprintf("%2d", i+1)) + "  " +
+			nameStyle.Render(fmt.Sprintf("%-22s", name)) + "  " +
+			muted.Render(s.Last.Local().Format("Jan 02 15:04")) + "   " +
+			numStyle.Render(fmt.Sprintf("%7s", humanizeTokens(s.Total())))
+		rows = append(rows, row(left, primaryBoldStyle.Render(cost)))
+
+		models := make([]string, 0, len(s.Models))
+		for _, md := range s.Models {
+			models = append(models, strings.TrimPrefix(md.Model, "claude-"))
+		}
+		id := s.ID
+		if len(id) > 8 {
+			id = id[:8]
+		}
+		detail := strings.Join(models, ", ") + " · " + statsDuration(s.Last.Sub(s.First)) + " · " + id
+		if !s.Resumable() {
+			detail += " · transcript gone"
+		}
+		const indent = "        "
+		rows = append(rows, row(indent+dimStyle.Render(TruncateMiddle(detail, menuContentWidth-len(indent))), ""))
+	}
+
+	if m.feedbackMsg != "" {
+		rows = append(rows, emptyRow)
+		rows = append(rows, row("  "+lipgloss.NewStyle().Foreground(lipgloss.Color("220")).Render(m.feedbackMsg), ""))
+	}
+	rows = append(rows, emptyRow)
+	return rows
+}
+
+// renderStatsBlockRows renders the windows view of the Stats tab: for each
+// native account, its open 5-hour window — when it resets, tokens and cost so
+// far against the largest recent window, the burn rate, and where that pace
+// lands by the reset.
+func (m *MainMenuModel) renderStatsBlockRows(leftBorder, rightBorder string) []string {
+	primaryBoldStyle := lipgloss.NewStyle().Foreground(m.theme.Primary).Bold(true)
+	header := lipgloss.NewStyle().Foreground(m.theme.Dim).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(m.theme.Dim)
+	numStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	faint := lipgloss.NewStyle().Faint(true)
+	warn := lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
+
+	emptyRow := leftBorder + strings.Repeat(" ", menuContentWidth) + rightBorder
+	row := func(left, right string) string {
+		gap := menuContentWidth - lipgloss.Width(left) - lipgloss.Width(right)
+		if gap < 1 {
+			gap = 1
+		}
+		return leftBorder + left + strings.Repeat(" ", gap) + right + rightBorder
+	}
+
+	var rows []string
+	if len(m.statsBlocks) == 0 {
+		rows = append(rows, row("  "+muted.Render("No Claude accounts found."), ""))
+		rows = append(rows, emptyRow)
+		return rows
+	}
+	rows = append(rows, row("  "+header.Render("Rolling 5-hour windows"),
+		faint.Render("as of "+m.statsBlocks[0].At.Local().Format("15:04"))))
+
+	const indent = "    "
+	for _, st := range m.statsBlocks {
+		rows = append(rows, emptyRow)
+		name := usage.AccountLabel(m.statsAccountLabels, st.Account)
+		b := st.Active
+		if b == nil {
+			rows = append(rows, row("  "+primaryBoldStyle.Render(name), muted.Render("idle")))
+			rows = append(rows, row(indent+dimStyle.Render("no calls in the current window"), ""))
+			continue
+		}
+		rows = append(rows, row("  "+primaryBoldStyle.Render(name),
+			muted.Render("resets "+b.End.Local().Format("15:04")+" · in "+statsDuration(b.End.Sub(st.At)))))
+
+		if st.Limit > 0 {
+			frac := float64(b.Total()) / float64(st.Limit)
+			rows = append(rows, row(indent+statsGauge(frac, lipgloss.NewStyle().Foreground(m.theme.Primary), dimStyle)+" "+
+				numStyle.Render(fmt.Sprintf("%3.0f%%", frac*100)), ""))
+		}
+
+		usd, priced := b.CostUSD()
+		cost := dollarFmt(usd)
+		if !priced {
+			cost = "~" + cost
+		}
+		rate := fmt.Sprintf("%s tokens · %s/min · %s/h", humanizeTokens(b.Total()),
+			humanizeTokens(int64(st.TokensPerMin)), dollarFmt(st.CostPerHour))
+		rows = append(rows, row(indent+numStyle.Render(rate), primaryBoldStyle.Render(cost)))
+
+		proj := fmt.Sprintf("at this pace %s · %s by the reset", humanizeTokens(st.ProjectedTotal), dollarFmt(st.ProjectedCost))
+		switch {
+		case st.ExhaustsAt.IsZero():
+			rows = append(rows, row(indent+dimStyle.Render(proj), ""))
+		case !st.ExhaustsAt.After(st.At):
+			rows = append(rows, row(indent+dimStyle.Render(proj), warn.Render("past the limit")))
+		default:
+			rows = append(rows, row(indent+dimStyle.Render(proj), warn.Render("limit ~"+st.ExhaustsAt.Local().Format("15:04"))))
+		}
+	}
 	rows = append(rows, emptyRow)
+	rows = append(rows, row("  "+faint.Render("Limit ≈ largest window of the past week · API-equivalent cost"), ""))
+	rows = append(rows, emptyRow)
+	return rows
+}
+
+// renderStatsToolRows renders the tools view of the Stats tab: the busiest
+// tools, then the MCP servers with their tools summed, each with its calls,
+// failure rate and share of the tokens spent on tool-calling turns, and the
+// sub-agent types spawned.
+func (m *MainMenuModel) renderStatsToolRows(leftBorder, rightBorder string) []string {
+	primaryBoldStyle := lipgloss.NewStyle().Foreground(m.theme.Primary).Bold(true)
+	header := lipgloss.NewStyle().Foreground(m.theme.Dim).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(m.theme.Dim)
+	numStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	faint := lipgloss.NewStyle().Faint(true)
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+
+	emptyRow := leftBorder + strings.Repeat(" ", menuContentWidth) + rightBorder
+	row := func(left, right string) string {
+		gap := menuContentWidth - lipgloss.Width(left) - lipgloss.Width(right)
+		if gap < 1 {
+			gap = 1
+		}
+		return leftBorder + left + strings.Repeat(" ", gap) + right + rightBorder
+	}
+
+	sum := m.statsTools
+	if sum.Total.Calls == 0 {
+		return []string{row("  "+muted.Render("No tool calls found yet."), ""), emptyRow}
+	}
+	tokens := sum.Total.Tokens
+	if tokens < 1 {
+		tokens = 1
+	}
+	// table renders a titled list: name, calls, failure rate, token share.
+	var rows []string
+	table := func(title string, list []usage.ToolUsage, limit int, nameStyle lipgloss.Style) {
+		rows = append(rows, row("  "+header.Render(fmt.Sprintf("%-30s %8s %8s", title, "Calls", "Failed")),
+			header.Render("Tokens")))
+		for i, t := range list {
+			if i == limit {
+				rows = append(rows, row("  "+faint.Render(fmt.Sprintf("+ %d more", len(list)-limit)), ""))
+				break
+			}
+			failed := dimStyle.Render(fmt.Sprintf("%8s", "—"))
+			if t.Errors > 0 {
+				failed = errStyle.Render(fmt.Sprintf("%7.1f%%", t.ErrorRate()*100))
+			}
+			left := "  " + nameStyle.Render(fmt.Sprintf("%-30s", TruncateMiddle(t.Name, 30))) + " " +
+				numStyle.Render(fmt.Sprintf("%8s", fmtThousands(t.Calls))) + " " + failed
+			rows = append(rows, row(left, muted.Render(fmt.Sprintf("%d%%", int(float64(t.Tokens)/float64(tokens)*100+0.5)))))
+		}
+	}
+
+	table("Tools", sum.Tools, statsTopTools, numStyle)
+	if len(sum.Servers) > 0 {
+		rows = append(rows, emptyRow)
+		table("MCP servers", sum.Servers, statsTopServers, primaryBoldStyle)
+	}
+	if len(sum.Agents) > 0 {
+		rows = append(rows, emptyRow)
+		spawns := make([]string, 0, len(sum.Agents))
+		for _, a := range sum.Agents {
+			spawns = append(spawns, fmt.Sprintf("%s %s", a.Name, fmtThousands(a.Calls)))
+		}
+		const label = "  Sub-agents  "
+		rows = append(rows, row(header.Render(label)+muted.Render(TruncateMiddle(strings.Join(spawns, " · "), menuContentWidth-len(label))), ""))
+	}
+	rows = append(rows, emptyRow)
+	rows = append(rows, row("  "+faint.Render(fmt.Sprintf("%s calls · %.1f%% failed · tokens: share of tool-calling turns",
+		fmtThousands(sum.Total.Calls), sum.Total.ErrorRate()*100)), ""))
+	rows = append(rows, emptyRow)
+	return rows
+}
+
+// renderStatsCacheRows renders the cache view of the Stats tab: the share of
+// input served from the prompt cache with what that saved against uncached
+// input, what was spent on cache writes never read back, and the same figures
+// for the latest months (each with its own gauge), the busiest models and the
+// busiest projects.
+func (m *MainMenuModel) renderStatsCacheRows(leftBorder, rightBorder string) []string {
+	primary := lipgloss.NewStyle().Foreground(m.theme.Primary)
+	header := lipgloss.NewStyle().Foreground(m.theme.Dim).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(m.theme.Dim)
+	numStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	faint := lipgloss.NewStyle().Faint(true)
+	good := lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+	warn := lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
+
+	emptyRow := leftBorder + strings.Repeat(" ", menuContentWidth) + rightBorder
+	row := func(left, right string) string {
+		gap := menuContentWidth - lipgloss.Width(left) - lipgloss.Width(right)
+		if gap < 1 {
+			gap = 1
+		}
+		return leftBorder + left + strings.Repeat(" ", gap) + right + rightBorder
+	}
 
+	c := m.statsCache
+	if c.total.CacheWrite+c.total.CacheRead == 0 {
+		return []string{row("  "+muted.Render("No prompt-cache use found yet."), ""), emptyRow}
+	}
+	// usd marks a figure that leaves out an unpriced model as approximate, and
+	// one with nothing priced at all as unknown.
+	usd := func(e usage.CacheEfficiency, v float64) string {
+		if !e.Priced && v == 0 {
+			return "—"
+		}
+		if !e.Priced {
+			return "~" + dollarFmt(v)
+		}
+		return dollarFmt(v)
+	}
+	pct := func(f float64) string { return fmt.Sprintf("%d%%", int(f*100+0.5)) }
+
+	var rows []string
+	t := c.total
+	rows = append(rows, row("  "+header.Render("Prompt cache"),
+		muted.Render("saved ")+good.Render(usd(t, t.SavedUSD))))
+	rows = append(rows, row("  "+statsGauge(t.HitRatio(), primary, dimStyle)+" "+
+		numStyle.Render(fmt.Sprintf("%4s", pct(t.HitRatio())))+muted.Render(" hit"), ""))
+	rows = append(rows, row("  "+muted.Render(fmt.Sprintf("%s read · %s written · %s never read ",
+		humanizeTokens(t.CacheRead), humanizeTokens(t.CacheWrite), pct(t.UnreadRatio())))+
+		warn.Render(usd(t, t.UnreadUSD)), ""))
+
+	// section renders up to statsTopCache entries under a column header; with
+	// gauges set each entry gets its hit-ratio meter on the line below.
+	section := func(title string, list []usage.CacheEfficiency, name func(string) string, gauges bool) {
+		rows = append(rows, emptyRow)
+		rows = append(rows, row("  "+header.Render(fmt.Sprintf("%-30s %6s %10s", title, "Hit", "Saved")),
+			header.Render("Unread")))
+		for i, e := range list {
+			if i == statsTopCache {
+				break
+			}
+			left := "  " + numStyle.Render(fmt.Sprintf("%-30s %6s", TruncateMiddle(name(e.Key), 30), pct(e.HitRatio()))) +
+				" " + good.Render(fmt.Sprintf("%10s", usd(e, e.SavedUSD)))
+			rows = append(rows, row(left, warn.Render(usd(e, e.UnreadUSD))))
+			if gauges {
+				rows = append(rows, row("  "+statsGauge(e.HitRatio(), primary, dimStyle), ""))
+			}
+		}
+	}
+	section("By month", c.months, monthLabel, true)
+	section("By model", c.models, func(k string) string { return k }, false)
+	section("By project", c.projects, func(k string) string {
+		if k == "" {
+			return "(unknown project)"
+		}
+		return filepath.Base(k)
+	}, false)
+
+	rows = append(rows, emptyRow)
+	rows = append(rows, row("  "+faint.Render(fmt.Sprintf("Hit: reads / all input · writes cost %s (1-hour %s) · API rates",
+		usd(t, t.WriteUSD), usd(t, t.Write1hUSD))), ""))
+	rows = append(rows, emptyRow)
+	return rows
+}
+
+// renderStatsValueRows renders the plan-value view of the Stats tab: for the
+// latest months, each subscribed account's usage priced at API rates against
+// its flat plan price, with a gauge that fills at break-even and a verdict.
+// Below half the plan's price the plan is more than the account needs.
+func (m *MainMenuModel) renderStatsValueRows(leftBorder, rightBorder string) []string {
+	primary := lipgloss.NewStyle().Foreground(m.theme.Primary)
+	primaryBoldStyle := primary.Bold(true)
+	header := lipgloss.NewStyle().Foreground(m.theme.Dim).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(m.theme.Dim)
+	numStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	faint := lipgloss.NewStyle().Faint(true)
+	good := lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+	warn := lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
+
+	emptyRow := leftBorder + strings.Repeat(" ", menuContentWidth) + rightBorder
+	row := func(left, right string) string {
+		gap := menuContentWidth - lipgloss.Width(left) - lipgloss.Width(right)
+		if gap < 1 {
+			gap = 1
+		}
+		return leftBorder + left + strings.Repeat(" ", gap) + right + rightBorder
+	}
+
+	if len(m.statsValues) == 0 {
+		return []string{
+			row("  "+muted.Render("No subscription plans recorded yet."), ""),
+			emptyRow,
+			row("  "+faint.Render("Set each login's plan with p in the Login panel (e.g. max-20x)."), ""),
+			emptyRow,
+		}
+	}
+
+	var rows []string
+	rows = append(rows, row("  "+header.Render("Plan value"), faint.Render("usage at API rates vs plan price")))
+	months := 0
+	for i, v := range m.statsValues {
+		if i == 0 || v.Month != m.statsValues[i-1].Month {
+			if months == statsTopValue {
+				break
+			}
+			months++
+			rows = append(rows, emptyRow)
+			rows = append(rows, row("  "+primaryBoldStyle.Render(monthLabel(v.Month)), ""))
+		}
+		name := usage.AccountLabel(m.statsAccountLabels, v.Account)
+		if len(name) > 16 {
+			name = name[:15] + "…"
+		}
+		value := dollarFmt(v.APIUSD)
+		if !v.Priced {
+			value = "~" + value
+		}
+		left := "    " + numStyle.Render(fmt.Sprintf("%-16s %-14s %8s", name, TruncateMiddle(v.Plan.String(), 14), value))
+		ratio := v.Ratio()
+		verdict := good.Render(fmt.Sprintf("%.1f× pays off", ratio))
+		switch {
+		case ratio < 0.5:
+			verdict = warn.Render(fmt.Sprintf("%.1f× over-provisioned", ratio))
+		case ratio < 1:
+			verdict = warn.Render(fmt.Sprintf("%.1f× below break-even", ratio))
+		}
+		rows = append(rows, row(left, verdict))
+		fill := primary
+		if ratio >= 1 {
+			fill = good
+		}
+		rows = append(rows, row("    "+statsGauge(ratio, fill, dimStyle), ""))
+	}
+
+	rows = append(rows, emptyRow)
+	rows = append(rows, row("  "+faint.Render("A full gauge is break-even · the current month is still running"), ""))
+	rows = append(rows, emptyRow)
 	return rows
 }
 
@@ -201,7 +690,7 @@ func (m *MainMenuModel) renderStatsBox() string {
 	if m.subscriptionRowCount() > 0 {
 		lines = append(lines, m.renderSubscriptionRow(leftBorder, rightBorder))
 	}
-	lines = append(lines, m.emptyMenuRow(leftBorder, rightBorder))
+	lines = append(lines, m.renderSwitcherGap(leftBorder, rightBorder))
 	lines = append(lines, m.renderTabBar(leftBorder, rightBorder))
 	lines = append(lines, separator)
 	lines = append(lines, m.renderStatsRows(leftBorder, rightBorder)...)
@@ -256,6 +256,9 @@ func TestRenderStatsBox_costColumnRightAligned(t *testing.T) {
 	var totalRow, modelRow, barRow, estRow string
 	for _, l := range lines {
 		p := stripANSI(l)
+		if strings.Contains(p, "Model mix") {
+			break // the charts under the table align on their own
+		}
 		switch {
 		case strings.Contains(p, "Jun 2026"):
 			totalRow = l
@@ -354,10 +357,10 @@ func TestRenderStatsBox_containsStatsRows(t *testing.T) {
 }
 
 // TestRenderStatsBox_LoadingState verifies a fresh model shows the loading text
-// when statsLoading is set but no data is cached yet.
+// when stats.loading is set but no data is cached yet.
 func TestRenderStatsBox_LoadingState(t *testing.T) {
 	m := NewMainMenu(nil, []string{"claude"}, "claude", "none")
-	m.statsLoading = true
+	m.stats.loading = true
 	m.SetActiveTab(TabStats)
 	out := m.renderStatsBox()
 	if !strings.Contains(out, "Crunching") {
@@ -388,15 +391,15 @@ func TestRenderStatsBox_DataState(t *testing.T) {
 }
 
 // TestHandleRune_TReturnsNonNilCmdAndSetsLoading verifies that pressing 't'
-// kicks off the async load (returns a non-nil cmd) and sets statsLoading true.
+// kicks off the async load (returns a non-nil cmd) and sets stats.loading true.
 func TestHandleRune_TReturnsNonNilCmdAndSetsLoading(t *testing.T) {
 	m := NewMainMenu(nil, []string{"claude"}, "claude", "none")
 	_, cmd := m.handleRune('t')
 	if m.ActiveTab() != TabStats {
 		t.Errorf("after 't' activeTab = %v, want TabStats", m.ActiveTab())
 	}
-	if !m.statsLoading {
-		t.Error("after 't' statsLoading should be true")
+	if !m.stats.loading {
+		t.Error("after 't' stats.loading should be true")
 	}
 	if cmd == nil {
 		t.Error("after 't' cmd should be non-nil (async load triggered)")
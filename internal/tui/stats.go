@@ -17,6 +17,27 @@ const (
 	statsGaugeW = 46 // gauge length (columns); spans the data columns up to Cache R's right edge
 	statsColEnd = 66 // right edge of the Total column; money/totals align here
 	statsWindow = 8  // months visible at once before scrolling
+
+	statsTopSessions   = 20 // most expensive conversations listed in the Stats tab
+	statsSessionWindow = 6  // conversations visible at once before scrolling
+	statsTopTools      = 8  // busiest tools listed in the tools view
+	statsTopServers    = 6  // busiest MCP servers listed in the tools view
+	statsTopCache      = 3  // months, models and projects listed in the cache view
+	statsTopValue      = 3  // months listed in the plan-value view
+
+	statsRefreshInterval = 3 * time.Second // how often the live Stats screen rescans
+)
+
+// statsView selects what the main menu's Stats tab shows.
+type statsView int
+
+const (
+	statsViewMonths   statsView = iota // token usage by month
+	statsViewSessions                  // most expensive conversations
+	statsViewBlocks                    // each account's rolling 5-hour window
+	statsViewTools                     // tool calls, MCP servers and sub-agents
+	statsViewCache                     // prompt-cache hit ratio and savings
+	statsViewValue                     // API-equivalent value of each subscription
 )
 
 // monthLabel turns a "YYYY-MM" bucket into a human label like "Jun 2026". Other
@@ -44,17 +65,29 @@ func humanizeTokens(n int64) string {
 	}
 }
 
-// StatsModel renders monthly token usage as a scrollable table + bar chart.
+// StatsModel renders monthly token usage as an interactive dashboard: a table
+// of months with a daily bar chart of the selected month and a stacked share
+// bar of its models beneath. Enter drills into the month's days, then a day's
+// projects, then a project's conversations; esc climbs back out. It stays
+// live: once loaded it rescans every statsRefreshInterval through watcher,
+// which re-reads only the transcripts that grew, and today heads the table.
 type StatsModel struct {
 	months      []usage.MonthlyUsage
+	records     []usage.Record       // the drill-down levels and the daily chart fold these
+	sessions    []usage.SessionUsage // conversations, for the deepest level
+	today       usage.GroupUsage
+	updated     time.Time // when the shown figures were scanned; zero for static data
+	watcher     *usage.Watcher
 	loading     bool
+	progress    usage.Progress
 	err         error
+	crumbs      []statsCrumb // rows drilled into, outermost first; empty on the months table
+	cursor      int          // selected row of the current level
 	offset      int
+	hover       int // row under the pointer, or -1 (transient; never moves the cursor)
 	width       int
 	height      int
-	claudeDirs  []string
-	opencodeDir string
-	cachePath   string
+	pricingPath string
 }
 
 // SetSize records the terminal dimensions so View can center the box. Callers
@@ -68,7 +101,20 @@ func (m *StatsModel) SetSize(width, height int) {
 // NewStatsModelWithData builds a ready-to-render model (no async load). For tests
 // and any caller that already has aggregated data.
 func NewStatsModelWithData(months []usage.MonthlyUsage) StatsModel {
-	return StatsModel{months: months, loading: false}
+	return StatsModel{months: months, loading: false, hover: -1}
+}
+
+// fmtThousands renders n with comma thousands separators.
+func fmtThousands(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	var out []byte
+	for i := 0; i < len(s); i++ {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			out = append(out, ',')
+		}
+		out = append(out, s[i])
+	}
+	return string(out)
 }
 
 // dollarFmt renders a USD estimate: cents under $1, comma-grouped whole dollars
@@ -78,15 +124,7 @@ func dollarFmt(usd float64) string {
 	case usd < 1:
 		return fmt.Sprintf("$%.2f", usd)
 	case usd < 10000:
-		s := strconv.FormatInt(int64(usd+0.5), 10)
-		var out []byte
-		for i := 0; i < len(s); i++ {
-			if i > 0 && (len(s)-i)%3 == 0 {
-				out = append(out, ',')
-			}
-			out = append(out, s[i])
-		}
-		return "$" + string(out)
+		return "$" + fmtThousands(int64(usd+0.5))
 	case usd < 1_000_000:
 		return fmt.Sprintf("$%.1fK", usd/1000)
 	default:
@@ -94,6 +132,23 @@ func dollarFmt(usd float64) string {
 	}
 }
 
+// statsCacheData is the cache view's figures: overall, then by month (newest
+// first), model and project (most tokens first).
+type statsCacheData struct {
+	total                    usage.CacheEfficiency
+	months, models, projects []usage.CacheEfficiency
+}
+
+// newStatsCacheData derives the cache view's figures from every record.
+func newStatsCacheData(records []usage.Record) statsCacheData {
+	return statsCacheData{
+		total:    usage.CacheEfficiencyTotal(records),
+		months:   usage.CacheEfficiencyBy(records, usage.ByMonth),
+		models:   usage.CacheEfficiencyBy(records, usage.ByModel),
+		projects: usage.CacheEfficiencyBy(records, usage.ByProject),
+	}
+}
+
 // statsGrandTotal sums every month into one MonthlyUsage labelled "Total".
 func statsGrandTotal(months []usage.MonthlyUsage) usage.MonthlyUsage {
 	g := usage.MonthlyUsage{Month: "Total"}
@@ -185,6 +240,26 @@ func (m StatsModel) center(content string) string {
 }
 
 func (m StatsModel) View() string {
+	frame, _ := m.render()
+	return m.center(frame)
+}
+
+// statsHint is the key legend under the table, naming what Enter opens next.
+func (m StatsModel) statsHint() string {
+	hint := "↑↓ select · esc back"
+	if next := m.drillNext(); next != "" {
+		hint = "↑↓ select · ⏎ " + next + " · esc back"
+	}
+	if !m.updated.IsZero() {
+		hint += " · live, updated " + m.updated.Local().Format("15:04:05")
+	}
+	return hint
+}
+
+// render draws the box and maps each of its lines to the row of statsItems it
+// belongs to (statsHitBack for the breadcrumb, -1 for none), so the pointer
+// hits exactly what the eye sees.
+func (m StatsModel) render() (string, []int) {
 	border := lipgloss.NewStyle().Foreground(currentTheme.Dim)
 	primary := lipgloss.NewStyle().Foreground(currentTheme.Primary)
 	primaryBold := lipgloss.NewStyle().Foreground(currentTheme.Primary).Bold(true)
@@ -192,7 +267,7 @@ func (m StatsModel) View() string {
 	num := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
 	muted := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
 	faint := lipgloss.NewStyle().Faint(true)
-	hint := faint.Render("↑↓ scroll · esc back")
+	hint := faint.Render(m.statsHint())
 
 	// One-line message states (error / loading / empty) share the same frame.
 	message := func(body string) string {
@@ -204,13 +279,49 @@ func (m StatsModel) View() string {
 		})
 	}
 	if m.err != nil {
-		return m.center(message(lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Render("Failed to load usage: " + m.err.Error())))
+		return message(lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Render("Failed to load usage: " + m.err.Error())), nil
 	}
 	if m.loading {
-		return m.center(message(primary.Render("Crunching token usage…")))
+		body := []string{statsBoxLine("", border), statsBoxLine("  "+primary.Render("Crunching token usage…"), border)}
+		for _, r := range statsProgressRows(m.progress, primary, border, faint) {
+			body = append(body, statsBoxLine("  "+r, border))
+		}
+		body = append(body, statsBoxLine("", border), statsBoxLine("  "+hint, border))
+		return statsFrame(body), nil
 	}
 	if len(m.months) == 0 {
-		return m.center(message(muted.Render("No usage data found yet.")))
+		return message(muted.Render("No usage data found yet.")), nil
+	}
+
+	// hits starts with the top border's line.
+	var body []string
+	hits := []int{-1}
+	add := func(line string, item int) {
+		body = append(body, line)
+		hits = append(hits, item)
+	}
+	rows := statsRows{box: func(content string) string { return statsBoxLine(content, border) }, sep: statsSep(border), add: add}
+	add(statsBoxLine("", border), -1)
+	if !m.updated.IsZero() {
+		// Today's headline ticks up with every rescan while a session runs. Its
+		// day is UTC like every day in the records, so say so.
+		cost, allPriced := m.today.CostUSD()
+		costLabel := dollarFmt(cost)
+		if !allPriced {
+			costLabel = "~" + costLabel
+		}
+		left := "  " + header.Render("Today so far") + muted.Render(" (UTC)") + "  " + primaryBold.Render(humanizeTokens(m.today.Total())) +
+			muted.Render(" tokens")
+		add(statsBoxLine(statsRightAlign(left, primaryBold.Render(costLabel)), border), -1)
+		add(statsBoxLine("", border), -1)
+	}
+	items := m.statsItems()
+	if m.level() > statsLevelMonths {
+		m.drillRows(items, rows)
+		m.chartRows(items, rows)
+		add(statsBoxLine("", border), -1)
+		add(statsBoxLine("  "+hint, border), -1)
+		return statsFrame(body), append(hits, -1)
 	}
 
 	grandTotal := statsGrandTotal(m.months).Total()
@@ -221,33 +332,23 @@ func (m StatsModel) View() string {
 	if end > len(m.months) {
 		end = len(m.months)
 	}
-
-	var body []string
-	body = append(body, statsBoxLine("", border))
-	body = append(body, statsBoxLine(
-		statsCols("Month", "Input", "Output", "Cache W", "Cache R", "Total", header, header, header), border))
-	body = append(body, statsSep(border))
+	add(statsBoxLine(
+		statsCols("Month", "Input", "Output", "Cache W", "Cache R", "Total", header, header, header), border), -1)
+	add(statsSep(border), -1)
 	var grandCost float64
 	grandAllPriced := true
-	// rightAlign places a left chunk and a right chunk on one inner row with the
-	// right chunk's edge landing on statsColEnd (under the Total column), so every
-	// gauge cost, model cost and grand total stacks into one clean money column.
-	rightAlign := func(left, right string) string {
-		pad := statsColEnd - lipgloss.Width(left) - lipgloss.Width(right)
-		if pad < 1 {
-			pad = 1
-		}
-		return left + strings.Repeat(" ", pad) + right
-	}
-	for idx, mu := range m.months[m.offset:end] {
-		if idx > 0 {
+	for i := m.offset; i < end; i++ {
+		mu := m.months[i]
+		if i > m.offset {
 			// Blank spacer sets each month block apart so they don't cluster.
-			body = append(body, statsBoxLine("", border))
+			add(statsBoxLine("", border), -1)
 		}
 		row := statsCols(mu.Month,
 			humanizeTokens(mu.Input), humanizeTokens(mu.Output),
 			humanizeTokens(mu.CacheWrite), humanizeTokens(mu.CacheRead),
 			humanizeTokens(mu.Total()), num, num, primaryBold)
+		// The selection marker takes the row's two-space indent.
+		row = m.statsItemMarker(i) + row[2:]
 		frac := float64(mu.Total()) / float64(grandTotal)
 		pct := int(frac*100 + 0.5)
 		monthCost, allPriced := mu.CostUSD()
@@ -255,17 +356,18 @@ func (m StatsModel) View() string {
 		if !allPriced {
 			costLabel = "~" + costLabel
 		}
-		// Gauge + inline percent on the left, the month's cost aligned right.
+		// Gauge + inline percent on the left, the month's cost aligned right so
+		// every gauge cost, model cost and grand total stacks into one column.
 		gaugeLeft := "  " + statsGauge(frac, primary, border) + " " +
 			faint.Render(fmt.Sprintf("%3d%%", pct))
-		barRow := rightAlign(gaugeLeft, primaryBold.Render(costLabel))
-		body = append(body, statsBoxLine(row, border))
-		body = append(body, statsBoxLine(barRow, border))
+		barRow := statsRightAlign(gaugeLeft, primaryBold.Render(costLabel))
+		add(statsBoxLine(row, border), i)
+		add(statsBoxLine(barRow, border), i)
 		// Per-model breakdown, drawn as a tree. Tokens align under Cache R and cost
 		// under Total so the breakdown reads as columns, not a clump.
-		for i, md := range mu.Models {
+		for j, md := range mu.Models {
 			connector := "├─ "
-			if i == len(mu.Models)-1 {
+			if j == len(mu.Models)-1 {
 				connector = "└─ "
 			}
 			label := strings.TrimPrefix(md.Model, "claude-")
@@ -281,7 +383,7 @@ func (m StatsModel) View() string {
 				muted.Render(fmt.Sprintf("%-28s", label)) +
 				muted.Render(fmt.Sprintf("%8s", humanizeTokens(md.Total()))) + "       " +
 				primary.Render(fmt.Sprintf("%9s", cost))
-			body = append(body, statsBoxLine(mrow, border))
+			add(statsBoxLine(mrow, border), i)
 		}
 	}
 	// Grand total cost spans every month (not just the visible window).
@@ -292,55 +394,167 @@ func (m StatsModel) View() string {
 			grandAllPriced = false
 		}
 	}
-	body = append(body, statsSep(border))
+	add(statsSep(border), -1)
 	g := statsGrandTotal(m.months)
-	body = append(body, statsBoxLine(statsCols(g.Month,
+	add(statsBoxLine(statsCols(g.Month,
 		humanizeTokens(g.Input), humanizeTokens(g.Output),
 		humanizeTokens(g.CacheWrite), humanizeTokens(g.CacheRead),
-		humanizeTokens(g.Total()), primaryBold, primaryBold, primaryBold), border))
+		humanizeTokens(g.Total()), primaryBold, primaryBold, primaryBold), border), -1)
 	grandCostStr := dollarFmt(grandCost)
 	if !grandAllPriced {
 		grandCostStr = "~" + grandCostStr
 	}
 	costLeft := "  " + header.Render("Total est. cost")
-	body = append(body, statsBoxLine(rightAlign(costLeft, primaryBold.Render(grandCostStr)), border))
-	body = append(body, statsBoxLine("", border))
-	body = append(body, statsBoxLine("  "+hint, border))
-	return m.center(statsFrame(body))
+	add(statsBoxLine(statsRightAlign(costLeft, primaryBold.Render(grandCostStr)), border), -1)
+	m.chartRows(items, rows)
+	add(statsBoxLine("", border), -1)
+	add(statsBoxLine("  "+hint, border), -1)
+	return statsFrame(body), append(hits, -1)
 }
 
-type statsLoadedMsg struct{ months []usage.MonthlyUsage }
+// statsLoadedMsg carries one scan's figures to StatsModel, standalone or as
+// the main menu's Stats tab (see statsTabLoadedMsg).
+type statsLoadedMsg struct {
+	months   []usage.MonthlyUsage
+	records  []usage.Record
+	sessions []usage.SessionUsage
+	today    usage.GroupUsage
+	at       time.Time
+}
 type statsErrMsg struct{ err error }
 
+// statsProgressMsg relays a cold scan's parsing progress; ch delivers the next
+// message, more progress or the result.
+type statsProgressMsg struct {
+	progress usage.Progress
+	ch       <-chan tea.Msg
+}
+
+// loadStatsCmd runs load in the background and returns the command that
+// relays its progress reports and then its result. A report that arrives while
+// the previous one is still unread is dropped; only the latest matters.
+func loadStatsCmd(load func(progress func(usage.Progress)) tea.Msg) tea.Cmd {
+	ch := make(chan tea.Msg, 1)
+	go func() {
+		ch <- load(func(p usage.Progress) {
+			select {
+			case ch <- statsProgressMsg{progress: p, ch: ch}:
+			default:
+			}
+		})
+	}()
+	return waitStatsCmd(ch)
+}
+
+// waitStatsCmd waits for the loader's next message.
+func waitStatsCmd(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg { return <-ch }
+}
+
+// humanizeBytes renders a byte count as a compact string (512 B, 1.5 MB, 2.0 GB).
+func humanizeBytes(n int64) string {
+	switch {
+	case n < 1000:
+		return fmt.Sprintf("%d B", n)
+	case n < 1_000_000:
+		return fmt.Sprintf("%.1f KB", float64(n)/1000)
+	case n < 1_000_000_000:
+		return fmt.Sprintf("%.1f MB", float64(n)/1_000_000)
+	default:
+		return fmt.Sprintf("%.1f GB", float64(n)/1_000_000_000)
+	}
+}
+
+// statsProgressRows renders a cold scan's progress under the loading message:
+// a gauge of the bytes parsed, then the file and byte counts. Nothing while no
+// file needs parsing, so a warm cache shows just the message.
+func statsProgressRows(p usage.Progress, fill, track, text lipgloss.Style) []string {
+	if p.FilesTotal == 0 {
+		return nil
+	}
+	return []string{
+		statsGauge(p.Fraction(), fill, track) + " " + text.Render(fmt.Sprintf("%3d%%", int(p.Fraction()*100))),
+		text.Render(fmt.Sprintf("%d of %d files · %s of %s", p.FilesDone, p.FilesTotal,
+			humanizeBytes(p.BytesDone), humanizeBytes(p.BytesTotal))),
+	}
+}
+
 // NewStatsModel builds a model that loads usage asynchronously on Init.
 func NewStatsModel() StatsModel {
 	home, _ := os.UserHomeDir()
-	_, opencodeDir, cachePath := usage.DefaultPaths(home)
-	claudeDirs := usage.ClaudeAccountProjectDirs(home)
-	return StatsModel{loading: true, claudeDirs: claudeDirs, opencodeDir: opencodeDir, cachePath: cachePath}
+	_, _, cachePath := usage.DefaultPaths(home)
+	return StatsModel{loading: true, hover: -1, pricingPath: usage.PricingPath(home),
+		watcher: usage.NewWatcher(usage.DefaultSources(home), cachePath)}
 }
 
 func (m StatsModel) Init() tea.Cmd {
 	if !m.loading {
 		return nil
 	}
-	claudeDirs, opencodeDir, cachePath := m.claudeDirs, m.opencodeDir, m.cachePath
-	return func() tea.Msg {
-		months, err := usage.AggregateAll(claudeDirs, opencodeDir, cachePath)
-		if err != nil {
+	w, pricingPath := m.watcher, m.pricingPath
+	return loadStatsCmd(func(progress func(usage.Progress)) tea.Msg {
+		if err := usage.LoadPricing(pricingPath); err != nil {
 			return statsErrMsg{err: err}
 		}
-		return statsLoadedMsg{months: months}
+		return refreshStats(w, progress)
+	})
+}
+
+// statsTickMsg asks the live Stats screen to rescan.
+type statsTickMsg struct{}
+
+// refreshStats rescans through w and returns the figures the Stats screen
+// shows, today's being the current UTC day like every day in the records.
+func refreshStats(w *usage.Watcher, progress func(usage.Progress)) tea.Msg {
+	snap, err := w.Refresh(progress)
+	if err != nil {
+		return statsErrMsg{err: err}
 	}
+	return newStatsLoadedMsg(snap, time.Now())
+}
+
+// newStatsLoadedMsg folds a snapshot scanned at now into the figures
+// StatsModel shows.
+func newStatsLoadedMsg(snap *usage.Snapshot, now time.Time) statsLoadedMsg {
+	return statsLoadedMsg{months: usage.Months(snap.Records), records: snap.Records, sessions: snap.Sessions,
+		today: usage.DayUsage(snap.Records, now.UTC().Format("2006-01-02")), at: now}
+}
+
+// statsTickCmd schedules the next rescan.
+func statsTickCmd() tea.Cmd {
+	return tea.Tick(statsRefreshInterval, func(time.Time) tea.Msg { return statsTickMsg{} })
 }
 
 func (m StatsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case statsProgressMsg:
+		m.progress = msg.progress
+		return m, waitStatsCmd(msg.ch)
 	case statsLoadedMsg:
 		m.months = msg.months
+		m.records = msg.records
+		m.sessions = msg.sessions
+		m.today = msg.today
+		m.updated = msg.at
 		m.loading = false
-		return m, nil
+		// A rescan can shrink the level drilled into; keep the cursor on a row.
+		m.clampCursor(len(m.statsItems()))
+		if m.watcher == nil {
+			return m, nil
+		}
+		return m, statsTickCmd()
+	case statsTickMsg:
+		if m.watcher == nil || m.err != nil {
+			return m, nil
+		}
+		w := m.watcher
+		return m, func() tea.Msg { return refreshStats(w, nil) }
 	case statsErrMsg:
+		if !m.updated.IsZero() && m.watcher != nil {
+			// A failed live rescan keeps the last good figures, their "updated"
+			// time going stale, and the next tick tries again.
+			return m, statsTickCmd()
+		}
 		m.err = msg.err
 		m.loading = false
 		return m, nil
@@ -348,23 +562,31 @@ func (m StatsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		return m, nil
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyEsc:
+			if m.climbOut() {
+				return m, nil
+			}
 			return m, func() tea.Msg { return PopScreenMsg{} }
+		case tea.KeyEnter:
+			m.drillIn()
+			return m, nil
 		case tea.KeyUp:
-			m.scrollUp()
+			m.moveCursor(-1)
 			return m, nil
 		case tea.KeyDown:
-			m.scrollDown()
+			m.moveCursor(1)
 			return m, nil
 		case tea.KeyRunes:
 			if len(msg.Runes) == 1 {
 				switch msg.Runes[0] {
 				case 'k':
-					m.scrollUp()
+					m.moveCursor(-1)
 				case 'j':
-					m.scrollDown()
+					m.moveCursor(1)
 				}
 			}
 			return m, nil
@@ -372,15 +594,3 @@ func (m StatsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 	return m, nil
 }
-
-func (m *StatsModel) scrollUp() {
-	if m.offset > 0 {
-		m.offset--
-	}
-}
-
-func (m *StatsModel) scrollDown() {
-	if m.offset < len(m.months)-statsWindow {
-		m.offset++
-	}
-}
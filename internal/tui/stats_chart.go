@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jackuait/wisp-deck/internal/usage"
+)
+
+const (
+	statsChartH      = 4  // rows of the daily bar chart; each row has eight steps
+	statsShareModels = 4  // models colored in the share bar before the rest fold into "other"
+	statsShareLabelW = 18 // longest model label in the share bar's legend
+)
+
+// statsChartBlocks are the eighth-steps of one chart cell, empty to full.
+var statsChartBlocks = []string{" ", "▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+
+// statsDailyChart draws a month (YYYY-MM) of daily groups as vertical bars,
+// one column per day with a gap between, day 1 on the left, followed by a day
+// axis. The selected day (YYYY-MM-DD, or "") is drawn in hi and the others in
+// fill; days without usage leave a dotted baseline. It also returns the
+// busiest day and its tokens for the caption. No lines when the month has no
+// usage.
+func statsDailyChart(month string, days []usage.GroupUsage, selected string, fill, hi, faint lipgloss.Style) ([]string, string, int64) {
+	start, err := time.Parse("2006-01", month)
+	if err != nil {
+		return nil, "", 0
+	}
+	n := start.AddDate(0, 1, -1).Day()
+	totals := make([]int64, n)
+	var peak int64
+	peakDay := ""
+	for _, d := range days {
+		t, err := time.Parse("2006-01-02", d.Key)
+		if err != nil || t.Format("2006-01") != month {
+			continue
+		}
+		totals[t.Day()-1] += d.Total()
+		if v := totals[t.Day()-1]; v > peak {
+			peak, peakDay = v, d.Key
+		}
+	}
+	if peak == 0 {
+		return nil, "", 0
+	}
+
+	lines := make([]string, 0, statsChartH+1)
+	for r := 0; r < statsChartH; r++ {
+		floor := (statsChartH - 1 - r) * 8 // eighths below this row
+		var b strings.Builder
+		for i, v := range totals {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			eighths := int(float64(v)/float64(peak)*statsChartH*8 + 0.5)
+			if v > 0 && eighths == 0 {
+				eighths = 1 // a day with any usage stays visible
+			}
+			style := fill
+			if start.AddDate(0, 0, i).Format("2006-01-02") == selected {
+				style = hi
+			}
+			switch {
+			case eighths >= floor+8:
+				b.WriteString(style.Render("█"))
+			case eighths > floor:
+				b.WriteString(style.Render(statsChartBlocks[eighths-floor]))
+			case floor == 0:
+				b.WriteString(faint.Render("·"))
+			default:
+				b.WriteString(" ")
+			}
+		}
+		lines = append(lines, b.String())
+	}
+
+	axis := []rune(strings.Repeat(" ", 2*n)) // room for a two-digit last label
+	for _, d := range []int{1, 5, 10, 15, 20, 25, 30} {
+		if d > n {
+			break
+		}
+		copy(axis[(d-1)*2:], []rune(strconv.Itoa(d)))
+	}
+	lines = append(lines, faint.Render(string(axis)))
+	return lines, peakDay, peak
+}
+
+// statsShareColors are the share bar's segment colors, largest model first;
+// the folded "other" segment takes the last.
+func statsShareColors() []lipgloss.Color {
+	return []lipgloss.Color{currentTheme.Primary, "39", "114", "213", "245"}
+}
+
+// statsShareBar splits statsGaugeW columns between models by their share of
+// the tokens (models arrive sorted largest first), each in its own color, and
+// returns the bar with a legend wrapped to width columns. Models past
+// statsShareModels fold into one "other" segment.
+func statsShareBar(models []usage.ModelUsage, width int, text lipgloss.Style) (string, []string) {
+	var total int64
+	for _, md := range models {
+		total += md.Total()
+	}
+	if total == 0 {
+		return "", nil
+	}
+	type segment struct {
+		label  string
+		tokens int64
+	}
+	var segs []segment
+	for i, md := range models {
+		if i == statsShareModels && len(models) > statsShareModels+1 {
+			var rest int64
+			for _, o := range models[i:] {
+				rest += o.Total()
+			}
+			segs = append(segs, segment{"other", rest})
+			break
+		}
+		segs = append(segs, segment{strings.TrimPrefix(md.Model, "claude-"), md.Total()})
+	}
+
+	colors := statsShareColors()
+	var bar strings.Builder
+	var legend []string
+	line := ""
+	var cum int64
+	for i, s := range segs {
+		// Round the running edges, not each width, so the segments always sum
+		// to the full gauge.
+		from := int(float64(cum)/float64(total)*statsGaugeW + 0.5)
+		cum += s.tokens
+		to := int(float64(cum)/float64(total)*statsGaugeW + 0.5)
+		color := colors[len(colors)-1]
+		if s.label != "other" && i < len(colors)-1 {
+			color = colors[i]
+		}
+		swatch := lipgloss.NewStyle().Foreground(color)
+		bar.WriteString(swatch.Render(strings.Repeat("█", to-from)))
+
+		label := s.label
+		if lipgloss.Width(label) > statsShareLabelW {
+			label = string([]rune(label)[:statsShareLabelW-1]) + "…"
+		}
+		entry := swatch.Render("■") + " " + text.Render(fmt.Sprintf("%s %d%%", label, int(float64(s.tokens)/float64(total)*100+0.5)))
+		switch {
+		case line == "":
+			line = entry
+		case lipgloss.Width(line)+3+lipgloss.Width(entry) > width:
+			legend = append(legend, line)
+			line = entry
+		default:
+			line += "   " + entry
+		}
+	}
+	legend = append(legend, line)
+	return bar.String(), legend
+}
@@ -0,0 +1,453 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jackuait/wisp-deck/internal/usage"
+)
+
+// statsLevel is how far the Stats screen has drilled in: the months table, a
+// month's days, a day's projects, or a project's conversations that day.
+type statsLevel int
+
+const (
+	statsLevelMonths statsLevel = iota
+	statsLevelDays
+	statsLevelProjects
+	statsLevelSessions
+)
+
+// statsHitBack marks the breadcrumb line in render's hit map; clicking it
+// climbs back out a level like esc.
+const statsHitBack = -2
+
+// statsCrumb is one drill step: the key of the row entered, and the cursor and
+// scroll offset to restore when esc climbs back out of it.
+type statsCrumb struct {
+	key    string
+	cursor int
+	offset int
+}
+
+// level is the depth the crumbs have drilled to.
+func (m StatsModel) level() statsLevel {
+	return statsLevel(len(m.crumbs))
+}
+
+// recordsWhere keeps the records keep accepts.
+func (m StatsModel) recordsWhere(keep func(usage.Record) bool) []usage.Record {
+	var out []usage.Record
+	for _, r := range m.records {
+		if keep(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// statsItems lists the rows of the current level folded into GroupUsage, keyed
+// by month, day, project path or session id: months newest first, days newest
+// first, projects and conversations largest first.
+func (m StatsModel) statsItems() []usage.GroupUsage {
+	switch m.level() {
+	case statsLevelMonths:
+		items := make([]usage.GroupUsage, len(m.months))
+		for i, mu := range m.months {
+			items[i] = usage.GroupUsage{Key: mu.Month, Input: mu.Input, Output: mu.Output,
+				CacheWrite: mu.CacheWrite, CacheRead: mu.CacheRead, Models: mu.Models}
+		}
+		return items
+	case statsLevelDays:
+		month := m.crumbs[0].key
+		return usage.GroupRecords(m.recordsWhere(func(r usage.Record) bool { return r.Month() == month }), usage.ByDay)
+	case statsLevelProjects:
+		day := m.crumbs[1].key
+		return usage.GroupRecords(m.recordsWhere(func(r usage.Record) bool { return r.Day == day }), usage.ByProject)
+	}
+	// A conversation's row is what it used in the project that day, from its
+	// records, so the rows add up to the project's row above; what no live
+	// conversation holds (other tools, pruned transcripts) is one more row.
+	day, project := m.crumbs[1].key, m.crumbs[2].key
+	inProject := func(r usage.Record) bool { return r.Day == day && r.Project == project }
+	var items []usage.GroupUsage
+	for _, s := range m.sessions {
+		var own []usage.Record
+		for _, r := range s.Records {
+			if inProject(r) {
+				own = append(own, r)
+			}
+		}
+		if g := usage.GroupRecords(own, usage.ByDay); len(g) > 0 {
+			g[0].Key = s.ID
+			items = append(items, g[0])
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Total() > items[j].Total() })
+	if rest := usage.GroupRecords(usage.UnattributedRecords(m.recordsWhere(inProject), m.sessions), usage.ByDay); len(rest) > 0 {
+		rest[0].Key = statsKeyUnattributed
+		items = append(items, rest[0])
+	}
+	return items
+}
+
+// statsKeyUnattributed keys the conversations level's row for the usage no
+// live conversation holds; session ids are never empty.
+const statsKeyUnattributed = ""
+
+// chartMonth is the month the daily chart shows: the month under the cursor on
+// the months table, else the month drilled into.
+func (m StatsModel) chartMonth(items []usage.GroupUsage) string {
+	if m.level() > statsLevelMonths {
+		return m.crumbs[0].key
+	}
+	if m.cursor < len(items) {
+		return items[m.cursor].Key
+	}
+	return ""
+}
+
+// chartDay is the day the daily chart highlights: the day under the cursor on
+// the days table, the day drilled into below it, or none on the months table.
+func (m StatsModel) chartDay(items []usage.GroupUsage) string {
+	switch {
+	case m.level() > statsLevelDays:
+		return m.crumbs[1].key
+	case m.level() == statsLevelDays && m.cursor < len(items):
+		return items[m.cursor].Key
+	}
+	return ""
+}
+
+// statsDayLabel turns a "YYYY-MM-DD" day into "Tue Jun 2".
+func statsDayLabel(day string) string {
+	t, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		return day
+	}
+	return t.Format("Mon Jan 2")
+}
+
+// statsProjectLabel names a project by its directory's base name.
+func statsProjectLabel(path string) string {
+	if path == "" {
+		return "(unknown project)"
+	}
+	return filepath.Base(path)
+}
+
+// itemLabel names a row of the current level.
+func (m StatsModel) itemLabel(key string) string {
+	switch m.level() {
+	case statsLevelMonths:
+		return monthLabel(key)
+	case statsLevelDays:
+		return statsDayLabel(key)
+	case statsLevelProjects:
+		return statsProjectLabel(key)
+	}
+	if key == statsKeyUnattributed {
+		return "(no live conversation)"
+	}
+	for _, s := range m.sessions {
+		if s.ID == key {
+			id := s.ID
+			if len(id) > 8 {
+				id = id[:8]
+			}
+			return s.First.Local().Format("15:04") + "–" + s.Last.Local().Format("15:04") + "  " + id
+		}
+	}
+	return key
+}
+
+// breadcrumb is the drill path back to the month, e.g. "Jun 2026 › Tue Jun 2 ›
+// wisp-deck".
+func (m StatsModel) breadcrumb() string {
+	parts := make([]string, len(m.crumbs))
+	for i, c := range m.crumbs {
+		switch statsLevel(i) {
+		case statsLevelMonths:
+			parts[i] = monthLabel(c.key)
+		case statsLevelDays:
+			parts[i] = statsDayLabel(c.key)
+		default:
+			parts[i] = statsProjectLabel(c.key)
+		}
+	}
+	return strings.Join(parts, " › ")
+}
+
+// statsRows is where drillRows and chartRows draw, so the Stats screen and
+// the main menu's Stats tab share them: box wraps a line's content in the
+// surface's borders, sep is its inner rule, and add appends a finished line
+// with the item it belongs to.
+type statsRows struct {
+	box func(content string) string
+	sep string
+	add func(line string, item int)
+}
+
+// line adds content boxed, belonging to item.
+func (r statsRows) line(content string, item int) { r.add(r.box(content), item) }
+
+// rule adds the inner rule.
+func (r statsRows) rule() { r.add(r.sep, -1) }
+
+// drillNext names what Enter opens from the current level, or "" on the
+// deepest.
+func (m StatsModel) drillNext() string {
+	return map[statsLevel]string{statsLevelMonths: "days", statsLevelDays: "projects", statsLevelProjects: "conversations"}[m.level()]
+}
+
+// statsItemMarker is a row's leading two columns: a bright ▸ on the keyboard
+// cursor, a dim one under the pointer, blank otherwise.
+func (m StatsModel) statsItemMarker(i int) string {
+	switch i {
+	case m.cursor:
+		return lipgloss.NewStyle().Foreground(currentTheme.Primary).Bold(true).Render("▸") + " "
+	case m.hover:
+		return lipgloss.NewStyle().Foreground(currentTheme.Dim).Render("▸") + " "
+	}
+	return "  "
+}
+
+// drillRows renders a drilled-in level: the breadcrumb, then two lines per
+// row — its name and tokens, then a gauge of its share of the level with its
+// cost — and the level's total. Each line is added with the item it belongs
+// to, so the pointer can hit it.
+func (m StatsModel) drillRows(items []usage.GroupUsage, rows statsRows) {
+	border := lipgloss.NewStyle().Foreground(currentTheme.Dim)
+	primary := lipgloss.NewStyle().Foreground(currentTheme.Primary)
+	primaryBold := lipgloss.NewStyle().Foreground(currentTheme.Primary).Bold(true)
+	header := lipgloss.NewStyle().Foreground(currentTheme.Dim).Bold(true)
+	num := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	faint := lipgloss.NewStyle().Faint(true)
+
+	rows.line("  "+muted.Render("‹ ")+primary.Render(m.breadcrumb()), statsHitBack)
+	rows.line("", -1)
+	column := map[statsLevel]string{statsLevelDays: "Day", statsLevelProjects: "Project", statsLevelSessions: "Conversation"}[m.level()]
+	rows.line(statsRightAlign("  "+header.Render(column), header.Render("Total")), -1)
+	rows.rule()
+
+	var sum usage.GroupUsage
+	for _, it := range items {
+		sum.Input += it.Input
+		sum.Output += it.Output
+		sum.CacheWrite += it.CacheWrite
+		sum.CacheRead += it.CacheRead
+		sum.Models = append(sum.Models, it.Models...)
+	}
+	if len(items) == 0 {
+		empty := map[statsLevel]string{
+			statsLevelDays:     "No daily usage recorded for this month.",
+			statsLevelProjects: "No project usage recorded for this day.",
+			statsLevelSessions: "No conversations recorded for this project that day.",
+		}[m.level()]
+		rows.line("  "+muted.Render(empty), -1)
+	}
+	levelTotal := sum.Total()
+	if levelTotal < 1 {
+		levelTotal = 1
+	}
+	end := m.offset + statsWindow
+	if end > len(items) {
+		end = len(items)
+	}
+	for i := m.offset; i < end; i++ {
+		it := items[i]
+		label := m.itemLabel(it.Key)
+		if lipgloss.Width(label) > 44 {
+			label = TruncateMiddle(label, 44) // keep the row inside the box
+		}
+		rows.line(statsRightAlign(m.statsItemMarker(i)+num.Render(label),
+			primaryBold.Render(humanizeTokens(it.Total()))), i)
+		frac := float64(it.Total()) / float64(levelTotal)
+		cost, allPriced := it.CostUSD()
+		costLabel := dollarFmt(cost)
+		if !allPriced {
+			costLabel = "~" + costLabel
+		}
+		gauge := "  " + statsGauge(frac, primary, border) + " " + faint.Render(fmt.Sprintf("%3d%%", int(frac*100+0.5)))
+		rows.line(statsRightAlign(gauge, primaryBold.Render(costLabel)), i)
+	}
+
+	rows.rule()
+	cost, allPriced := sum.CostUSD()
+	costLabel := dollarFmt(cost)
+	if !allPriced {
+		costLabel = "~" + costLabel
+	}
+	left := "  " + header.Render("Total") + "  " + primaryBold.Render(humanizeTokens(sum.Total())) + muted.Render(" tokens")
+	rows.line(statsRightAlign(left, primaryBold.Render(costLabel)), -1)
+}
+
+// chartRows renders the dashboard under the table: the daily chart of the
+// month in focus, then the model mix of the row under the cursor as a stacked
+// share bar with its legend.
+func (m StatsModel) chartRows(items []usage.GroupUsage, rows statsRows) {
+	primary := lipgloss.NewStyle().Foreground(currentTheme.Primary)
+	primaryBold := lipgloss.NewStyle().Foreground(currentTheme.Primary).Bold(true)
+	header := lipgloss.NewStyle().Foreground(currentTheme.Dim).Bold(true)
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	faint := lipgloss.NewStyle().Faint(true)
+
+	month := m.chartMonth(items)
+	days := usage.GroupRecords(m.recordsWhere(func(r usage.Record) bool { return r.Month() == month }), usage.ByDay)
+	chart, peakDay, peak := statsDailyChart(month, days, m.chartDay(items), primary, primaryBold.Foreground(currentTheme.Accent), faint)
+	var share string
+	var legend []string
+	if m.cursor < len(items) {
+		share, legend = statsShareBar(items[m.cursor].Models, statsColEnd-2, muted)
+	}
+	if chart == nil && share == "" {
+		return
+	}
+
+	rows.rule()
+	if chart != nil {
+		rows.line("", -1)
+		rows.line(statsRightAlign("  "+header.Render("Daily tokens · "+monthLabel(month)),
+			muted.Render("peak "+humanizeTokens(peak)+" on "+statsDayLabel(peakDay))), -1)
+		for _, l := range chart {
+			rows.line("  "+l, -1)
+		}
+	}
+	if share != "" {
+		rows.line("", -1)
+		rows.line(statsRightAlign("  "+header.Render("Model mix · "+m.itemLabel(items[m.cursor].Key)),
+			muted.Render(humanizeTokens(items[m.cursor].Total())+" tokens")), -1)
+		rows.line("  "+share, -1)
+		for _, l := range legend {
+			rows.line("  "+l, -1)
+		}
+	}
+}
+
+// statsRightAlign places a left chunk and a right chunk on one inner row with
+// the right chunk's edge landing on statsColEnd (under the Total column), so
+// every cost and total stacks into one clean money column.
+func statsRightAlign(left, right string) string {
+	pad := statsColEnd - lipgloss.Width(left) - lipgloss.Width(right)
+	if pad < 1 {
+		pad = 1
+	}
+	return left + strings.Repeat(" ", pad) + right
+}
+
+// moveCursor moves the cursor by delta within the current level, scrolling so
+// it stays inside the statsWindow rows shown.
+func (m *StatsModel) moveCursor(delta int) {
+	m.cursor += delta
+	m.clampCursor(len(m.statsItems()))
+}
+
+// clampCursor keeps the cursor on one of n rows and the window around it.
+func (m *StatsModel) clampCursor(n int) {
+	if m.cursor >= n {
+		m.cursor = n - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+statsWindow {
+		m.offset = m.cursor - statsWindow + 1
+	}
+	if last := n - statsWindow; m.offset > last {
+		m.offset = last
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
+// drillIn opens the row under the cursor: a month's days, a day's projects, a
+// project's conversations. Conversations are the deepest level.
+func (m *StatsModel) drillIn() {
+	items := m.statsItems()
+	if m.level() == statsLevelSessions || m.cursor >= len(items) {
+		return
+	}
+	m.crumbs = append(m.crumbs[:len(m.crumbs):len(m.crumbs)], statsCrumb{key: items[m.cursor].Key, cursor: m.cursor, offset: m.offset})
+	m.cursor, m.offset, m.hover = 0, 0, -1
+}
+
+// climbOut returns to the level above with its cursor where it was, reporting
+// false on the months table, where there is nothing to climb out of.
+func (m *StatsModel) climbOut() bool {
+	if len(m.crumbs) == 0 {
+		return false
+	}
+	c := m.crumbs[len(m.crumbs)-1]
+	m.crumbs = m.crumbs[:len(m.crumbs)-1]
+	m.cursor, m.offset, m.hover = c.cursor, c.offset, -1
+	m.clampCursor(len(m.statsItems()))
+	return true
+}
+
+// statsItemAt maps a pointer row on the rendered screen to the item drawn
+// there, statsHitBack for the breadcrumb, or -1. frame is the screen as View
+// draws it, so the centering offset is found from the box's top border.
+func (m StatsModel) statsItemAt(frame []string, y int) int {
+	_, hits := m.render()
+	for top, l := range frame {
+		if strings.Contains(l, "╭") {
+			if i := y - top; i >= 0 && i < len(hits) {
+				return hits[i]
+			}
+			break
+		}
+	}
+	return -1
+}
+
+// handleMouse gives the Stats screen the main menu's pointer parity: hover
+// marks the row under the pointer without moving the cursor, a left-click
+// selects a row and a click on the selected row opens it (the Enter action),
+// a click on the breadcrumb climbs back out, and the wheel moves the cursor.
+func (m StatsModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	switch msg.Button {
+	case tea.MouseButtonWheelDown:
+		m.moveCursor(1)
+		return m, nil
+	case tea.MouseButtonWheelUp:
+		m.moveCursor(-1)
+		return m, nil
+	}
+	frame := strings.Split(m.View(), "\n")
+	i := m.statsItemAt(frame, msg.Y)
+	// Require an actual glyph under the pointer so trailing padding inside the box
+	// doesn't register as the row.
+	if i != -1 && !frameCellHasGlyph(frame, msg.X, msg.Y) {
+		i = -1
+	}
+	switch msg.Action {
+	case tea.MouseActionMotion:
+		m.hover = -1
+		if i >= 0 {
+			m.hover = i
+		}
+	case tea.MouseActionPress:
+		if msg.Button != tea.MouseButtonLeft {
+			return m, nil
+		}
+		switch {
+		case i == statsHitBack:
+			m.climbOut()
+		case i >= 0 && i == m.cursor:
+			m.drillIn()
+		case i >= 0:
+			m.cursor = i
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,227 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jackuait/wisp-deck/internal/usage"
+)
+
+// drillModel is a loaded Stats screen over June (two days, two projects on the
+// 14th, one conversation in app) and May.
+func drillModel() StatsModel {
+	rec := func(day, project, model string, in int64) usage.Record {
+		return usage.Record{Day: day, Project: project, ModelUsage: usage.ModelUsage{Model: model, Input: in}}
+	}
+	records := []usage.Record{
+		rec("2026-06-14", "/src/app", "claude-opus-4-7", 3_000_000),
+		rec("2026-06-14", "/src/web", "claude-sonnet-4-5", 1_000_000),
+		rec("2026-06-02", "/src/app", "claude-sonnet-4-5", 500_000),
+		rec("2026-05-20", "/src/app", "claude-sonnet-4-5", 2_000_000),
+	}
+	at := time.Date(2026, 6, 14, 9, 0, 0, 0, time.UTC)
+	// 3f9c2a71 ran from the 13th into the 14th, where it used 2.0M of app's
+	// 3.0M; the rest came from outside any live conversation.
+	sessions := []usage.SessionUsage{
+		{ID: "3f9c2a71-aaaa", Project: "/src/app", First: at.AddDate(0, 0, -1), Last: at.Add(2 * time.Hour), Input: 6_000_000,
+			Models:  []usage.ModelUsage{{Model: "claude-opus-4-7", Input: 6_000_000}},
+			Records: []usage.Record{rec("2026-06-13", "/src/app", "claude-opus-4-7", 4_000_000), rec("2026-06-14", "/src/app", "claude-opus-4-7", 2_000_000)}},
+		{ID: "old", Project: "/src/app", First: at.AddDate(0, 0, -12), Last: at.AddDate(0, 0, -12), Input: 500_000,
+			Records: []usage.Record{rec("2026-06-02", "/src/app", "claude-sonnet-4-5", 500_000)}},
+	}
+	m := StatsModel{hover: -1}
+	updated, _ := m.Update(statsLoadedMsg{months: usage.Months(records), records: records, sessions: sessions})
+	return updated.(StatsModel)
+}
+
+func statsKey(t *testing.T, m StatsModel, k tea.KeyType) (StatsModel, tea.Cmd) {
+	t.Helper()
+	updated, cmd := m.Update(tea.KeyMsg{Type: k})
+	return updated.(StatsModel), cmd
+}
+
+func TestStatsDrill_enterDescendsAndEscClimbsBack(t *testing.T) {
+	m := drillModel()
+	m, _ = statsKey(t, m, tea.KeyEnter)
+	view := stripANSI(m.View())
+	if m.level() != statsLevelDays || !strings.Contains(view, "Sun Jun 14") || !strings.Contains(view, "Tue Jun 2") {
+		t.Fatalf("enter on June should list its days:\n%s", view)
+	}
+
+	m, _ = statsKey(t, m, tea.KeyEnter)
+	view = stripANSI(m.View())
+	if !strings.Contains(view, "Jun 2026 › Sun Jun 14") || !strings.Contains(view, "app") || !strings.Contains(view, "web") {
+		t.Fatalf("enter on the 14th should list its projects under a breadcrumb:\n%s", view)
+	}
+
+	m, _ = statsKey(t, m, tea.KeyEnter)
+	view = stripANSI(m.View())
+	if !strings.Contains(view, "3f9c2a71") || strings.Contains(view, "old") {
+		t.Fatalf("enter on app should list only the conversations active that day:\n%s", view)
+	}
+	// Each row is the conversation's usage that day, and the rows add up to
+	// app's 3.0M on the 14th.
+	items := m.statsItems()
+	if len(items) != 2 || items[0].Key != "3f9c2a71-aaaa" || items[0].Total() != 2_000_000 ||
+		items[1].Key != statsKeyUnattributed || items[1].Total() != 1_000_000 {
+		t.Errorf("conversations = %+v, want 3f9c2a71's 2.0M then 1.0M outside any conversation", items)
+	}
+	if !strings.Contains(view, "(no live conversation)") || !strings.Contains(view, "Total  3.0M tokens") {
+		t.Errorf("conversations should add up to the project's day:\n%s", view)
+	}
+	if m, _ = statsKey(t, m, tea.KeyEnter); m.level() != statsLevelSessions {
+		t.Error("enter on a conversation should stay at the deepest level")
+	}
+
+	m, _ = statsKey(t, m, tea.KeyEsc)
+	m, _ = statsKey(t, m, tea.KeyDown) // web
+	m, _ = statsKey(t, m, tea.KeyEsc)
+	m, cmd := statsKey(t, m, tea.KeyEsc)
+	if cmd != nil || m.level() != statsLevelMonths || m.cursor != 0 {
+		t.Fatalf("esc should climb back to the months table (level %d, cursor %d)", m.level(), m.cursor)
+	}
+	if _, cmd = statsKey(t, m, tea.KeyEsc); cmd == nil {
+		t.Fatal("esc on the months table should leave the screen")
+	}
+	if _, ok := cmd().(PopScreenMsg); !ok {
+		t.Errorf("esc cmd = %T, want PopScreenMsg", cmd())
+	}
+}
+
+func TestStatsDrill_climbingRestoresTheCursor(t *testing.T) {
+	m := drillModel()
+	m, _ = statsKey(t, m, tea.KeyDown) // May
+	m, _ = statsKey(t, m, tea.KeyEnter)
+	if !strings.Contains(stripANSI(m.View()), "Wed May 20") {
+		t.Fatalf("enter on May should list May's days:\n%s", stripANSI(m.View()))
+	}
+	m, _ = statsKey(t, m, tea.KeyEsc)
+	if m.cursor != 1 {
+		t.Errorf("cursor after climbing out = %d, want 1 (May)", m.cursor)
+	}
+}
+
+func TestStatsView_dailyChartAndModelMix(t *testing.T) {
+	view := stripANSI(drillModel().View())
+	for _, want := range []string{"Daily tokens · Jun 2026", "peak 4.0M on Sun Jun 14", "Model mix · Jun 2026", "opus-4-7 67%", "sonnet-4-5 33%"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("dashboard missing %q:\n%s", want, view)
+		}
+	}
+	lines := strings.Split(view, "\n")
+	for i, l := range lines {
+		if strings.Contains(l, "Model mix") {
+			if n := strings.Count(lines[i+1], "█"); n != statsGaugeW {
+				t.Errorf("share bar = %d blocks, want the full %d", n, statsGaugeW)
+			}
+		}
+		if strings.Contains(l, "│") && lipgloss.Width(l) != statsInner+2 {
+			t.Errorf("box line width = %d, want %d: %q", lipgloss.Width(l), statsInner+2, l)
+		}
+	}
+}
+
+func TestStatsDailyChart_scalesToThePeakDay(t *testing.T) {
+	days := []usage.GroupUsage{
+		{Key: "2026-06-14", Input: 800},
+		{Key: "2026-06-02", Input: 100},
+	}
+	plain := lipgloss.NewStyle()
+	lines, peakDay, peak := statsDailyChart("2026-06", days, "", plain, plain, plain)
+	if peakDay != "2026-06-14" || peak != 800 || len(lines) != statsChartH+1 {
+		t.Fatalf("chart = %d lines, peak %s %d", len(lines), peakDay, peak)
+	}
+	col := func(day int) string {
+		var b strings.Builder
+		for _, l := range lines[:statsChartH] {
+			b.WriteRune([]rune(l)[(day-1)*2])
+		}
+		return b.String()
+	}
+	if got := col(14); got != "████" {
+		t.Errorf("peak day column = %q, want a full bar", got)
+	}
+	if got := col(2); got != "   ▄" {
+		t.Errorf("an eighth of the peak = %q, want half a cell", got)
+	}
+	if got := col(3); got != "   ·" {
+		t.Errorf("an idle day = %q, want the dotted baseline", got)
+	}
+	if w := lipgloss.Width(lines[0]); w != 2*30-1 {
+		t.Errorf("June chart width = %d, want one column per day with gaps", w)
+	}
+	if !strings.HasPrefix(lines[statsChartH], "1       5") {
+		t.Errorf("axis = %q, want day numbers under their columns", lines[statsChartH])
+	}
+	if lines, _, _ := statsDailyChart("2026-06", nil, "", plain, plain, plain); lines != nil {
+		t.Error("a month without daily records should draw no chart")
+	}
+}
+
+// drillScreen renders m at a known size and returns the screen lines and the
+// row of the first line containing text.
+func drillScreen(t *testing.T, m StatsModel, text string) (StatsModel, []string, int) {
+	t.Helper()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 80})
+	m = updated.(StatsModel)
+	lines := strings.Split(m.View(), "\n")
+	for y, l := range lines {
+		if strings.Contains(stripANSI(l), text) {
+			return m, lines, y
+		}
+	}
+	t.Fatalf("screen has no %q:\n%s", text, stripANSI(m.View()))
+	return m, nil, 0
+}
+
+func TestStatsMouse_clickSelectsThenOpens(t *testing.T) {
+	m, lines, y := drillScreen(t, drillModel(), "May 2026")
+	x := strings.Index(stripANSI(lines[y]), "May")
+	x = len([]rune(stripANSI(lines[y])[:x]))
+
+	click := tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}
+	updated, _ := m.Update(click)
+	m = updated.(StatsModel)
+	if m.cursor != 1 || m.level() != statsLevelMonths {
+		t.Fatalf("first click should select May (cursor %d, level %d)", m.cursor, m.level())
+	}
+	if !strings.Contains(stripANSI(m.View()), "Model mix · May 2026") {
+		t.Error("selecting May should move the dashboard to May")
+	}
+	updated, _ = m.Update(click)
+	m = updated.(StatsModel)
+	if m.level() != statsLevelDays || m.crumbs[0].key != "2026-05" {
+		t.Fatalf("a click on the selected month should open its days (level %d)", m.level())
+	}
+
+	_, lines, y = drillScreen(t, m, "‹ May 2026")
+	x = len([]rune(stripANSI(lines[y])[:strings.Index(stripANSI(lines[y]), "‹")]))
+	updated, _ = m.Update(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if m = updated.(StatsModel); m.level() != statsLevelMonths {
+		t.Error("a click on the breadcrumb should climb back out")
+	}
+}
+
+func TestStatsMouse_hoverAndWheel(t *testing.T) {
+	withTrueColor(t)
+	m, lines, y := drillScreen(t, drillModel(), "May 2026")
+	x := len([]rune(stripANSI(lines[y])[:strings.Index(stripANSI(lines[y]), "May")]))
+
+	updated, _ := m.Update(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionMotion, Button: tea.MouseButtonNone})
+	m = updated.(StatsModel)
+	if m.hover != 1 || m.cursor != 0 {
+		t.Errorf("hover = %d cursor = %d, want hover on May without moving the cursor", m.hover, m.cursor)
+	}
+	updated, _ = m.Update(tea.MouseMsg{X: 0, Y: y, Action: tea.MouseActionMotion, Button: tea.MouseButtonNone})
+	if m = updated.(StatsModel); m.hover != -1 {
+		t.Errorf("hover off the box = %d, want -1", m.hover)
+	}
+
+	updated, _ = m.Update(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown})
+	if m = updated.(StatsModel); m.cursor != 1 {
+		t.Errorf("wheel down cursor = %d, want 1", m.cursor)
+	}
+}
@@ -1,8 +1,11 @@
 package tui
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -388,3 +391,45 @@ func TestStatsUpdate_errMsgSetsError(t *testing.T) {
 		t.Errorf("after err: err=%v loading=%v, want set/false", sm.err, sm.loading)
 	}
 }
+
+func TestStatsUpdate_failedRescanKeepsLastGoodData(t *testing.T) {
+	at := time.Now().Add(-time.Minute)
+	m := StatsModel{months: threeMonths(), updated: at, watcher: usage.NewWatcher(nil, filepath.Join(t.TempDir(), "cache.json"))}
+	updated, cmd := m.Update(statsErrMsg{err: errTestStats})
+	sm := updated.(StatsModel)
+	if sm.err != nil || len(sm.months) != 3 || !sm.updated.Equal(at) {
+		t.Errorf("after a failed rescan: err=%v months=%d updated=%v, want the last good data kept", sm.err, len(sm.months), sm.updated)
+	}
+	if cmd == nil {
+		t.Error("a failed rescan should schedule the next one")
+	}
+}
+
+func TestStatsUpdate_liveRescanShowsTodaySoFar(t *testing.T) {
+	root := t.TempDir()
+	proj := filepath.Join(root, "projects", "-src-app")
+	os.MkdirAll(proj, 0o755)
+	day := time.Now().UTC().Format("2006-01-02")
+	os.WriteFile(filepath.Join(proj, "s.jsonl"), []byte(`{"type":"assistant","timestamp":"`+day+
+		`T00:00:00Z","message":{"id":"a","model":"claude-sonnet-4-5","usage":{"input_tokens":1500000}}}`+"\n"), 0o644)
+	m := StatsModel{loading: true, watcher: usage.NewWatcher(usage.Sources([]string{filepath.Join(root, "projects")}, ""), filepath.Join(root, "cache.json"))}
+
+	updated, cmd := m.Update(statsTickMsg{})
+	if cmd == nil {
+		t.Fatal("tick returned no rescan")
+	}
+	msg, ok := cmd().(statsLoadedMsg)
+	if !ok || msg.today.Total() != 1_500_000 || msg.at.IsZero() {
+		t.Fatalf("rescan = %+v, want today's 1.5M tokens", msg)
+	}
+	updated, cmd = updated.Update(msg)
+	if cmd == nil {
+		t.Error("a live model should schedule the next rescan")
+	}
+	view := updated.View()
+	for _, want := range []string{"Today so far (UTC)", "1.5M", "$5", "live, updated"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+}
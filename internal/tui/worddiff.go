@@ -0,0 +1,205 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Emphasis bands for the changed words of a paired -/+ line: a brighter take
+// on the row's own green/red tint, so a one-character edit in a long line
+// stands out. Raw SGR like the row bands (see diffAddBgSeq); each span hands
+// back to its row band when it ends rather than resetting, so tintColumn's
+// band keeps running to the end of the row.
+const (
+	diffAddEmphBgSeq = "\x1b[48;2;38;92;56m"
+	diffDelEmphBgSeq = "\x1b[48;2;112;36;46m"
+)
+
+const (
+	// diffWordMaxTokens caps the words per line that get diffed; a longer line
+	// (minified code, data) keeps only its row tint.
+	diffWordMaxTokens = 400
+	// diffWordMinShared is the share of a pair's text that must be unchanged for
+	// the pair to read as an edit. Below it the lines are a rewrite, and
+	// emphasizing nearly every word would only add noise.
+	diffWordMinShared = 0.4
+)
+
+// diffSpan is a [start, end) range of visible runes within a line's text.
+type diffSpan struct{ start, end int }
+
+// emphasizeChanges marks the words that changed between each removed line and
+// the added line it pairs with. Within a change block (a run of '-' lines then
+// a run of '+' lines) the i-th removal pairs with the i-th addition; each pair
+// is diffed word by word against the raw body and the changed words of both
+// lines get an emphasis band. highlighted is highlightDiff's output for raw,
+// line for line; raw's lines have tabs already expanded so rune positions
+// agree. Only background bands that return to the row's band are added, so
+// the foreground-only contract tintColumn relies on still holds.
+func emphasizeChanges(highlighted, raw string) string {
+	hl := strings.Split(highlighted, "\n")
+	lines := strings.Split(raw, "\n")
+	if len(hl) != len(lines) {
+		return highlighted
+	}
+	var dels, adds []int
+	flush := func() {
+		for i := 0; i < len(dels) && i < len(adds); i++ {
+			d, a := dels[i], adds[i]
+			delSpans, addSpans := wordDiffSpans(lines[d][1:], lines[a][1:])
+			hl[d] = emphasizeSpans(hl[d], delSpans, diffDelEmphBgSeq, diffDelBgSeq)
+			hl[a] = emphasizeSpans(hl[a], addSpans, diffAddEmphBgSeq, diffAddBgSeq)
+		}
+		dels, adds = nil, nil
+	}
+	for i, ln := range lines {
+		switch {
+		case strings.HasPrefix(ln, "-"):
+			if len(adds) > 0 { // a removal after additions starts a new block
+				flush()
+			}
+			dels = append(dels, i)
+		case strings.HasPrefix(ln, "+"):
+			adds = append(adds, i)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return strings.Join(hl, "\n")
+}
+
+// diffWords splits text into words (runs of letters, digits and '_'), runs of
+// whitespace, and single punctuation runes, so an edit is pinned to the token
+// it touched.
+func diffWords(text string) []string {
+	class := func(r rune) int {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			return 1
+		case unicode.IsSpace(r):
+			return 2
+		}
+		return 0
+	}
+	var words []string
+	rs := []rune(text)
+	for i := 0; i < len(rs); {
+		j := i + 1
+		if c := class(rs[i]); c != 0 {
+			for j < len(rs) && class(rs[j]) == c {
+				j++
+			}
+		}
+		words = append(words, string(rs[i:j]))
+		i = j
+	}
+	return words
+}
+
+// wordDiffSpans diffs before against after word by word (longest common
+// subsequence) and returns the rune spans of each side that changed. Both are
+// nil when the lines share too little to read as an edit, when they are
+// identical, or when either is too long to diff.
+func wordDiffSpans(before, after string) ([]diffSpan, []diffSpan) {
+	a, b := diffWords(before), diffWords(after)
+	if len(a) > diffWordMaxTokens || len(b) > diffWordMaxTokens {
+		return nil, nil
+	}
+	// lcs[i][j] is the common-subsequence length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = maxInt(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var oldSpans, newSpans []diffSpan
+	mark := func(spans []diffSpan, pos int, w string) []diffSpan {
+		end := pos + len([]rune(w))
+		if n := len(spans); n > 0 && spans[n-1].end == pos {
+			spans[n-1].end = end // extend the span the previous word opened
+			return spans
+		}
+		return append(spans, diffSpan{pos, end})
+	}
+	shared, oldPos, newPos := 0, 0, 0
+	for i, j := 0, 0; i < len(a) || j < len(b); {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			if strings.TrimSpace(a[i]) != "" {
+				shared += len([]rune(a[i]))
+			}
+			oldPos += len([]rune(a[i]))
+			newPos += len([]rune(b[j]))
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			oldSpans = mark(oldSpans, oldPos, a[i])
+			oldPos += len([]rune(a[i]))
+			i++
+		default:
+			newSpans = mark(newSpans, newPos, b[j])
+			newPos += len([]rune(b[j]))
+			j++
+		}
+	}
+	visible := func(s string) int { return len([]rune(strings.Join(strings.Fields(s), ""))) }
+	longest := maxInt(visible(before), visible(after))
+	if len(oldSpans)+len(newSpans) == 0 || float64(shared) < diffWordMinShared*float64(longest) {
+		return nil, nil
+	}
+	return oldSpans, newSpans
+}
+
+// emphasizeSpans opens emphBg at the start of each span of a highlighted diff
+// line and restores rowBg at its end. Span positions count the line's visible
+// runes after its marker; escape sequences are copied through untouched.
+func emphasizeSpans(line string, spans []diffSpan, emphBg, rowBg string) string {
+	if len(spans) == 0 {
+		return line
+	}
+	rs := []rune(line)
+	var b strings.Builder
+	vis := -1 // the marker is visible rune -1; the text starts at 0
+	next := 0
+	for i := 0; i < len(rs); {
+		if rs[i] == '\x1b' {
+			j := i
+			for j < len(rs) && rs[j] != 'm' {
+				j++
+			}
+			if j < len(rs) {
+				j++
+			}
+			b.WriteString(string(rs[i:j]))
+			i = j
+			continue
+		}
+		if next < len(spans) {
+			switch vis {
+			case spans[next].start:
+				b.WriteString(emphBg)
+			case spans[next].end:
+				b.WriteString(rowBg)
+				if next++; next < len(spans) && spans[next].start == vis {
+					b.WriteString(emphBg)
+				}
+			}
+		}
+		b.WriteRune(rs[i])
+		vis++
+		i++
+	}
+	if next < len(spans) && vis >= spans[next].start {
+		b.WriteString(rowBg) // the last span ran to the end of the line
+	}
+	return b.String()
+}
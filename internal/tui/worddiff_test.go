@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+// emphasized returns the text inside each emphasis band of a line (escapes
+// stripped), in order.
+func emphasized(line, emphBg string) []string {
+	var out []string
+	for _, part := range strings.Split(line, emphBg)[1:] {
+		out = append(out, stripA(strings.SplitN(part, "\x1b[48;", 2)[0]))
+	}
+	return out
+}
+
+// A one-word edit in a paired -/+ line emphasizes just that word on each side.
+func TestWordDiffSpans_marks_only_the_changed_word(t *testing.T) {
+	del, add := wordDiffSpans("return total * rate", "return total * price")
+	if len(del) != 1 || del[0] != (diffSpan{15, 19}) {
+		t.Errorf("removed spans = %v, want [{15 19}] (rate)", del)
+	}
+	if len(add) != 1 || add[0] != (diffSpan{15, 20}) {
+		t.Errorf("added spans = %v, want [{15 20}] (price)", add)
+	}
+}
+
+// Lines that share too little are a rewrite, not an edit: no emphasis.
+func TestWordDiffSpans_rewrite_is_not_emphasized(t *testing.T) {
+	if del, add := wordDiffSpans("foo(bar)", "x := compute(y, z)"); del != nil || add != nil {
+		t.Errorf("a rewritten line should not be word-diffed, got %v / %v", del, add)
+	}
+}
+
+// Within a change block the i-th removal pairs with the i-th addition; an
+// unpaired extra addition keeps only its row tint.
+func TestEmphasizeChanges_pairs_lines_within_a_block(t *testing.T) {
+	raw := " ctx\n-a := 1\n-b := 2\n+a := 10\n+b := 2 + c\n+extra line\n ctx\n"
+	out := strings.Split(emphasizeChanges(raw, raw), "\n")
+	if got := emphasized(out[1], diffDelEmphBgSeq); len(got) != 1 || got[0] != "1" {
+		t.Errorf("first removal emphasis = %q, want [1]", got)
+	}
+	if got := emphasized(out[3], diffAddEmphBgSeq); len(got) != 1 || got[0] != "10" {
+		t.Errorf("first addition emphasis = %q, want [10]", got)
+	}
+	if got := emphasized(out[4], diffAddEmphBgSeq); len(got) != 1 || got[0] != " + c" {
+		t.Errorf("second addition emphasis = %q, want [\" + c\"]", got)
+	}
+	if strings.Contains(out[2], diffDelEmphBgSeq) || strings.Contains(out[5], diffAddEmphBgSeq) {
+		t.Errorf("unchanged or unpaired lines must not be emphasized: %q / %q", out[2], out[5])
+	}
+	if stripA(strings.Join(out, "\n")) != raw {
+		t.Error("emphasis must not change the visible text")
+	}
+}
+
+// Emphasis lands on the right code in a syntax-highlighted line, and each band
+// hands back to the row tint so the band behind the rest of the row survives.
+func TestNewDiffView_emphasis_keeps_row_band_in_both_modes(t *testing.T) {
+	raw := " package main\n-var limit = 10 // requests\n+var limit = 20 // requests\n"
+	m := NewDiffView("main.go", raw)
+	for _, mode := range []int{diffModeInline, diffModeSideBySide} {
+		body := renderBodyMode(m.bodyContent(), 120, mode)
+		for _, c := range []struct{ emph, row, word string }{
+			{diffDelEmphBgSeq, diffDelBgSeq, "10"},
+			{diffAddEmphBgSeq, diffAddBgSeq, "20"},
+		} {
+			i := strings.Index(body, c.emph)
+			if i < 0 {
+				t.Fatalf("mode %d: no emphasis band %q in:\n%q", mode, c.emph, body)
+			}
+			rest := body[i+len(c.emph):]
+			end := strings.Index(rest, c.row)
+			if end < 0 || strings.Contains(rest[:end], "\x1b[0m") {
+				t.Fatalf("mode %d: emphasis must return to the row band before any reset: %q", mode, rest)
+			}
+			if got := stripA(rest[:end]); got != c.word {
+				t.Errorf("mode %d: emphasized %q, want %q", mode, got, c.word)
+			}
+			if !strings.Contains(stripA(rest[end:]), "// requests") {
+				t.Errorf("mode %d: the rest of the row should follow inside the band: %q", mode, rest[end:])
+			}
+		}
+	}
+}
+
+// A wrap inside an emphasized span re-opens the band on the continuation row.
+func TestWrapColumns_reopens_emphasis_band(t *testing.T) {
+	s := diffAddEmphBgSeq + "abcdef" + diffAddBgSeq + "gh"
+	rows := wrapColumns(s, 4)
+	if len(rows) != 2 || !strings.HasPrefix(rows[1], diffAddEmphBgSeq) {
+		t.Errorf("continuation row should re-open the emphasis band, got %q", rows)
+	}
+}
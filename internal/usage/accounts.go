@@ -0,0 +1,93 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackuait/wisp-deck/internal/claudeaccount"
+)
+
+// Account keys tag each Record with the login that made the calls. A native
+// account is keyed by its dir under claude-accounts; these are the rest. None
+// can collide with a dir, which claudeaccount slugifies to [a-z0-9-].
+const (
+	DefaultAccount  = "default"   // the standard ~/.claude login
+	OpenCodeAccount = ":opencode" // OpenCode, whatever provider it billed
+	CodexAccount    = ":codex"    // the Codex CLI
+	GeminiAccount   = ":gemini"   // the Gemini CLI
+	AiderAccount    = ":aider"    // Aider, whatever provider it billed
+)
+
+// wispDeckConfigDir is ${XDG_CONFIG_HOME:-~/.config}/wisp-deck, where the
+// account list and the per-account config dirs live.
+func wispDeckConfigDir(home string) string {
+	configHome := strings.TrimSpace(os.Getenv("XDG_CONFIG_HOME"))
+	if configHome == "" {
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "wisp-deck")
+}
+
+// AccountKey returns the account a transcript root from
+// ClaudeAccountProjectDirs belongs to: DefaultAccount for ~/.claude/projects,
+// otherwise the account's dir under claude-accounts.
+func AccountKey(projectsDir string) string {
+	parent := filepath.Base(filepath.Dir(projectsDir))
+	if parent == ".claude" {
+		return DefaultAccount
+	}
+	return parent
+}
+
+// AccountLabels maps account keys to the labels the menu's ACCOUNT switcher
+// shows: the Default login's custom label, each native account's label from
+// claude-accounts.list, and the other assistants' names. Use AccountLabel to
+// look keys up.
+func AccountLabels(home string) map[string]string {
+	dir := wispDeckConfigDir(home)
+	labels := map[string]string{
+		DefaultAccount:  claudeaccount.GetDefaultLabel(filepath.Join(dir, "claude-account-default-label")),
+		OpenCodeAccount: "OpenCode",
+		CodexAccount:    "Codex",
+		GeminiAccount:   "Gemini",
+		AiderAccount:    "Aider",
+	}
+	for _, a := range claudeaccount.Load(filepath.Join(dir, "claude-accounts.list")) {
+		labels[a.Dir] = a.Label
+	}
+	return labels
+}
+
+// AccountLabel resolves key through labels. An account since removed from the
+// list keeps its dir name; usage archived before accounts were tracked has no
+// key and reads "Unattributed".
+func AccountLabel(labels map[string]string, key string) string {
+	if l, ok := labels[key]; ok {
+		return l
+	}
+	if key == "" {
+		return "Unattributed"
+	}
+	return key
+}
+
+// withAccount tags every record with account, copying only when a record is
+// not tagged yet (entries served from the cache usually are). A source with no
+// account (the proxy ledger) tags its records itself and is left alone.
+func withAccount(records []Record, account string) []Record {
+	if account == "" {
+		return records
+	}
+	for i := range records {
+		if records[i].Account != account {
+			out := make([]Record, len(records))
+			for j, r := range records {
+				r.Account = account
+				out[j] = r
+			}
+			return out
+		}
+	}
+	return records
+}
@@ -0,0 +1,65 @@
+package usage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScan_tagsRecordsWithTheirAccount(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", "")
+	def := filepath.Join(home, ".claude", "projects", "-src-app")
+	work := filepath.Join(home, ".config", "wisp-deck", "claude-accounts", "work", "projects", "-src-app")
+	oc := filepath.Join(home, "oc", "ses_1")
+	mkdirAll(t, def)
+	mkdirAll(t, work)
+	mkdirAll(t, oc)
+	line := func(id string, in int) string {
+		return fmt.Sprintf(`{"type":"assistant","timestamp":"2026-05-01T09:00:00Z","cwd":"/src/app","message":{"id":%q,"model":"m","usage":{"input_tokens":%d}}}`+"\n", id, in)
+	}
+	writeFixture(t, def, "a.jsonl", line("a", 10))
+	writeFixture(t, work, "b.jsonl", line("b", 200))
+	writeFixture(t, oc, "msg_1.json", ocMsg("assistant", "m", time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC).UnixMilli(), 3000, 0, 0, 0, 0))
+
+	cachePath := filepath.Join(home, "cache.json")
+	for pass := 0; pass < 2; pass++ { // fresh parse, then served from the cache
+		records, err := Collect(Sources(ClaudeAccountProjectDirs(home), filepath.Join(home, "oc")), cachePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]int64{}
+		for _, g := range GroupRecords(records, ByAccount) {
+			got[g.Key] = g.Total()
+		}
+		want := map[string]int64{DefaultAccount: 10, "work": 200, OpenCodeAccount: 3000}
+		if len(got) != len(want) || got[DefaultAccount] != 10 || got["work"] != 200 || got[OpenCodeAccount] != 3000 {
+			t.Errorf("pass %d: by account = %v, want %v", pass, got, want)
+		}
+	}
+}
+
+func TestAccountLabels_resolveThroughTheAccountList(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+	dir := filepath.Join(home, "xdg", "wisp-deck")
+	mkdirAll(t, dir)
+	os.WriteFile(filepath.Join(dir, "claude-accounts.list"), []byte("Work Max:work\nSide Project:side\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "claude-account-default-label"), []byte("Personal\n"), 0o644)
+
+	labels := AccountLabels(home)
+	for key, want := range map[string]string{
+		DefaultAccount:  "Personal",
+		"work":          "Work Max",
+		"side":          "Side Project",
+		OpenCodeAccount: "OpenCode",
+		"removed":       "removed",
+		"":              "Unattributed",
+	} {
+		if got := AccountLabel(labels, key); got != want {
+			t.Errorf("AccountLabel(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -1,188 +1,327 @@
 package usage
 
 import (
+	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 )
 
-// addRow accumulates one model's tokens into dst (model id -> running total).
-func addRow(dst map[string]*ModelUsage, m ModelUsage) {
-	a := dst[m.Model]
-	if a == nil {
-		a = &ModelUsage{Model: m.Model}
-		dst[m.Model] = a
-	}
-	a.Input += m.Input
-	a.Output += m.Output
-	a.CacheWrite += m.CacheWrite
-	a.CacheRead += m.CacheRead
+// parseFunc reads one source file into its cache entry; parseTranscript
+// (Claude) and every other source's parser (see sourceKinds) satisfy it so the
+// cache treats them alike.
+type parseFunc func(path string) (fileCacheEntry, error)
+
+// Snapshot is the result of one scan: every record, live and archived, merged
+// by day, project, account and model, the per-conversation index of the Claude
+// transcripts still on disk (see SessionUsage), most expensive first, and every
+// Claude tool call, live and archived, merged by day, tool and agent.
+type Snapshot struct {
+	Records  []Record
+	Sessions []SessionUsage
+	Tools    []ToolRecord
+}
+
+// Aggregate is AggregateAll for a single Claude transcript root and OpenCode.
+// Most callers should use AggregateAll with DefaultSources instead, which
+// counts every native account and every other assistant too.
+func Aggregate(claudeDir, opencodeDir, cachePath string) ([]MonthlyUsage, error) {
+	return AggregateAll(Sources([]string{claudeDir}, opencodeDir), cachePath)
 }
 
-// addModelRows accumulates per-model rows into dst (model id -> running total).
-func addModelRows(dst map[string]*ModelUsage, rows []ModelUsage) {
-	for _, m := range rows {
-		addRow(dst, m)
+// AggregateAll is Collect folded into per-month token usage sorted newest-first.
+func AggregateAll(sources []Source, cachePath string) ([]MonthlyUsage, error) {
+	records, err := Collect(sources, cachePath)
+	if err != nil {
+		return nil, err
 	}
+	return Months(records), nil
 }
 
-// foldMonths accumulates a file's per-month, per-model usage into acc
-// (month -> model -> running total).
-func foldMonths(acc map[string]map[string]*ModelUsage, months map[string]*MonthlyUsage) {
-	for month, mu := range months {
-		bucket := acc[month]
-		if bucket == nil {
-			bucket = map[string]*ModelUsage{}
-			acc[month] = bucket
-		}
-		addModelRows(bucket, mu.Models)
+// Collect is Scan's records alone.
+func Collect(sources []Source, cachePath string) ([]Record, error) {
+	snap, err := Scan(sources, cachePath)
+	if err != nil {
+		return nil, err
 	}
+	return snap.Records, nil
 }
 
-// foldArchive accumulates an archive (month -> model -> total) into acc.
-func foldArchive(acc map[string]map[string]*ModelUsage, archive map[string]map[string]*ModelUsage) {
-	for month, byModel := range archive {
-		bucket := acc[month]
-		if bucket == nil {
-			bucket = map[string]*ModelUsage{}
-			acc[month] = bucket
-		}
-		for _, m := range byModel {
-			addRow(bucket, *m)
-		}
+// Progress reports how far a scan has got through the files it has to parse.
+// Files served from the cache cost nothing and are not counted, so a warm scan
+// reports 0 of 0.
+type Progress struct {
+	FilesDone  int
+	FilesTotal int
+	BytesDone  int64
+	BytesTotal int64
+}
+
+// Fraction returns the share of the work done, by bytes (by files when every
+// pending file is empty), or 1 when there is nothing to parse.
+func (p Progress) Fraction() float64 {
+	switch {
+	case p.BytesTotal > 0:
+		return float64(p.BytesDone) / float64(p.BytesTotal)
+	case p.FilesTotal > 0:
+		return float64(p.FilesDone) / float64(p.FilesTotal)
 	}
+	return 1
 }
 
-// cloneArchive deep-copies an archive so callers can fold into the copy without
-// mutating the loaded cache's pointers.
-func cloneArchive(src map[string]map[string]*ModelUsage) map[string]map[string]*ModelUsage {
-	dst := map[string]map[string]*ModelUsage{}
-	for month, byModel := range src {
-		bucket := map[string]*ModelUsage{}
-		for id, mu := range byModel {
-			cp := *mu
-			bucket[id] = &cp
+// parseWorkers bounds how many files a scan parses at once.
+var parseWorkers = runtime.GOMAXPROCS(0)
+
+// pendingFile is a file whose cache entry is missing or stale.
+type pendingFile struct {
+	path    string
+	size    int64
+	parse   parseFunc
+	account string
+}
+
+// parseAll parses files on a pool of at most parseWorkers goroutines. Each
+// entry lands at its file's index (nil when the file is unreadable), so what
+// follows sees the same order however the work was scheduled. progress, when
+// set, is called on the calling goroutine before the first file and after
+// each one.
+func parseAll(files []pendingFile, progress func(Progress)) []*fileCacheEntry {
+	out := make([]*fileCacheEntry, len(files))
+	p := Progress{FilesTotal: len(files)}
+	for _, f := range files {
+		p.BytesTotal += f.size
+	}
+	if progress != nil {
+		progress(p)
+	}
+	if len(files) == 0 {
+		return out
+	}
+
+	jobs := make(chan int)
+	done := make(chan int)
+	for w := 0; w < max(1, min(parseWorkers, len(files))); w++ {
+		go func() {
+			for i := range jobs {
+				if entry, err := files[i].parse(files[i].path); err == nil {
+					out[i] = &entry
+				}
+				done <- i
+			}
+		}()
+	}
+	go func() {
+		for i := range files {
+			jobs <- i
+		}
+		close(jobs)
+	}()
+	for range files {
+		i := <-done
+		p.FilesDone++
+		p.BytesDone += files[i].size
+		if progress != nil {
+			progress(p)
 		}
-		dst[month] = bucket
 	}
-	return dst
+	return out
 }
 
-// parseFunc reads one source file into per-month usage; ParseFile (Claude) and
-// ParseOpenCodeMessage (OpenCode) both satisfy it so the cache treats them alike.
-type parseFunc func(path string) (map[string]*MonthlyUsage, FileMeta, error)
+// Scan is ScanProgress without progress reporting.
+func Scan(sources []Source, cachePath string) (*Snapshot, error) {
+	return ScanProgress(sources, cachePath, nil)
+}
 
-// Aggregate is AggregateAll for a single Claude transcript root. Most callers
-// that track usage across multiple native accounts should use AggregateAll with
-// ClaudeAccountProjectDirs instead.
-func Aggregate(claudeDir, opencodeDir, cachePath string) ([]MonthlyUsage, error) {
-	return AggregateAll([]string{claudeDir}, opencodeDir, cachePath)
+// ScanProgress walks each source's root for the files its kind recognises
+// (see sourceKinds), merging all of them into per-day, per-project, per-model
+// records (GroupRecords folds them into coarser views) and indexing the Claude
+// transcripts by conversation. Each record is tagged with its source's Account.
+// Multiple Claude roots let usage be counted across every native account (the
+// Default ~/.claude plus each extra account's config dir); file paths are
+// absolute and disjoint across roots, so they share one cache without
+// colliding. Same-named models from any source fold into a single row. It
+// reuses cachePath entries for files whose size and mtime are unchanged and
+// re-parses changed files in parallel, reporting to progress (which may be
+// nil) as they finish. When a previously-cached file vanishes from ALL walked
+// roots, its records are sealed into a durable Archive so the history survives
+// the tool's transcript pruning; sealed paths are never re-counted. A
+// missing/empty root (e.g. an assistant that is not installed) is simply
+// skipped. Best-effort saves the updated cache.
+func ScanProgress(sources []Source, cachePath string, progress func(Progress)) (*Snapshot, error) {
+	snap, next, _, err := scanCache(LoadCache(cachePath), sources, progress, parseTranscript)
+	if err != nil {
+		return nil, err
+	}
+	_ = next.Save(cachePath) // best-effort; a save failure must not break the view
+	return snap, nil
 }
 
-// AggregateAll walks each dir in claudeDirs for *.jsonl transcripts and
-// opencodeDir for *.json OpenCode message files, merging all of them into
-// per-month token usage sorted newest-first. Multiple Claude roots let usage be
-// counted across every native account (the Default ~/.claude plus each extra
-// account's config dir); transcript paths are absolute and disjoint across roots,
-// so they share one cache without colliding. Same-named models from any source
-// fold into a single row. It reuses cachePath entries for files whose size and
-// mtime are unchanged and re-parses changed files. When a previously-cached file
-// vanishes from ALL walked roots, its totals are sealed into a durable per-month
-// Archive so the history survives the tool's transcript pruning; sealed paths are
-// never re-counted. A missing/empty opencodeDir (e.g. OpenCode not installed) is
-// simply skipped. Best-effort saves the updated cache.
-func AggregateAll(claudeDirs []string, opencodeDir, cachePath string) ([]MonthlyUsage, error) {
-	cache := LoadCache(cachePath)
+// scanCache is ScanProgress against an already loaded cache, parsing Claude
+// transcripts with parseClaude. It returns the next cache for the caller to
+// keep or save, and whether it sealed any vanished transcript.
+func scanCache(cache *Cache, sources []Source, progress func(Progress), parseClaude parseFunc) (*Snapshot, *Cache, bool, error) {
 	next := &Cache{
-		Version: cacheVersion,
-		Files:   map[string]fileCacheEntry{},
-		Archive: cloneArchive(cache.Archive),
-		Sealed:  map[string]bool{},
+		Version:     cacheVersion,
+		Files:       map[string]fileCacheEntry{},
+		Archive:     cache.Archive,
+		ToolArchive: cache.ToolArchive,
+		Sealed:      map[string]bool{},
 	}
 	for p := range cache.Sealed {
 		next.Sealed[p] = true
 	}
 
 	seen := map[string]bool{}
-	consider := func(path string, info fs.FileInfo, parse parseFunc) {
+	var pending []pendingFile
+	consider := func(path string, info fs.FileInfo, parse parseFunc, account string) {
 		seen[path] = true
 		if next.Sealed[path] {
-			// Already folded into Archive; never re-count. A brand-new file that
-			// reuses a sealed path would be ignored, but transcript/message paths
-			// embed a session id so reuse does not happen in practice.
+			// Already folded into Archive; never re-count. A path is sealed only
+			// once its file is gone from disk, so this skips a file recreated at
+			// the same path: rare for transcripts, whose names embed a session
+			// id, but possible for Aider's fixed-name history and analytics logs.
 			return
 		}
 		if prev, ok := cache.Files[path]; ok &&
 			prev.Meta.Size == info.Size() && prev.Meta.ModTime.Equal(info.ModTime()) {
+			prev.Records = withAccount(prev.Records, account)
 			next.Files[path] = prev
 			return
 		}
-		months, meta, parseErr := parse(path)
-		if parseErr != nil {
-			return // skip unreadable file
-		}
-		next.Files[path] = fileCacheEntry{Meta: meta, Months: months}
+		pending = append(pending, pendingFile{path: path, size: info.Size(), parse: parse, account: account})
 	}
 
-	walk := func(root, suffix string, parse parseFunc) error {
-		return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+	walk := func(src Source) error {
+		kind, ok := sourceKinds[src.Kind]
+		if !ok {
+			return fmt.Errorf("usage source %q: unknown kind %q", src.Root, src.Kind)
+		}
+		parse := kind.parse
+		if src.Kind == SourceClaude {
+			parse = parseClaude
+		}
+		return filepath.WalkDir(src.Root, func(path string, d fs.DirEntry, err error) error {
 			if err != nil {
 				return nil // skip unreadable dirs/files, keep going
 			}
-			if d.IsDir() || !strings.HasSuffix(path, suffix) {
+			if d.IsDir() || !kind.match(path) {
 				return nil
 			}
 			info, statErr := d.Info()
 			if statErr != nil {
 				return nil
 			}
-			consider(path, info, parse)
+			consider(path, info, parse, src.Account)
 			return nil
 		})
 	}
 
-	for _, claudeDir := range claudeDirs {
-		if err := walk(claudeDir, ".jsonl", ParseFile); err != nil {
-			return nil, err
+	for _, src := range sources {
+		if err := walk(src); err != nil {
+			return nil, nil, false, err
 		}
 	}
-	if err := walk(opencodeDir, ".json", ParseOpenCodeMessage); err != nil {
-		return nil, err
+
+	for i, entry := range parseAll(pending, progress) {
+		if entry == nil {
+			continue // skip unreadable file
+		}
+		entry.Records = withAccount(entry.Records, pending[i].account)
+		next.Files[pending[i].path] = *entry
 	}
 
 	// Seal transcripts that were cached but have vanished from disk: fold their
-	// months into the durable archive so the history outlives the source file.
+	// records into the durable archive so the history outlives the source file.
+	// A cached file the walk merely missed is still on disk (its source left
+	// the set: an Aider project gone stale, AIDER_ANALYTICS_LOG pointed
+	// elsewhere), so it is carried over as is and picked up again should its
+	// source return.
+	var sealed [][]Record
+	var sealedTools [][]ToolRecord
+	var sealedIDs []string
 	for path, entry := range cache.Files {
 		if seen[path] || next.Sealed[path] {
 			continue
 		}
-		foldMonths(next.Archive, entry.Months)
+		if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+			next.Files[path] = entry
+			continue
+		}
+		sealed = append(sealed, entry.Records)
+		sealedTools = append(sealedTools, entry.Tools)
+		sealedIDs = append(sealedIDs, entry.MessageIDs...)
 		next.Sealed[path] = true
 	}
+	if len(sealed) > 0 {
+		next.Archive = mergeRecords(append(sealed, next.Archive)...)
+		next.ToolArchive = mergeToolRecords(append(sealedTools, next.ToolArchive)...)
+	}
+	dedupeLedgers(next, cache.SealedIDs, sealedIDs)
 
 	// Dedup is per-file only (ParseFile dedups by message.id within a file). We do
 	// NOT dedup across files: a global dedup would require parsing every file
 	// together, which defeats the incremental cache. Cross-file duplicate ids are
 	// rare (~0.02% in practice) and intentionally tolerated. Do not "fix" this.
-	// Accumulate live files plus the sealed archive into month -> model totals.
-	acc := map[string]map[string]*ModelUsage{}
-	for _, entry := range next.Files {
-		foldMonths(acc, entry.Months)
+	// The proxy ledger is the exception, as it logs every proxied call a
+	// transcript also holds; dedupeLedgers matches it by the ids each entry lists.
+	// Accumulate live files, in path order so the merge never depends on which
+	// worker finished first, plus the sealed archive into one set of rows.
+	paths := make([]string, 0, len(next.Files))
+	for path := range next.Files {
+		paths = append(paths, path)
 	}
-	foldArchive(acc, next.Archive)
+	sort.Strings(paths)
+	all := make([][]Record, 0, len(next.Files)+1)
+	tools := make([][]ToolRecord, 0, len(next.Files)+1)
+	for _, path := range paths {
+		all = append(all, next.Files[path].Records)
+		tools = append(tools, next.Files[path].Tools)
+	}
+	all = append(all, next.Archive)
+	tools = append(tools, next.ToolArchive)
 
-	_ = next.Save(cachePath) // best-effort; a save failure must not break the view
+	snap := &Snapshot{Records: mergeRecords(all...), Sessions: buildSessions(next.Files), Tools: mergeToolRecords(tools...)}
+	return snap, next, len(sealed) > 0, nil
+}
 
-	out := make([]MonthlyUsage, 0, len(acc))
-	for month, byModel := range acc {
-		if mu := buildMonthly(month, byModel); mu != nil {
-			out = append(out, *mu)
+// dedupeLedgers sets the records of each proxy ledger in next to the calls no
+// transcript counted (see ledgerRecords). A sealed transcript's ids are kept in
+// next.SealedIDs for as long as a live ledger still logs the call, so the call
+// is not counted again once its transcript is gone.
+func dedupeLedgers(next *Cache, prevSealed map[string]bool, justSealed []string) {
+	logged := map[string]bool{}
+	for _, e := range next.Files {
+		for _, c := range e.Calls {
+			if c.ID != "" {
+				logged[c.ID] = true
+			}
+		}
+	}
+	if len(logged) == 0 {
+		return
+	}
+	next.SealedIDs = map[string]bool{}
+	for id := range prevSealed {
+		if logged[id] {
+			next.SealedIDs[id] = true
+		}
+	}
+	for _, id := range justSealed {
+		if logged[id] {
+			next.SealedIDs[id] = true
+		}
+	}
+	counted := countedIDs(next.Files, next.SealedIDs)
+	for path, e := range next.Files {
+		if len(e.Calls) > 0 {
+			e.Records = ledgerRecords(e.Calls, counted)
+			next.Files[path] = e
 		}
 	}
-	sort.Slice(out, func(i, j int) bool { return out[i].Month > out[j].Month })
-	return out, nil
 }
 
 // DefaultPaths returns the production Claude transcript dir, the OpenCode message
@@ -212,11 +351,7 @@ func DefaultPaths(home string) (claudeDir, opencodeDir, cachePath string) {
 func ClaudeAccountProjectDirs(home string) []string {
 	dirs := []string{filepath.Join(home, ".claude", "projects")}
 
-	configHome := strings.TrimSpace(os.Getenv("XDG_CONFIG_HOME"))
-	if configHome == "" {
-		configHome = filepath.Join(home, ".config")
-	}
-	accountsDir := filepath.Join(configHome, "wisp-deck", "claude-accounts")
+	accountsDir := filepath.Join(wispDeckConfigDir(home), "claude-accounts")
 
 	entries, err := os.ReadDir(accountsDir)
 	if err != nil {
@@ -60,11 +60,8 @@ func TestAggregate_reusesCacheForUnchangedFiles(t *testing.T) {
 	// Output is rebuilt from per-model rows, so tamper a model row (not the flat
 	// field) — it must survive if the unchanged file is served from cache.
 	c.Files[p] = fileCacheEntry{
-		Meta: FileMeta{ModTime: info.ModTime(), Size: info.Size()},
-		Months: map[string]*MonthlyUsage{"2026-05": {
-			Month: "2026-05", Input: 999,
-			Models: []ModelUsage{{Model: "unknown", Input: 999}},
-		}},
+		Meta:    FileMeta{ModTime: info.ModTime(), Size: info.Size()},
+		Records: []Record{{Day: "2026-05-01", ModelUsage: ModelUsage{Model: "unknown", Input: 999}}},
 	}
 	if err := c.Save(cachePath); err != nil {
 		t.Fatal(err)
@@ -127,8 +124,8 @@ func TestAggregate_sealsDeletedFileIntoArchive(t *testing.T) {
 	if !c.Sealed[p] {
 		t.Errorf("deleted file should be recorded in Sealed")
 	}
-	if c.Archive["2026-05"]["claude-opus-4-7"].Input != 10 {
-		t.Errorf("archive = %+v, want 2026-05/opus input 10", c.Archive)
+	if len(c.Archive) != 1 || c.Archive[0].Day != "2026-05-01" || c.Archive[0].Model != "claude-opus-4-7" || c.Archive[0].Input != 10 {
+		t.Errorf("archive = %+v, want 2026-05-01/opus input 10", c.Archive)
 	}
 }
 
@@ -155,6 +152,47 @@ func TestAggregate_sealedFileNotDoubleCountedOnReappear(t *testing.T) {
 	}
 }
 
+// A source can leave the set while its file stays on disk (an Aider project
+// gone stale): the file is not sealed, so it is counted again, growth and all,
+// once the source returns.
+func TestScan_sourceRemovedThenReaddedIsNotSealed(t *testing.T) {
+	dir := t.TempDir()
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+	p := writeFixture(t, dir, aiderHistoryFile, aiderHistoryFixture)
+	aider := []Source{{Kind: SourceAider, Root: p, Account: AiderAccount}}
+	input := func(sources []Source) int64 {
+		t.Helper()
+		snap, err := Scan(sources, cachePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n int64
+		for _, r := range snap.Records {
+			n += r.Input
+		}
+		return n
+	}
+
+	if got := input(aider); got != 2700 {
+		t.Fatalf("first scan input = %d, want 2700", got)
+	}
+	if got := input(nil); got != 2700 {
+		t.Errorf("scan without the source = %d, want the cached 2700 kept", got)
+	}
+	if c := LoadCache(cachePath); c.Sealed[p] {
+		t.Fatal("a file still on disk was sealed")
+	}
+	f, err := os.OpenFile(p, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("> Tokens: 300 sent, 5 received. Cost: $0.01 message, $0.02 session.\n")
+	f.Close()
+	if got := input(aider); got != 3000 {
+		t.Errorf("input after the source returns = %d, want 3000 with the new session", got)
+	}
+}
+
 func TestAggregate_mergesArchiveWithLiveMonth(t *testing.T) {
 	dir := t.TempDir()
 	cachePath := filepath.Join(t.TempDir(), "cache.json")
@@ -177,32 +215,37 @@ func TestAggregate_mergesArchiveWithLiveMonth(t *testing.T) {
 	}
 }
 
-func TestAddModelRows_accumulatesByModel(t *testing.T) {
-	dst := map[string]*ModelUsage{}
-	addModelRows(dst, []ModelUsage{
-		{Model: "claude-opus-4-7", Input: 10, Output: 1},
-		{Model: "claude-opus-4-7", Input: 5, CacheRead: 2},
-		{Model: "claude-fable-5", Input: 3},
-	})
-	if dst["claude-opus-4-7"].Input != 15 || dst["claude-opus-4-7"].Output != 1 ||
-		dst["claude-opus-4-7"].CacheRead != 2 {
-		t.Errorf("opus row = %+v, want input 15 output 1 cacheRead 2", dst["claude-opus-4-7"])
-	}
-	if dst["claude-fable-5"].Input != 3 {
-		t.Errorf("fable row = %+v, want input 3", dst["claude-fable-5"])
+func TestMergeRecords_accumulatesByDayProjectAndModel(t *testing.T) {
+	rec := func(day, project, model string, in, cw1h int64) Record {
+		return Record{Day: day, Project: project, ModelUsage: ModelUsage{Model: model, Input: in, CacheWrite: cw1h, CacheWrite1h: cw1h}}
+	}
+	out := mergeRecords(
+		[]Record{rec("2026-05-01", "/a", "claude-opus-4-7", 10, 2), rec("2026-05-01", "/b", "claude-opus-4-7", 1, 0)},
+		[]Record{rec("2026-05-01", "/a", "claude-opus-4-7", 5, 3), rec("2026-05-02", "/a", "claude-opus-4-7", 7, 0)},
+	)
+	if len(out) != 3 {
+		t.Fatalf("out = %+v, want 3 rows (day, project and model all distinguish rows)", out)
+	}
+	if a := out[0]; a.Project != "/a" || a.Input != 15 || a.CacheWrite1h != 5 {
+		t.Errorf("first row = %+v, want /a on 2026-05-01 with input 15 and the 1h subset kept (5)", a)
+	}
+	if out[2].Day != "2026-05-02" {
+		t.Errorf("rows should be ordered by day, got %+v", out)
 	}
 }
 
-func TestFoldMonths_accumulatesByMonthAndModel(t *testing.T) {
-	acc := map[string]map[string]*ModelUsage{}
-	foldMonths(acc, map[string]*MonthlyUsage{
-		"2026-05": {Month: "2026-05", Models: []ModelUsage{{Model: "m", Input: 4}}},
-	})
-	foldMonths(acc, map[string]*MonthlyUsage{
-		"2026-05": {Month: "2026-05", Models: []ModelUsage{{Model: "m", Input: 6}}},
-	})
-	if acc["2026-05"]["m"].Input != 10 {
-		t.Errorf("acc = %+v, want 2026-05/m input 10", acc)
+func TestCollect_keepsDayAndProject(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "a.jsonl",
+		`{"type":"assistant","timestamp":"2026-05-01T10:00:00Z","cwd":"/src/app","message":{"id":"a","model":"m","usage":{"input_tokens":10}}}
+{"type":"assistant","timestamp":"2026-05-03T10:00:00Z","cwd":"/src/lib","message":{"id":"b","model":"m","usage":{"input_tokens":4}}}`+"\n")
+	records, err := Collect(Sources([]string{dir}, ""), filepath.Join(t.TempDir(), "cache.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Day != "2026-05-01" || records[0].Project != "/src/app" ||
+		records[1].Day != "2026-05-03" || records[1].Project != "/src/lib" {
+		t.Errorf("records = %+v, want one row per day and project", records)
 	}
 }
 
@@ -274,7 +317,7 @@ func TestAggregateAll_mergesMultipleClaudeDirs(t *testing.T) {
 	writeFixture(t, dirB, "b.jsonl",
 		`{"type":"assistant","timestamp":"2026-05-02T10:00:00Z","message":{"id":"b","model":"claude-opus-4-8","usage":{"input_tokens":5,"output_tokens":0,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}}}`+"\n")
 
-	out, err := AggregateAll([]string{dirA, dirB}, "", filepath.Join(t.TempDir(), "cache.json"))
+	out, err := AggregateAll(Sources([]string{dirA, dirB}, ""), filepath.Join(t.TempDir(), "cache.json"))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -297,12 +340,12 @@ func TestAggregateAll_sealingIgnoresOtherRootsFiles(t *testing.T) {
 	writeFixture(t, dirB, "b.jsonl",
 		`{"type":"assistant","timestamp":"2026-05-02T10:00:00Z","message":{"id":"b","model":"claude-opus-4-8","usage":{"input_tokens":5,"output_tokens":0,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}}}`+"\n")
 
-	if _, err := AggregateAll([]string{dirA, dirB}, "", cachePath); err != nil {
+	if _, err := AggregateAll(Sources([]string{dirA, dirB}, ""), cachePath); err != nil {
 		t.Fatal(err)
 	}
 	// Second pass over both roots; nothing deleted, so totals must be unchanged
 	// and no path may be sealed.
-	out, err := AggregateAll([]string{dirA, dirB}, "", cachePath)
+	out, err := AggregateAll(Sources([]string{dirA, dirB}, ""), cachePath)
 	if err != nil {
 		t.Fatal(err)
 	}
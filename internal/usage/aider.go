@@ -0,0 +1,168 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// aiderHistoryFile is the chat history Aider keeps in the root of each repo it
+// runs in.
+const aiderHistoryFile = ".aider.chat.history.md"
+
+// aiderModel strips the litellm provider route ("anthropic/",
+// "openrouter/anthropic/") from an Aider model name so it prices like the
+// same model seen from any other source. An empty name is "unknown".
+func aiderModel(name string) string {
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	if name == "" {
+		return "unknown"
+	}
+	return name
+}
+
+// aiderTokenCount parses one of Aider's abbreviated token counts: "850",
+// "1.2k", "12k" or "1.5M".
+func aiderTokenCount(s string) (int64, bool) {
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(s, "k"):
+		mult, s = 1e3, strings.TrimSuffix(s, "k")
+	case strings.HasSuffix(s, "M"):
+		mult, s = 1e6, strings.TrimSuffix(s, "M")
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0, false
+	}
+	return int64(v*mult + 0.5), true
+}
+
+// ParseAiderHistoryRecords reads an .aider.chat.history.md file into records,
+// matching ParseFileRecords' contract. Each session opens with a
+// "# aider chat started at <local time>" heading and names its model in a
+// "> Model:" (or "> Main model:") line; every reply is followed by a line like
+//
+//	> Tokens: 12k sent, 1.2k cache write, 3.4k cache hit, 850 received. Cost: ...
+//
+// which is billed to the session's day and model. The counts are rounded as
+// Aider prints them, and "sent" includes the cache counts, so they are taken
+// out of Input. Lines before the first heading are skipped. The project is the
+// directory holding the file.
+func ParseAiderHistoryRecords(path string) ([]Record, FileMeta, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, FileMeta{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, FileMeta{}, err
+	}
+	meta := FileMeta{ModTime: info.ModTime(), Size: info.Size()}
+
+	acc := recordSet{}
+	project := filepath.Dir(path)
+	day, model := "", "unknown"
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	for sc.Scan() {
+		line := sc.Text()
+		if rest, ok := strings.CutPrefix(line, "# aider chat started at "); ok {
+			if len(rest) >= 10 {
+				day, model = rest[:10], "unknown"
+			}
+			continue
+		}
+		rest, ok := strings.CutPrefix(line, "> ")
+		if !ok || day == "" {
+			continue
+		}
+		if name, ok := strings.CutPrefix(rest, "Model: "); ok {
+			model = aiderModel(strings.SplitN(name, " with ", 2)[0])
+		} else if name, ok := strings.CutPrefix(rest, "Main model: "); ok {
+			model = aiderModel(strings.SplitN(name, " with ", 2)[0])
+		} else if counts, ok := strings.CutPrefix(rest, "Tokens: "); ok {
+			counts, _, _ = strings.Cut(counts, ". Cost")
+			var c usageCounts
+			var sent int64
+			for _, part := range strings.Split(strings.TrimSuffix(counts, "."), ", ") {
+				num, label, _ := strings.Cut(part, " ")
+				n, ok := aiderTokenCount(num)
+				if !ok {
+					continue
+				}
+				switch label {
+				case "sent":
+					sent = n
+				case "received":
+					c.Output = n
+				case "cache write":
+					c.CacheWrite = n
+				case "cache hit":
+					c.CacheRead = n
+				}
+			}
+			c.Input = max(0, sent-c.CacheWrite-c.CacheRead)
+			acc.addCounts(day, project, model, c)
+		}
+	}
+	return acc.records(), meta, sc.Err()
+}
+
+// aiderEvent is the subset of an Aider analytics log line we read. Run with
+// --analytics-log (or AIDER_ANALYTICS_LOG), Aider appends one JSON event per
+// line; each message_send carries the reply's exact token counts.
+type aiderEvent struct {
+	Event      string `json:"event"`
+	Time       int64  `json:"time"`
+	Properties struct {
+		MainModel        string `json:"main_model"`
+		PromptTokens     int64  `json:"prompt_tokens"`
+		CompletionTokens int64  `json:"completion_tokens"`
+	} `json:"properties"`
+}
+
+// ParseAiderLogRecords reads an Aider analytics log into records, matching
+// ParseFileRecords' contract. Only message_send events with a time are billed,
+// on their UTC day; malformed lines are skipped. The log names no project, so
+// it is "", and it does not split out cache tokens, so every prompt token is
+// Input.
+func ParseAiderLogRecords(path string) ([]Record, FileMeta, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, FileMeta{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, FileMeta{}, err
+	}
+	meta := FileMeta{ModTime: info.ModTime(), Size: info.Size()}
+
+	acc := recordSet{}
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	for sc.Scan() {
+		var ev aiderEvent
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			continue
+		}
+		if ev.Event != "message_send" || ev.Time <= 0 {
+			continue
+		}
+		acc.addCounts(timeFromEpoch(ev.Time).Format("2006-01-02"), "", aiderModel(ev.Properties.MainModel), usageCounts{
+			Input:  ev.Properties.PromptTokens,
+			Output: ev.Properties.CompletionTokens,
+		})
+	}
+	return acc.records(), meta, sc.Err()
+}
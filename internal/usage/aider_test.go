@@ -0,0 +1,74 @@
+package usage
+
+import "testing"
+
+const aiderHistoryFixture = `
+# aider chat started at 2026-05-01 09:00:00
+
+> /usr/local/bin/aider --model sonnet
+> Aider v0.86.1
+> Main model: anthropic/claude-sonnet-4-5 with diff edit format, infinite output
+> Git repo: .git with 120 files
+
+#### fix the bug
+
+Done.
+
+> Tokens: 12k sent, 2.0k cache write, 8.5k cache hit, 850 received. Cost: $0.03 message, $0.03 session.
+
+# aider chat started at 2026-05-02 10:00:00
+
+> Model: gpt-4o with diff edit format
+> Tokens: 1.2k sent, 40 received. Cost: $0.01 message, $0.01 session.
+> Tokens: garbage sent.
+`
+
+func TestParseAiderHistoryRecords_billsEachTokensLine(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFixture(t, dir, aiderHistoryFile, aiderHistoryFixture)
+	records, meta, err := ParseAiderHistoryRecords(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Size == 0 {
+		t.Errorf("meta.Size = 0, want non-zero")
+	}
+	want := []Record{
+		{Day: "2026-05-01", Project: dir, ModelUsage: ModelUsage{Model: "claude-sonnet-4-5", Input: 1500, CacheWrite: 2000, CacheRead: 8500, Output: 850}},
+		{Day: "2026-05-02", Project: dir, ModelUsage: ModelUsage{Model: "gpt-4o", Input: 1200, Output: 40}},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("records = %+v, want %+v", records, want)
+	}
+	for i := range want {
+		if records[i] != want[i] {
+			t.Errorf("records[%d] = %+v, want %+v", i, records[i], want[i])
+		}
+	}
+}
+
+func TestParseAiderLogRecords_messageSendEvents(t *testing.T) {
+	dir := t.TempDir()
+	body := `{"event": "launched", "properties": {}, "user_id": "u", "time": 1777626000}` + "\n" +
+		`{"event": "message_send", "properties": {"main_model": "openrouter/anthropic/claude-sonnet-4-5", "prompt_tokens": 900, "completion_tokens": 70, "total_tokens": 970, "cost": 0.004}, "user_id": "u", "time": 1777626000}` + "\n" +
+		"not json\n"
+	records, _, err := ParseAiderLogRecords(writeFixture(t, dir, "analytics.jsonl", body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Record{Day: "2026-05-01", ModelUsage: ModelUsage{Model: "claude-sonnet-4-5", Input: 900, Output: 70}}
+	if len(records) != 1 || records[0] != want {
+		t.Errorf("records = %+v, want [%+v]", records, want)
+	}
+}
+
+func TestAiderTokenCount(t *testing.T) {
+	for in, want := range map[string]int64{"850": 850, "1.2k": 1200, "12k": 12000, "1.5M": 1500000} {
+		if got, ok := aiderTokenCount(in); !ok || got != want {
+			t.Errorf("aiderTokenCount(%q) = %d, %v, want %d", in, got, ok, want)
+		}
+	}
+	if _, ok := aiderTokenCount("garbage"); ok {
+		t.Errorf("aiderTokenCount(garbage) ok, want not")
+	}
+}
@@ -0,0 +1,200 @@
+package usage
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BlockDuration is the length of a Claude plan's rolling usage window: the
+// first call after a window lapses opens a new one, and the limits reset when
+// it ends.
+const BlockDuration = 5 * time.Hour
+
+// blockLookback bounds how far back AccountBlocks reconstructs blocks. It must
+// cover more than one window so the largest recent block can stand in for the
+// plan's (unpublished) limit.
+const blockLookback = 7 * 24 * time.Hour
+
+// Block is one reconstructed 5-hour billing window. Start is the first call's
+// time floored to the hour, as the plan limits count it; End is Start plus
+// BlockDuration. First and Last are the first and latest billed calls in it.
+type Block struct {
+	Start      time.Time
+	End        time.Time
+	First      time.Time
+	Last       time.Time
+	Input      int64
+	Output     int64
+	CacheWrite int64
+	CacheRead  int64
+	Calls      int
+	Models     []ModelUsage
+}
+
+// Total returns the sum of all token columns.
+func (b Block) Total() int64 {
+	return b.Input + b.Output + b.CacheWrite + b.CacheRead
+}
+
+// CostUSD returns the block's API-equivalent cost; the bool is false when a
+// model in it has no pricing entry, making the figure a lower bound.
+func (b Block) CostUSD() (float64, bool) {
+	return costOf(b.Models)
+}
+
+// Active reports whether the window is still open at now.
+func (b Block) Active(now time.Time) bool {
+	return !now.Before(b.Start) && now.Before(b.End)
+}
+
+// BlockStatus is one native account's rolling window as of At. Account is its
+// key (see AccountKey) and Dir its transcript root. Active is nil
+// when the account has made no call in the current window. Limit is the
+// largest completed block in the lookback, the best available stand-in for the
+// plan's token cap; 0 when there is no history yet. The rates are measured from
+// the active block's first call to At; the projection assumes that pace holds
+// until the window ends, and ExhaustsAt is when it would reach Limit (the
+// latest call once it already has; zero when the window resets first or there
+// is no Limit).
+type BlockStatus struct {
+	Account        string
+	Dir            string
+	At             time.Time
+	Active         *Block
+	Limit          int64
+	TokensPerMin   float64
+	CostPerHour    float64
+	ProjectedTotal int64
+	ProjectedCost  float64
+	ExhaustsAt     time.Time
+}
+
+// blockEvent is one billed call.
+type blockEvent struct {
+	at time.Time
+	m  ModelUsage
+}
+
+// buildBlocks groups events into consecutive 5-hour windows, oldest first. A
+// call at or after the current window's end opens a new one, so an idle gap of
+// a full window always starts fresh.
+func buildBlocks(events []blockEvent) []Block {
+	sort.SliceStable(events, func(i, j int) bool { return events[i].at.Before(events[j].at) })
+	var blocks []Block
+	var models map[string]*ModelUsage
+	flush := func() {
+		if len(blocks) > 0 {
+			blocks[len(blocks)-1].Models = sortedModels(models)
+		}
+	}
+	for _, e := range events {
+		if len(blocks) == 0 || !e.at.Before(blocks[len(blocks)-1].End) {
+			flush()
+			start := e.at.UTC().Truncate(time.Hour)
+			blocks = append(blocks, Block{Start: start, End: start.Add(BlockDuration), First: e.at})
+			models = map[string]*ModelUsage{}
+		}
+		b := &blocks[len(blocks)-1]
+		b.Last = e.at
+		b.Calls++
+		b.Input += e.m.Input
+		b.Output += e.m.Output
+		b.CacheWrite += e.m.CacheWrite
+		b.CacheRead += e.m.CacheRead
+		a := models[e.m.Model]
+		if a == nil {
+			a = &ModelUsage{Model: e.m.Model}
+			models[e.m.Model] = a
+		}
+		addUsage(a, e.m)
+	}
+	flush()
+	return blocks
+}
+
+// readBlockEvents collects every billed call made at or after since from the
+// transcripts under dir. Files last written before since cannot hold such a
+// call and are not opened.
+func readBlockEvents(dir string, since time.Time) []blockEvent {
+	var events []blockEvent
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".jsonl") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.ModTime().Before(since) {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return nil
+		}
+		defer f.Close()
+		scanTranscript(f, func(transcriptRecord) {}, func(rec *transcriptRecord, m ModelUsage) {
+			at, err := time.Parse(time.RFC3339Nano, rec.Timestamp)
+			if err != nil || at.Before(since) {
+				return
+			}
+			events = append(events, blockEvent{at: at, m: m})
+		})
+		return nil
+	})
+	return events
+}
+
+// blockStatusAt reconstructs the billing blocks in events and reports the one
+// open at now.
+func blockStatusAt(events []blockEvent, now time.Time) BlockStatus {
+	st := BlockStatus{At: now}
+	for _, b := range buildBlocks(events) {
+		if b.Active(now) {
+			st.Active = &b
+			continue
+		}
+		if b.Total() > st.Limit {
+			st.Limit = b.Total()
+		}
+	}
+	b := st.Active
+	if b == nil {
+		return st
+	}
+	cost, _ := b.CostUSD()
+	elapsed := now.Sub(b.First)
+	if elapsed < time.Minute {
+		elapsed = time.Minute // a lone first call is not an infinite rate
+	}
+	st.TokensPerMin = float64(b.Total()) / elapsed.Minutes()
+	st.CostPerHour = cost / elapsed.Hours()
+	remaining := b.End.Sub(now)
+	st.ProjectedTotal = b.Total() + int64(st.TokensPerMin*remaining.Minutes())
+	st.ProjectedCost = cost + st.CostPerHour*remaining.Hours()
+	if st.Limit > 0 {
+		if b.Total() >= st.Limit {
+			st.ExhaustsAt = b.Last
+		} else if st.ProjectedTotal >= st.Limit {
+			st.ExhaustsAt = now.Add(time.Duration(float64(st.Limit-b.Total()) / st.TokensPerMin * float64(time.Minute)))
+		}
+	}
+	return st
+}
+
+// AccountBlocks reports the rolling 5-hour window of each native account's
+// transcript root (see ClaudeAccountProjectDirs) as of now, in claudeDirs
+// order. Blocks are rebuilt from transcript timestamps on every call rather
+// than from the cache, whose records keep only the day.
+func AccountBlocks(claudeDirs []string, now time.Time) []BlockStatus {
+	since := now.Add(-blockLookback)
+	out := make([]BlockStatus, 0, len(claudeDirs))
+	for _, dir := range claudeDirs {
+		st := blockStatusAt(readBlockEvents(dir, since), now)
+		st.Account = AccountKey(dir)
+		st.Dir = dir
+		out = append(out, st)
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func at(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+	return t
+}
+
+func TestBuildBlocks_startsANewWindowAtTheEndOfTheLast(t *testing.T) {
+	ev := func(s string, in int64) blockEvent {
+		return blockEvent{at: at(s), m: ModelUsage{Model: "m", Input: in}}
+	}
+	blocks := buildBlocks([]blockEvent{
+		ev("2026-05-10T11:00:00Z", 2),
+		ev("2026-05-10T09:10:00Z", 1),
+		ev("2026-05-10T14:05:00Z", 4), // past 09:00's window end: a new block from 14:00
+		ev("2026-05-10T20:00:00Z", 8),
+	})
+	want := []struct {
+		start string
+		total int64
+		calls int
+	}{{"2026-05-10T09:00:00Z", 3, 2}, {"2026-05-10T14:00:00Z", 4, 1}, {"2026-05-10T20:00:00Z", 8, 1}}
+	if len(blocks) != len(want) {
+		t.Fatalf("blocks = %+v, want %d", blocks, len(want))
+	}
+	for i, w := range want {
+		b := blocks[i]
+		if !b.Start.Equal(at(w.start)) || !b.End.Equal(at(w.start).Add(BlockDuration)) || b.Total() != w.total || b.Calls != w.calls {
+			t.Errorf("block %d = %v–%v total %d calls %d, want %s total %d calls %d", i, b.Start, b.End, b.Total(), b.Calls, w.start, w.total, w.calls)
+		}
+	}
+	if !blocks[0].First.Equal(at("2026-05-10T09:10:00Z")) || !blocks[0].Last.Equal(at("2026-05-10T11:00:00Z")) {
+		t.Errorf("first block span = %v – %v", blocks[0].First, blocks[0].Last)
+	}
+	if len(blocks[0].Models) != 1 || blocks[0].Models[0].Input != 3 {
+		t.Errorf("first block models = %+v", blocks[0].Models)
+	}
+}
+
+func TestAccountBlocks_reportsTheActiveWindowPerAccount(t *testing.T) {
+	home := t.TempDir()
+	now := at("2026-05-10T12:00:00Z")
+	proj := filepath.Join(home, ".claude", "projects", "-src-app")
+	mkdirAll(t, proj)
+	// Yesterday's 1,000-token block sets the limit; today's window opened at
+	// 11:00 and has burned 300 tokens in the hour since.
+	writeFixture(t, proj, "a.jsonl", `{"type":"assistant","timestamp":"2026-05-09T10:00:00Z","message":{"id":"a","model":"m","usage":{"input_tokens":1000}}}
+{"type":"assistant","timestamp":"2026-05-10T11:00:00Z","message":{"id":"b","model":"m","usage":{"input_tokens":100}}}
+{"type":"assistant","timestamp":"2026-05-10T11:30:00Z","message":{"id":"c","model":"m","usage":{"output_tokens":200}}}
+`)
+	os.Chtimes(filepath.Join(proj, "a.jsonl"), now, now)
+	// A transcript last written before the lookback is never read.
+	writeFixture(t, proj, "old.jsonl",
+		`{"type":"assistant","timestamp":"2026-05-10T11:45:00Z","message":{"id":"d","model":"m","usage":{"input_tokens":9999}}}`+"\n")
+	old := now.Add(-30 * 24 * time.Hour)
+	os.Chtimes(filepath.Join(proj, "old.jsonl"), old, old)
+	work := filepath.Join(home, ".config", "wisp-deck", "claude-accounts", "work", "projects")
+	mkdirAll(t, work)
+
+	got := AccountBlocks([]string{filepath.Join(home, ".claude", "projects"), work}, now)
+	if len(got) != 2 || got[0].Account != DefaultAccount || got[1].Account != "work" {
+		t.Fatalf("statuses = %+v, want Default then work", got)
+	}
+	if got[1].Active != nil {
+		t.Errorf("work active = %+v, want none", got[1].Active)
+	}
+	st := got[0]
+	if st.Active == nil || st.Active.Total() != 300 || !st.Active.End.Equal(at("2026-05-10T16:00:00Z")) {
+		t.Fatalf("active = %+v, want 300 tokens until 16:00", st.Active)
+	}
+	if st.Limit != 1000 || st.TokensPerMin != 5 || st.ProjectedTotal != 1500 {
+		t.Errorf("limit %d rate %v projected %d, want 1000, 5/min, 1500", st.Limit, st.TokensPerMin, st.ProjectedTotal)
+	}
+	if !st.ExhaustsAt.Equal(at("2026-05-10T14:20:00Z")) {
+		t.Errorf("exhausts at %v, want 14:20 (700 tokens at 5/min)", st.ExhaustsAt)
+	}
+}
@@ -0,0 +1,286 @@
+package usage
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BudgetThresholds are the shares of a budget, in percent, whose crossing
+// raises an alert.
+var BudgetThresholds = []int{50, 80, 100}
+
+// Budget is a monthly spend limit in USD, priced at API list rates (see
+// ModelCostUSD): an overall cap and optional caps per project and per account.
+// The zero Budget sets none.
+type Budget struct {
+	MonthlyUSD float64
+	Projects   map[string]float64 // by project path or its base name
+	Accounts   map[string]float64 // by account key (see AccountKey)
+}
+
+// IsZero reports whether no limit is set.
+func (b Budget) IsZero() bool {
+	return b.MonthlyUSD == 0 && len(b.Projects) == 0 && len(b.Accounts) == 0
+}
+
+// ParseBudget reads a budget spec, the value of the settings file's "budget"
+// key: comma-separated entries, each a bare amount for the overall cap or
+// "project:<path or name>=<amount>" / "account:<key>=<amount>" for a scoped
+// one, e.g. "200,project:wisp-deck=50,account:work=100". Amounts may carry a
+// leading "$". An empty spec clears the budget.
+func ParseBudget(spec string) (Budget, error) {
+	var b Budget
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		scope, amount := "", entry
+		if i := strings.LastIndexByte(entry, '='); i >= 0 {
+			scope, amount = strings.TrimSpace(entry[:i]), entry[i+1:]
+		}
+		usd, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(amount), "$"), 64)
+		if err != nil || usd <= 0 {
+			return Budget{}, fmt.Errorf("budget %q: want a positive amount such as \"200\" or \"project:app=50\"", entry)
+		}
+		if scope == "" {
+			b.MonthlyUSD = usd
+			continue
+		}
+		kind, key, _ := strings.Cut(scope, ":")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return Budget{}, fmt.Errorf("budget %q: want project:<path or name> or account:<key>", entry)
+		}
+		switch kind {
+		case "project":
+			if b.Projects == nil {
+				b.Projects = map[string]float64{}
+			}
+			b.Projects[key] = usd
+		case "account":
+			if b.Accounts == nil {
+				b.Accounts = map[string]float64{}
+			}
+			b.Accounts[key] = usd
+		default:
+			return Budget{}, fmt.Errorf("budget %q: unknown scope %q (want project or account)", entry, kind)
+		}
+	}
+	return b, nil
+}
+
+// String renders the budget the way ParseBudget reads it, scoped entries
+// sorted by key.
+func (b Budget) String() string {
+	var parts []string
+	if b.MonthlyUSD > 0 {
+		parts = append(parts, formatBudgetUSD(b.MonthlyUSD))
+	}
+	for _, scoped := range []struct {
+		kind   string
+		limits map[string]float64
+	}{{"project", b.Projects}, {"account", b.Accounts}} {
+		keys := make([]string, 0, len(scoped.limits))
+		for k := range scoped.limits {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			parts = append(parts, scoped.kind+":"+k+"="+formatBudgetUSD(scoped.limits[k]))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatBudgetUSD(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+// SettingsPath returns the wisp-deck settings file, where the budget is kept
+// alongside the other key=value preferences.
+func SettingsPath(home string) string {
+	return filepath.Join(wispDeckConfigDir(home), "settings")
+}
+
+// LoadBudget reads the "budget" key of a settings file. A missing file or key
+// is the zero Budget; a spec that does not parse is an error.
+func LoadBudget(settingsFile string) (Budget, error) {
+	f, err := os.Open(settingsFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Budget{}, nil
+		}
+		return Budget{}, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if spec, ok := strings.CutPrefix(sc.Text(), "budget="); ok {
+			return ParseBudget(spec)
+		}
+	}
+	return Budget{}, sc.Err()
+}
+
+// BudgetStatus is one cap measured against a month's spend. Scope is "" for
+// the overall cap, else "project" or "account" with the cap's Key. Priced is
+// false when a model had no rate, so SpentUSD understates the spend.
+type BudgetStatus struct {
+	Scope    string
+	Key      string
+	LimitUSD float64
+	SpentUSD float64
+	Priced   bool
+}
+
+// Percent is the share of the cap spent, in percent.
+func (s BudgetStatus) Percent() float64 {
+	if s.LimitUSD <= 0 {
+		return 0
+	}
+	return s.SpentUSD / s.LimitUSD * 100
+}
+
+// Threshold is the highest of BudgetThresholds the spend has reached, or 0
+// below the first.
+func (s BudgetStatus) Threshold() int {
+	crossed := 0
+	for _, t := range BudgetThresholds {
+		if s.Percent() >= float64(t) {
+			crossed = t
+		}
+	}
+	return crossed
+}
+
+// Label names the cap: "budget" for the overall one, else the scope and key.
+func (s BudgetStatus) Label() string {
+	if s.Scope == "" {
+		return "budget"
+	}
+	return s.Scope + " " + s.Key
+}
+
+// String is the one-line alert text, e.g. "82% of budget ($164/$200)".
+func (s BudgetStatus) String() string {
+	approx := ""
+	if !s.Priced {
+		approx = "~"
+	}
+	return fmt.Sprintf("%s%.0f%% of %s ($%.0f/$%.0f)", approx, s.Percent(), s.Label(), s.SpentUSD, s.LimitUSD)
+}
+
+// EvaluateBudget measures every cap in b against the spend of month (YYYY-MM)
+// in records, most-spent share first. A project cap matches a record whose
+// project path equals the key or ends in it as its base name.
+func EvaluateBudget(records []Record, b Budget, month string) []BudgetStatus {
+	var out []BudgetStatus
+	if b.MonthlyUSD > 0 {
+		out = append(out, BudgetStatus{LimitUSD: b.MonthlyUSD, Priced: true})
+	}
+	for k, v := range b.Projects {
+		out = append(out, BudgetStatus{Scope: "project", Key: k, LimitUSD: v, Priced: true})
+	}
+	for k, v := range b.Accounts {
+		out = append(out, BudgetStatus{Scope: "account", Key: k, LimitUSD: v, Priced: true})
+	}
+	for _, r := range records {
+		if r.Month() != month {
+			continue
+		}
+		usd, priced := ModelCostUSD(r.ModelUsage)
+		for i := range out {
+			s := &out[i]
+			switch s.Scope {
+			case "project":
+				if r.Project == "" || (r.Project != s.Key && filepath.Base(r.Project) != s.Key) {
+					continue
+				}
+			case "account":
+				if r.Account != s.Key {
+					continue
+				}
+			}
+			s.SpentUSD += usd
+			s.Priced = s.Priced && priced
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if pi, pj := out[i].Percent(), out[j].Percent(); pi != pj {
+			return pi > pj
+		}
+		return out[i].Label() < out[j].Label()
+	})
+	return out
+}
+
+// BudgetAlert returns the most-spent cap that has crossed a threshold, if any.
+func BudgetAlert(statuses []BudgetStatus) (BudgetStatus, bool) {
+	for _, s := range statuses {
+		if s.Threshold() > 0 {
+			return s, true
+		}
+	}
+	return BudgetStatus{}, false
+}
+
+// BudgetStatePath returns the budget alert state file, next to the settings.
+// Its first line holds the month, the headline alert's threshold and its text
+// ("2026-05 80 82% of budget ($164/$200)"), so the status line can show the
+// alert without rescanning usage. Each further line is the threshold last
+// alerted for one cap and its label ("50 project app"), so every cap is
+// announced once per threshold it crosses.
+func BudgetStatePath(home string) string {
+	return filepath.Join(wispDeckConfigDir(home), "budget-alert")
+}
+
+// RecordBudgetAlert writes the month's alert state for statuses (or clears it
+// when no cap has crossed a threshold) and returns the most-spent cap whose
+// threshold is higher than the one recorded for it before in the same month,
+// i.e. the crossing that is new and should be announced.
+func RecordBudgetAlert(path, month string, statuses []BudgetStatus) (BudgetStatus, bool, error) {
+	prev := map[string]int{}
+	if data, err := os.ReadFile(path); err == nil {
+		lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+		if fields := strings.Fields(lines[0]); len(fields) >= 2 && fields[0] == month {
+			for _, line := range lines[1:] {
+				threshold, label, _ := strings.Cut(line, " ")
+				if n, err := strconv.Atoi(threshold); err == nil {
+					prev[label] = n
+				}
+			}
+		}
+	}
+	headline, ok := BudgetAlert(statuses)
+	if !ok {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return BudgetStatus{}, false, err
+		}
+		return BudgetStatus{}, false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return BudgetStatus{}, false, err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %d %s\n", month, headline.Threshold(), headline)
+	var raised BudgetStatus
+	found := false
+	for _, s := range statuses { // most-spent first
+		t := s.Threshold()
+		if t == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "%d %s\n", t, s.Label())
+		if !found && t > prev[s.Label()] {
+			raised, found = s, true
+		}
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return BudgetStatus{}, false, err
+	}
+	return raised, found, nil
+}
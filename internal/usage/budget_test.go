@@ -0,0 +1,133 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseBudget_roundTripsScopedCaps(t *testing.T) {
+	b, err := ParseBudget(" $200, project:/src/app=50 ,account::codex=20.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.MonthlyUSD != 200 || b.Projects["/src/app"] != 50 || b.Accounts[":codex"] != 20.5 {
+		t.Errorf("budget = %+v", b)
+	}
+	if got, want := b.String(), "200,project:/src/app=50,account::codex=20.5"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if b, err := ParseBudget(""); err != nil || !b.IsZero() {
+		t.Errorf("empty spec = %+v, %v, want the zero budget", b, err)
+	}
+	for _, bad := range []string{"lots", "-5", "team:x=5", "project:=5"} {
+		if _, err := ParseBudget(bad); err == nil {
+			t.Errorf("ParseBudget(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestEvaluateBudget_thresholdsPerScope(t *testing.T) {
+	sonnet := func(day, project, account string, in int64) Record {
+		return Record{Day: day, Project: project, Account: account, ModelUsage: ModelUsage{Model: "claude-sonnet-4-5", Input: in}}
+	}
+	records := []Record{
+		sonnet("2026-05-02", "/src/app", "work", 30_000_000),         // $90
+		sonnet("2026-05-03", "/src/web", DefaultAccount, 10_000_000), // $30
+		sonnet("2026-04-30", "/src/app", "work", 90_000_000),         // last month
+	}
+	b := Budget{MonthlyUSD: 200, Projects: map[string]float64{"app": 100}, Accounts: map[string]float64{"work": 80}}
+
+	got := EvaluateBudget(records, b, "2026-05")
+	want := []struct {
+		label     string
+		spent     float64
+		threshold int
+	}{{"account work", 90, 100}, {"project app", 90, 80}, {"budget", 120, 50}}
+	if len(got) != len(want) {
+		t.Fatalf("statuses = %+v", got)
+	}
+	for i, w := range want {
+		s := got[i]
+		if s.Label() != w.label || s.SpentUSD < w.spent-0.001 || s.SpentUSD > w.spent+0.001 || s.Threshold() != w.threshold {
+			t.Errorf("statuses[%d] = %s (threshold %d), want %s $%.0f threshold %d", i, s, s.Threshold(), w.label, w.spent, w.threshold)
+		}
+	}
+	if a, ok := BudgetAlert(got); !ok || a.Label() != "account work" {
+		t.Errorf("alert = %+v, %v, want account work", a, ok)
+	}
+	if _, ok := BudgetAlert(EvaluateBudget(records, Budget{MonthlyUSD: 1000}, "2026-05")); ok {
+		t.Errorf("12%% of the budget raised an alert")
+	}
+}
+
+func TestRecordBudgetAlert_announcesEachCrossingOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budget-alert")
+	at := func(spent float64) BudgetStatus { return BudgetStatus{LimitUSD: 100, SpentUSD: spent, Priced: true} }
+	steps := []struct {
+		month string
+		spent float64
+		want  bool
+	}{
+		{"2026-05", 55, true},
+		{"2026-05", 60, false},
+		{"2026-05", 85, true},
+		{"2026-05", 85, false},
+		{"2026-06", 51, true},
+	}
+	for i, s := range steps {
+		_, raised, err := RecordBudgetAlert(path, s.month, []BudgetStatus{at(s.spent)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if raised != s.want {
+			t.Errorf("step %d (%s at %.0f%%): raised = %v, want %v", i, s.month, s.spent, raised, s.want)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	if got, want := string(data), "2026-06 50 51% of budget ($51/$100)\n50 budget\n"; got != want {
+		t.Errorf("state = %q, want %q", got, want)
+	}
+	if _, _, err := RecordBudgetAlert(path, "2026-06", []BudgetStatus{at(10)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("state file kept after the alert cleared")
+	}
+}
+
+// Each cap remembers its own threshold: the overall budget crossing 50% is
+// announced even while a project cap already sits higher at 80%.
+func TestRecordBudgetAlert_tracksEachCap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budget-alert")
+	project := BudgetStatus{Scope: "project", Key: "app", LimitUSD: 10, SpentUSD: 9, Priced: true}
+	overall := BudgetStatus{LimitUSD: 100, SpentUSD: 20, Priced: true}
+	if a, raised, _ := RecordBudgetAlert(path, "2026-05", []BudgetStatus{project, overall}); !raised || a.Label() != "project app" {
+		t.Fatalf("first check = %v/%v, want the project cap announced", a, raised)
+	}
+	overall.SpentUSD = 55
+	if a, raised, _ := RecordBudgetAlert(path, "2026-05", []BudgetStatus{project, overall}); !raised || a.Label() != "budget" {
+		t.Errorf("overall at 55%% = %v/%v, want the overall cap announced", a, raised)
+	}
+	if _, raised, _ := RecordBudgetAlert(path, "2026-05", []BudgetStatus{project, overall}); raised {
+		t.Errorf("an unchanged check announced again")
+	}
+	data, _ := os.ReadFile(path)
+	if got, want := string(data), "2026-05 80 90% of project app ($9/$10)\n80 project app\n50 budget\n"; got != want {
+		t.Errorf("state = %q, want %q", got, want)
+	}
+}
+
+func TestLoadBudget_readsTheSettingsKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings")
+	if err := os.WriteFile(path, []byte("theme=auto\nbudget=150,account:work=40\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := LoadBudget(path)
+	if err != nil || b.MonthlyUSD != 150 || b.Accounts["work"] != 40 {
+		t.Errorf("LoadBudget = %+v, %v", b, err)
+	}
+	if b, err := LoadBudget(filepath.Join(t.TempDir(), "missing")); err != nil || !b.IsZero() {
+		t.Errorf("missing settings = %+v, %v, want the zero budget", b, err)
+	}
+}
@@ -4,52 +4,87 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"syscall"
+
+	"github.com/jackuait/wisp-deck/internal/util"
 )
 
-// cacheVersion is 5: ParseFile now attributes each billed iteration of a turn to
-// its own model (model-fallback rounds were previously dropped), so v4 caches must
-// rebuild from on-disk transcripts to capture them. LoadCache rejects a mismatched
-// version.
-const cacheVersion = 5
+// cacheVersion is 10: Claude entries now list the message ids they counted, so
+// the proxy ledger can leave those calls out. Older
+// caches are migrated on load (see migrateV5 and migrateForReparse); any other
+// mismatched version is rejected by LoadCache.
+const cacheVersion = 10
 
-// fileCacheEntry stores one transcript file's identity and its parsed months.
+// fileCacheEntry stores one transcript file's identity and its parsed records.
+// Session and Tools are set for Claude transcripts only; OpenCode message files
+// are not indexed as conversations and record no tool calls.
 type fileCacheEntry struct {
-	Meta   FileMeta                 `json:"meta"`
-	Months map[string]*MonthlyUsage `json:"months"`
+	Meta    FileMeta     `json:"meta"`
+	Records []Record     `json:"records"`
+	Session *SessionSpan `json:"session,omitempty"`
+	Tools   []ToolRecord `json:"tools,omitempty"`
+	// MessageIDs lists the calls a Claude transcript counted, and Calls holds
+	// a proxy ledger's calls one by one, so a call both logged is counted once
+	// (see ledgerRecords).
+	MessageIDs []string     `json:"ids,omitempty"`
+	Calls      []ledgerCall `json:"calls,omitempty"`
 }
 
 // Cache is the persisted incremental-parse state. Files holds one entry per live
 // transcript (keyed by absolute path) for incremental re-parsing. Archive holds
-// durable month -> model -> totals folded from transcripts that have since been
-// deleted from disk, and Sealed records which paths have already been folded so
-// nothing is counted twice.
+// durable records folded from transcripts that have since been deleted from
+// disk (ToolArchive their tool calls), and Sealed records which paths have
+// already been folded so nothing is counted twice. SealedIDs keeps the message
+// ids of sealed transcripts that a live proxy ledger also logged, so those
+// calls stay counted once after the transcript is gone.
 type Cache struct {
-	Version int                               `json:"version"`
-	Files   map[string]fileCacheEntry         `json:"files"`
-	Archive map[string]map[string]*ModelUsage `json:"archive"`
-	Sealed  map[string]bool                   `json:"sealed"`
+	Version     int                       `json:"version"`
+	Files       map[string]fileCacheEntry `json:"files"`
+	Archive     []Record                  `json:"archive"`
+	ToolArchive []ToolRecord              `json:"tool_archive,omitempty"`
+	Sealed      map[string]bool           `json:"sealed"`
+	SealedIDs   map[string]bool           `json:"sealed_ids,omitempty"`
 }
 
 // LoadCache reads the cache file. A missing or corrupt cache returns an empty,
 // usable cache (so the stats screen can always rebuild from scratch) — never nil.
-// Archive and Sealed are always non-nil so callers can write to them directly.
+// Files and Sealed are always non-nil so callers can write to them directly.
 func LoadCache(path string) *Cache {
 	empty := &Cache{
 		Version: cacheVersion,
 		Files:   map[string]fileCacheEntry{},
-		Archive: map[string]map[string]*ModelUsage{},
 		Sealed:  map[string]bool{},
 	}
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return empty
 	}
-	var c Cache
-	if err := json.Unmarshal(data, &c); err != nil || c.Version != cacheVersion || c.Files == nil {
+	var probe struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
 		return empty
 	}
-	if c.Archive == nil {
-		c.Archive = map[string]map[string]*ModelUsage{}
+	var c Cache
+	switch probe.Version {
+	case cacheVersion:
+		if err := json.Unmarshal(data, &c); err != nil || c.Files == nil {
+			return empty
+		}
+	case 9, 8, 7, 6:
+		m, ok := migrateForReparse(data)
+		if !ok {
+			return empty
+		}
+		c = *m
+	case 5:
+		m, ok := migrateV5(data)
+		if !ok {
+			return empty
+		}
+		c = *m
+	default:
+		return empty
 	}
 	if c.Sealed == nil {
 		c.Sealed = map[string]bool{}
@@ -57,7 +92,68 @@ func LoadCache(path string) *Cache {
 	return &c
 }
 
-// Save writes the cache atomically (temp file + rename).
+// cacheV5 is the month-only cache layout written before records.
+type cacheV5 struct {
+	Files map[string]struct {
+		Meta   FileMeta                 `json:"meta"`
+		Months map[string]*MonthlyUsage `json:"months"`
+	} `json:"files"`
+	Archive map[string]map[string]*ModelUsage `json:"archive"`
+	Sealed  map[string]bool                   `json:"sealed"`
+}
+
+// migrateV5 converts a v5 cache without losing history. Archived months — whose
+// transcripts are gone and cannot be re-parsed — become month-precision records
+// (Day "YYYY-MM", no project). Live entries are kept the same way but with a
+// zero FileMeta, so a transcript still on disk is re-parsed at full grain on the
+// next scan, while one deleted since the v5 scan is still sealed into the
+// archive rather than lost.
+func migrateV5(data []byte) (*Cache, bool) {
+	var old cacheV5
+	if err := json.Unmarshal(data, &old); err != nil || old.Files == nil {
+		return nil, false
+	}
+	c := &Cache{Version: cacheVersion, Files: map[string]fileCacheEntry{}, Sealed: old.Sealed}
+	for path, e := range old.Files {
+		var records []Record
+		for month, mu := range e.Months {
+			for _, m := range mu.Models {
+				records = append(records, Record{Day: month, ModelUsage: m})
+			}
+		}
+		c.Files[path] = fileCacheEntry{Records: mergeRecords(records)}
+	}
+	var archive []Record
+	for month, byModel := range old.Archive {
+		for _, m := range byModel {
+			archive = append(archive, Record{Day: month, ModelUsage: *m})
+		}
+	}
+	c.Archive = mergeRecords(archive)
+	return c, true
+}
+
+// migrateForReparse upgrades a v6 cache, whose entries lack a SessionSpan, a
+// v7 one, whose entries lack their tool calls, a v8 one, whose records lack
+// their unread cache writes, or a v9 one, whose entries lack their message
+// ids. The records are kept but each
+// live entry's FileMeta is zeroed, as in migrateV5, so files still on disk are
+// re-parsed for what is missing and deleted ones are still sealed.
+func migrateForReparse(data []byte) (*Cache, bool) {
+	var c Cache
+	if err := json.Unmarshal(data, &c); err != nil || c.Files == nil {
+		return nil, false
+	}
+	c.Version = cacheVersion
+	for path, e := range c.Files {
+		c.Files[path] = fileCacheEntry{Records: e.Records}
+	}
+	return &c, true
+}
+
+// Save writes the cache atomically (see util.WriteFileAtomic) under an
+// exclusive flock on path+".lock", so the concurrent scans of several watchers
+// never interleave their writes.
 func (c *Cache) Save(path string) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return err
@@ -66,9 +162,13 @@ func (c *Cache) Save(path string) error {
 	if err != nil {
 		return err
 	}
-	tmp := path + ".tmp"
-	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+	lock, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	defer lock.Close() // closing releases the lock
+	if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err != nil {
 		return err
 	}
-	return os.Rename(tmp, path)
+	return util.WriteFileAtomic(path, data, 0o644)
 }
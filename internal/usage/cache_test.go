@@ -1,7 +1,10 @@
 package usage
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -29,8 +32,8 @@ func TestCache_saveThenLoadRoundTrips(t *testing.T) {
 	p := filepath.Join(t.TempDir(), "cache.json")
 	c := &Cache{Version: cacheVersion, Files: map[string]fileCacheEntry{
 		"/a.jsonl": {
-			Meta:   FileMeta{ModTime: time.Unix(1000, 0).UTC(), Size: 42},
-			Months: map[string]*MonthlyUsage{"2026-05": {Month: "2026-05", Input: 5}},
+			Meta:    FileMeta{ModTime: time.Unix(1000, 0).UTC(), Size: 42},
+			Records: []Record{{Day: "2026-05-01", Project: "/src/app", ModelUsage: ModelUsage{Model: "m", Input: 5}}},
 		},
 	}}
 	if err := c.Save(p); err != nil {
@@ -38,15 +41,45 @@ func TestCache_saveThenLoadRoundTrips(t *testing.T) {
 	}
 	got := LoadCache(p)
 	entry, ok := got.Files["/a.jsonl"]
-	if !ok || entry.Meta.Size != 42 || entry.Months["2026-05"].Input != 5 {
+	if !ok || entry.Meta.Size != 42 || len(entry.Records) != 1 || entry.Records[0].Project != "/src/app" || entry.Records[0].Input != 5 {
 		t.Errorf("round-trip mismatch: %+v", got.Files)
 	}
 }
 
-func TestLoadCache_initializesArchiveAndSealed(t *testing.T) {
+// Concurrent full scans (the watcher's background budget checks) each save
+// the whole cache; every write must land intact and leave no temp file behind.
+func TestCache_concurrentSavesStayWhole(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "cache.json")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(n int64) {
+			defer wg.Done()
+			c := &Cache{Version: cacheVersion, Files: map[string]fileCacheEntry{
+				"/a.jsonl": {Records: []Record{{Day: "2026-05-01", ModelUsage: ModelUsage{Model: "m", Input: n}}}},
+			}}
+			if err := c.Save(p); err != nil {
+				t.Error(err)
+			}
+		}(int64(i + 1))
+	}
+	wg.Wait()
+	if got := LoadCache(p); len(got.Files["/a.jsonl"].Records) != 1 {
+		t.Errorf("cache after concurrent saves = %+v, want one whole write", got.Files)
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			t.Errorf("temp file %s left behind", e.Name())
+		}
+	}
+}
+
+func TestLoadCache_initializesFilesAndSealed(t *testing.T) {
 	c := LoadCache(filepath.Join(t.TempDir(), "nope.json"))
-	if c.Archive == nil {
-		t.Errorf("Archive = nil, want initialized map")
+	if c.Files == nil {
+		t.Errorf("Files = nil, want initialized map")
 	}
 	if c.Sealed == nil {
 		t.Errorf("Sealed = nil, want initialized map")
@@ -58,16 +91,14 @@ func TestCache_archiveAndSealedRoundTrip(t *testing.T) {
 	c := &Cache{
 		Version: cacheVersion,
 		Files:   map[string]fileCacheEntry{},
-		Archive: map[string]map[string]*ModelUsage{
-			"2026-05": {"claude-opus-4-7": {Model: "claude-opus-4-7", Input: 7}},
-		},
-		Sealed: map[string]bool{"/gone.jsonl": true},
+		Archive: []Record{{Day: "2026-05-01", ModelUsage: ModelUsage{Model: "claude-opus-4-7", Input: 7}}},
+		Sealed:  map[string]bool{"/gone.jsonl": true},
 	}
 	if err := c.Save(p); err != nil {
 		t.Fatal(err)
 	}
 	got := LoadCache(p)
-	if got.Archive["2026-05"]["claude-opus-4-7"].Input != 7 {
+	if len(got.Archive) != 1 || got.Archive[0].Input != 7 {
 		t.Errorf("archive round-trip mismatch: %+v", got.Archive)
 	}
 	if !got.Sealed["/gone.jsonl"] {
@@ -81,7 +112,47 @@ func TestLoadCache_rejectsOlderVersion(t *testing.T) {
 	writeFixture(t, filepath.Dir(p), "cache.json",
 		`{"version":2,"files":{"/a.jsonl":{"meta":{"mod_time":"2026-05-01T00:00:00Z","size":1},"months":{}}}}`)
 	c := LoadCache(p)
-	if len(c.Files) != 0 || c.Archive == nil || c.Sealed == nil {
-		t.Errorf("v2 cache should rebuild empty v3, got %+v", c)
+	if len(c.Files) != 0 || c.Files == nil || c.Sealed == nil {
+		t.Errorf("v2 cache should rebuild empty, got %+v", c)
+	}
+}
+
+func TestLoadCache_migratesMonthOnlyV5(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "cache.json")
+	writeFixture(t, filepath.Dir(p), "cache.json", `{"version":5,
+		"files":{"/live.jsonl":{"meta":{"mod_time":"2026-05-01T00:00:00Z","size":9},
+			"months":{"2026-05":{"month":"2026-05","input":3,"models":[{"model":"m","input":3}]}}}},
+		"archive":{"2026-04":{"m":{"model":"m","input":7,"cache_write":2,"cache_write_1h":2}}},
+		"sealed":{"/gone.jsonl":true}}`)
+	c := LoadCache(p)
+	if c.Version != cacheVersion || !c.Sealed["/gone.jsonl"] {
+		t.Fatalf("migrated cache = %+v", c)
+	}
+	if len(c.Archive) != 1 || c.Archive[0].Day != "2026-04" || c.Archive[0].Input != 7 || c.Archive[0].CacheWrite1h != 2 {
+		t.Errorf("archive = %+v, want the April history kept at month precision", c.Archive)
+	}
+	live := c.Files["/live.jsonl"]
+	if len(live.Records) != 1 || live.Records[0].Day != "2026-05" || live.Records[0].Input != 3 {
+		t.Errorf("live entry = %+v, want its months kept", live)
+	}
+	if !live.Meta.ModTime.IsZero() || live.Meta.Size != 0 {
+		t.Errorf("live entry meta = %+v, want zeroed so the transcript is re-parsed at day grain", live.Meta)
+	}
+}
+
+// A transcript deleted after the last v5 scan is still sealed into the archive
+// after migration, so its month is not lost.
+func TestAggregate_v5EntryForDeletedFileIsSealed(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+	writeFixture(t, filepath.Dir(cachePath), "cache.json", `{"version":5,
+		"files":{"/gone.jsonl":{"meta":{"mod_time":"2026-05-01T00:00:00Z","size":9},
+			"months":{"2026-05":{"month":"2026-05","input":3,"models":[{"model":"m","input":3}]}}}},
+		"archive":{},"sealed":{}}`)
+	out, err := Aggregate(t.TempDir(), "", cachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 || out[0].Month != "2026-05" || out[0].Input != 3 {
+		t.Errorf("out = %+v, want May's 3 tokens kept", out)
 	}
 }
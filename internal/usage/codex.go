@@ -0,0 +1,102 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// codexLine is the subset of a Codex CLI rollout line we read. The CLI writes
+// one rollout-<time>-<id>.jsonl per session under
+// ${CODEX_HOME:-~/.codex}/sessions/YYYY/MM/DD/, each line a timestamped item:
+// session_meta and turn_context carry the cwd (turn_context also the model),
+// and event_msg lines of type token_count carry the session's running token
+// totals. Note that input_tokens includes cached_input_tokens, and that
+// output_tokens already includes the reasoning tokens.
+type codexLine struct {
+	Timestamp string `json:"timestamp"`
+	Type      string `json:"type"`
+	Payload   struct {
+		Type  string `json:"type"`
+		Cwd   string `json:"cwd"`
+		Model string `json:"model"`
+		Info  *struct {
+			Total codexTokens `json:"total_token_usage"`
+		} `json:"info"`
+	} `json:"payload"`
+}
+
+// codexTokens is a Codex token_usage object.
+type codexTokens struct {
+	Input       int64 `json:"input_tokens"`
+	CachedInput int64 `json:"cached_input_tokens"`
+	Output      int64 `json:"output_tokens"`
+}
+
+// isCodexSession matches the Codex CLI's rollout files.
+func isCodexSession(path string) bool {
+	base := filepath.Base(path)
+	return strings.HasPrefix(base, "rollout-") && strings.HasSuffix(base, ".jsonl")
+}
+
+// ParseCodexRecords reads a Codex CLI rollout file into records, matching
+// ParseFileRecords' contract. token_count events repeat the session's running
+// totals (the CLI re-emits them unchanged, e.g. after a rate-limit update), so
+// each call is the growth of total_token_usage since the previous event,
+// billed on the day of the event to the model and cwd of the latest
+// turn_context. Malformed lines are skipped, and a token_count event without a
+// timestamp folds into the next one. A session with no model is attributed to
+// "unknown".
+func ParseCodexRecords(path string) ([]Record, FileMeta, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, FileMeta{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, FileMeta{}, err
+	}
+	meta := FileMeta{ModTime: info.ModTime(), Size: info.Size()}
+
+	acc := recordSet{}
+	var prev codexTokens
+	model, project := "unknown", ""
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	for sc.Scan() {
+		var line codexLine
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			continue
+		}
+		p := line.Payload
+		switch {
+		case line.Type == "session_meta" || line.Type == "turn_context":
+			if p.Cwd != "" {
+				project = p.Cwd
+			}
+			if p.Model != "" {
+				model = p.Model
+			}
+		case line.Type == "event_msg" && p.Type == "token_count" && p.Info != nil:
+			if len(line.Timestamp) < 10 {
+				continue
+			}
+			cur := p.Info.Total
+			d := codexTokens{Input: cur.Input - prev.Input, CachedInput: cur.CachedInput - prev.CachedInput, Output: cur.Output - prev.Output}
+			if d.Input < 0 || d.CachedInput < 0 || d.Output < 0 {
+				d = cur // the totals restarted; count them afresh
+			}
+			prev = cur
+			acc.addCounts(line.Timestamp[:10], project, model, usageCounts{
+				Input:     max(0, d.Input-d.CachedInput),
+				Output:    d.Output,
+				CacheRead: d.CachedInput,
+			})
+		}
+	}
+	return acc.records(), meta, sc.Err()
+}
@@ -0,0 +1,73 @@
+package usage
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+// cxTokens renders a Codex token_count event carrying the session's running
+// totals.
+func cxTokens(ts string, in, cached, out int64) string {
+	return fmt.Sprintf(`{"timestamp":%q,"type":"event_msg","payload":{"type":"token_count","info":`+
+		`{"total_token_usage":{"input_tokens":%d,"cached_input_tokens":%d,"output_tokens":%d,"reasoning_output_tokens":0,"total_tokens":%d},`+
+		`"last_token_usage":{"input_tokens":0,"cached_input_tokens":0,"output_tokens":0,"total_tokens":0}}}}`+"\n",
+		ts, in, cached, out, in+out)
+}
+
+func cxContext(ts, cwd, model string) string {
+	return fmt.Sprintf(`{"timestamp":%q,"type":"turn_context","payload":{"cwd":%q,"model":%q,"approval_policy":"on-request"}}`+"\n", ts, cwd, model)
+}
+
+func TestParseCodexRecords_billsTheGrowthOfTheRunningTotals(t *testing.T) {
+	dir := t.TempDir()
+	body := `{"timestamp":"2026-05-01T09:00:00.000Z","type":"session_meta","payload":{"id":"s1","cwd":"/src/app"}}` + "\n" +
+		cxContext("2026-05-01T09:00:01.000Z", "/src/app", "gpt-5-codex") +
+		cxTokens("2026-05-01T09:00:05.000Z", 1000, 400, 50) +
+		`{"timestamp":"2026-05-01T09:00:06.000Z","type":"event_msg","payload":{"type":"token_count","info":null}}` + "\n" +
+		cxTokens("2026-05-01T09:00:07.000Z", 1000, 400, 50) + // re-emitted unchanged
+		cxTokens("2026-05-02T10:00:00.000Z", 3000, 1400, 80)
+	records, meta, err := ParseCodexRecords(writeFixture(t, dir, "rollout-2026-05-01T09-00-00-s1.jsonl", body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Size == 0 {
+		t.Errorf("meta.Size = 0, want non-zero")
+	}
+	want := []Record{
+		{Day: "2026-05-01", Project: "/src/app", ModelUsage: ModelUsage{Model: "gpt-5-codex", Input: 600, CacheRead: 400, Output: 50}},
+		{Day: "2026-05-02", Project: "/src/app", ModelUsage: ModelUsage{Model: "gpt-5-codex", Input: 1000, CacheRead: 1000, Output: 30}},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("records = %+v, want %+v", records, want)
+	}
+	for i := range want {
+		if records[i] != want[i] {
+			t.Errorf("records[%d] = %+v, want %+v", i, records[i], want[i])
+		}
+	}
+}
+
+func TestParseCodexRecords_skipsMalformedLinesAndUnknownModel(t *testing.T) {
+	dir := t.TempDir()
+	body := "not json\n" + cxTokens("2026-05-01T09:00:05.000Z", 10, 0, 2)
+	records, _, err := ParseCodexRecords(writeFixture(t, dir, "rollout-x.jsonl", body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Model != "unknown" || records[0].Input != 10 || records[0].Output != 2 {
+		t.Errorf("records = %+v, want one unknown-model row of 10 in / 2 out", records)
+	}
+}
+
+func TestIsCodexSession(t *testing.T) {
+	for path, want := range map[string]bool{
+		filepath.Join("sessions", "2026", "05", "01", "rollout-2026-05-01T09-00-00-s1.jsonl"): true,
+		filepath.Join("sessions", "history.jsonl"):                                            false,
+		filepath.Join("sessions", "rollout-s1.json"):                                          false,
+	} {
+		if got := isCodexSession(path); got != want {
+			t.Errorf("isCodexSession(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
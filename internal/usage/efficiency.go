@@ -0,0 +1,137 @@
+package usage
+
+// cacheCall is the cache traffic of one billed call: it read the prompt prefix
+// up to read tokens and wrote the next write tokens (write1h of them at the
+// 1-hour TTL).
+type cacheCall struct {
+	day, project         string
+	read, write, write1h int64
+}
+
+// cacheLedger follows the prompt cache through one transcript, per model (a
+// cache is never shared across models). Each call writes the prefix from its
+// cache-read length up to read+write; a later call whose read reaches past that
+// start has read the write back, up to its own read length. Whatever lies beyond
+// the furthest later read was never read: the conversation ended, or the cache
+// expired before the next turn and the prefix had to be written again.
+type cacheLedger map[string][]cacheCall
+
+// add records one billed call in file order.
+func (l cacheLedger) add(day, project string, m ModelUsage) {
+	if m.CacheWrite == 0 && m.CacheRead == 0 {
+		return
+	}
+	l[m.Model] = append(l[m.Model], cacheCall{day: day, project: project, read: m.CacheRead, write: m.CacheWrite, write1h: m.CacheWrite1h})
+}
+
+// unread returns a row per call with cache writes no later call read back,
+// carrying only CacheUnread and CacheUnread1h so it folds into that call's own
+// record. The 1-hour share is split in proportion to the call's writes.
+func (l cacheLedger) unread() []Record {
+	var out []Record
+	for model, calls := range l {
+		var later int64 // the furthest read by any later call
+		for i := len(calls) - 1; i >= 0; i-- {
+			c := calls[i]
+			if c.write > 0 {
+				covered := min(max(later-c.read, 0), c.write)
+				if n := c.write - covered; n > 0 {
+					out = append(out, Record{Day: c.day, Project: c.project, ModelUsage: ModelUsage{
+						Model:         model,
+						CacheUnread:   n,
+						CacheUnread1h: n * min(c.write1h, c.write) / c.write,
+					}})
+				}
+			}
+			later = max(later, c.read)
+		}
+	}
+	return out
+}
+
+// CacheEfficiency is how well one group of usage used the prompt cache. Input,
+// CacheWrite (CacheWrite1h its 1-hour-TTL share) and CacheRead are its token
+// columns; CacheUnread is the share of CacheWrite never read back (tracked for
+// Claude transcripts only). SavedUSD is what the cache reads would have cost as
+// uncached input less what they did cost; WriteUSD is the cost of every cache
+// write, Write1hUSD that of the 1-hour ones and UnreadUSD that of the unread
+// ones. Priced is false when a model had no rate, so the dollar figures
+// leave it out.
+type CacheEfficiency struct {
+	Key          string  `json:"key"`
+	Input        int64   `json:"input"`
+	CacheWrite   int64   `json:"cache_write"`
+	CacheWrite1h int64   `json:"cache_write_1h"`
+	CacheRead    int64   `json:"cache_read"`
+	CacheUnread  int64   `json:"cache_unread"`
+	SavedUSD     float64 `json:"saved_usd"`
+	WriteUSD     float64 `json:"write_usd"`
+	Write1hUSD   float64 `json:"write_1h_usd"`
+	UnreadUSD    float64 `json:"unread_usd"`
+	Priced       bool    `json:"priced"`
+}
+
+// HitRatio returns the share of all input tokens (uncached, cache-written and
+// cache-read) served from the cache.
+func (e CacheEfficiency) HitRatio() float64 {
+	total := e.Input + e.CacheWrite + e.CacheRead
+	if total == 0 {
+		return 0
+	}
+	return float64(e.CacheRead) / float64(total)
+}
+
+// UnreadRatio returns the share of cache-written tokens never read back.
+func (e CacheEfficiency) UnreadRatio() float64 {
+	if e.CacheWrite == 0 {
+		return 0
+	}
+	return float64(e.CacheUnread) / float64(e.CacheWrite)
+}
+
+// add folds one model's usage in, pricing each column at ModelCostUSD rates.
+func (e *CacheEfficiency) add(m ModelUsage) {
+	e.Input += m.Input
+	e.CacheWrite += m.CacheWrite
+	e.CacheWrite1h += m.CacheWrite1h
+	e.CacheRead += m.CacheRead
+	e.CacheUnread += m.CacheUnread
+
+	if _, ok := ModelCostUSD(m); !ok {
+		e.Priced = false
+		return
+	}
+	asInput, _ := ModelCostUSD(ModelUsage{Model: m.Model, Input: m.CacheRead})
+	asRead, _ := ModelCostUSD(ModelUsage{Model: m.Model, CacheRead: m.CacheRead})
+	written, _ := ModelCostUSD(ModelUsage{Model: m.Model, CacheWrite: m.CacheWrite, CacheWrite1h: m.CacheWrite1h})
+	written1h, _ := ModelCostUSD(ModelUsage{Model: m.Model, CacheWrite: m.CacheWrite1h, CacheWrite1h: m.CacheWrite1h})
+	unread, _ := ModelCostUSD(ModelUsage{Model: m.Model, CacheWrite: m.CacheUnread, CacheWrite1h: m.CacheUnread1h})
+	e.SavedUSD += asInput - asRead
+	e.WriteUSD += written
+	e.Write1hUSD += written1h
+	e.UnreadUSD += unread
+}
+
+// CacheEfficiencyBy folds records into one CacheEfficiency per key of by, in
+// GroupRecords order.
+func CacheEfficiencyBy(records []Record, by Dimension) []CacheEfficiency {
+	groups := GroupRecords(records, by)
+	out := make([]CacheEfficiency, 0, len(groups))
+	for _, g := range groups {
+		e := CacheEfficiency{Key: g.Key, Priced: true}
+		for _, m := range g.Models {
+			e.add(m)
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// CacheEfficiencyTotal folds every record into one CacheEfficiency.
+func CacheEfficiencyTotal(records []Record) CacheEfficiency {
+	e := CacheEfficiency{Key: "Total", Priced: true}
+	for _, r := range records {
+		e.add(r.ModelUsage)
+	}
+	return e
+}
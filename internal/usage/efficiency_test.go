@@ -0,0 +1,62 @@
+package usage
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// cacheTranscript writes a 1,000-token prefix, reads it back while extending it
+// by 500, then reads only 800 tokens (the extension expired unread), and finally
+// an unpriced model writes 50 tokens nothing reads.
+const cacheTranscript = `{"type":"assistant","timestamp":"2026-05-01T09:00:00Z","cwd":"/src/app","message":{"id":"a","model":"claude-sonnet-4-5","usage":{"input_tokens":200,"cache_creation_input_tokens":1000}}}
+{"type":"assistant","timestamp":"2026-05-01T09:01:00Z","cwd":"/src/app","message":{"id":"b","model":"claude-sonnet-4-5","usage":{"cache_read_input_tokens":1000,"cache_creation_input_tokens":500}}}
+{"type":"assistant","timestamp":"2026-05-01T09:30:00Z","cwd":"/src/app","message":{"id":"c","model":"claude-sonnet-4-5","usage":{"cache_read_input_tokens":800}}}
+{"type":"assistant","timestamp":"2026-05-01T09:31:00Z","cwd":"/src/app","message":{"id":"d","model":"mystery","usage":{"cache_creation_input_tokens":50}}}
+`
+
+func TestScan_countsCacheWritesNeverReadBack(t *testing.T) {
+	root := t.TempDir()
+	proj := filepath.Join(root, "projects", "-src-app")
+	mkdirAll(t, proj)
+	writeFixture(t, proj, "s.jsonl", cacheTranscript)
+
+	records, err := Collect(Sources([]string{filepath.Join(root, "projects")}, ""), filepath.Join(root, "cache.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	by := map[string]CacheEfficiency{}
+	for _, e := range CacheEfficiencyBy(records, ByModel) {
+		by[e.Key] = e
+	}
+	sonnet := by["claude-sonnet-4-5"]
+	if sonnet.CacheWrite != 1500 || sonnet.CacheRead != 1800 || sonnet.CacheUnread != 500 {
+		t.Fatalf("sonnet = %+v, want 1500 written, 1800 read, 500 unread", sonnet)
+	}
+	if got, want := sonnet.HitRatio(), 1800.0/3500; math.Abs(got-want) > 1e-9 {
+		t.Errorf("hit ratio = %v, want %v", got, want)
+	}
+	// Sonnet input is $3/MTok, a cache read $0.30 and a 5-minute write $3.75.
+	if !sonnet.Priced || math.Abs(sonnet.SavedUSD-1800*2.7/1e6) > 1e-12 ||
+		math.Abs(sonnet.WriteUSD-1500*3.75/1e6) > 1e-12 || math.Abs(sonnet.UnreadUSD-500*3.75/1e6) > 1e-12 {
+		t.Errorf("sonnet dollars = saved %v write %v unread %v", sonnet.SavedUSD, sonnet.WriteUSD, sonnet.UnreadUSD)
+	}
+	if m := by["mystery"]; m.CacheUnread != 50 || m.Priced || m.UnreadUSD != 0 {
+		t.Errorf("mystery = %+v, want 50 unread and unpriced", m)
+	}
+
+	total := CacheEfficiencyTotal(records)
+	if total.CacheUnread != 550 || total.Priced || math.Abs(total.UnreadUSD-sonnet.UnreadUSD) > 1e-12 {
+		t.Errorf("total = %+v", total)
+	}
+}
+
+func TestCacheLedger_splitsTheOneHourShare(t *testing.T) {
+	l := cacheLedger{}
+	l.add("2026-05-01", "/a", ModelUsage{Model: "m", CacheWrite: 400, CacheWrite1h: 100})
+	l.add("2026-05-01", "/a", ModelUsage{Model: "m", CacheRead: 200})
+	got := l.unread()
+	if len(got) != 1 || got[0].CacheUnread != 200 || got[0].CacheUnread1h != 50 {
+		t.Errorf("unread = %+v, want 200 of which 50 at the 1-hour TTL", got)
+	}
+}
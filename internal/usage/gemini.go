@@ -0,0 +1,85 @@
+package usage
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// geminiChat is the subset of a Gemini CLI chat log we read. The CLI records
+// each session as one JSON document under
+// ~/.gemini/tmp/<project-hash>/chats/session-*.json, rewritten as the chat
+// goes on. Only "gemini" messages carry tokens. Note that tokens.input
+// includes tokens.cached (it is the API's promptTokenCount), while the
+// thinking tokens and the tool-use prompt tokens are counted apart.
+type geminiChat struct {
+	Messages []struct {
+		Type      string `json:"type"`
+		Timestamp string `json:"timestamp"`
+		Model     string `json:"model"`
+		Tokens    *struct {
+			Input    int64 `json:"input"`
+			Output   int64 `json:"output"`
+			Cached   int64 `json:"cached"`
+			Thoughts int64 `json:"thoughts"`
+			Tool     int64 `json:"tool"`
+		} `json:"tokens"`
+	} `json:"messages"`
+}
+
+// isGeminiChat matches the Gemini CLI's chat logs.
+func isGeminiChat(path string) bool {
+	base := filepath.Base(path)
+	return strings.HasPrefix(base, "session-") && strings.HasSuffix(base, ".json") &&
+		filepath.Base(filepath.Dir(path)) == "chats"
+}
+
+// ParseGeminiRecords reads a Gemini CLI chat log into records, matching
+// ParseFileRecords' contract. Messages without tokens or a timestamp, and
+// malformed files, are skipped (returning no records, not an error). A message
+// with no model is attributed to "unknown". Thinking tokens are folded into
+// Output and tool-use prompt tokens into Input, as they are billed. The log
+// only names its project by a hash of the cwd, so the project is "".
+func ParseGeminiRecords(path string) ([]Record, FileMeta, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, FileMeta{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, FileMeta{}, err
+	}
+	meta := FileMeta{ModTime: info.ModTime(), Size: info.Size()}
+
+	var empty []Record
+	data, err := io.ReadAll(io.LimitReader(f, maxLineBytes))
+	if err != nil {
+		return nil, meta, err
+	}
+	var chat geminiChat
+	if err := json.Unmarshal(data, &chat); err != nil {
+		return empty, meta, nil // skip non-JSON / unexpected shape
+	}
+
+	acc := recordSet{}
+	for _, msg := range chat.Messages {
+		t := msg.Tokens
+		if msg.Type != "gemini" || t == nil || len(msg.Timestamp) < 10 {
+			continue
+		}
+		model := msg.Model
+		if model == "" {
+			model = "unknown"
+		}
+		acc.addCounts(msg.Timestamp[:10], "", model, usageCounts{
+			Input:     max(0, t.Input-t.Cached) + t.Tool,
+			Output:    t.Output + t.Thoughts,
+			CacheRead: t.Cached,
+		})
+	}
+	return acc.records(), meta, nil
+}
@@ -0,0 +1,69 @@
+package usage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+const geminiFixture = `{
+  "sessionId": "s1",
+  "projectHash": "0f3c",
+  "startTime": "2026-05-01T09:00:00.000Z",
+  "messages": [
+    {"id": "1", "timestamp": "2026-05-01T09:00:00.000Z", "type": "user", "content": "hi"},
+    {"id": "2", "timestamp": "2026-05-01T09:00:02.000Z", "type": "gemini", "content": "hello",
+     "model": "gemini-2.5-pro",
+     "tokens": {"input": 1000, "output": 40, "cached": 600, "thoughts": 10, "tool": 5, "total": 1055}},
+    {"id": "3", "timestamp": "2026-05-02T09:00:02.000Z", "type": "gemini", "content": "again",
+     "tokens": {"input": 20, "output": 2, "cached": 0, "thoughts": 0, "tool": 0, "total": 22}},
+    {"id": "4", "timestamp": "2026-05-02T09:00:03.000Z", "type": "gemini", "content": "no usage"}
+  ]
+}`
+
+func TestParseGeminiRecords_geminiMessagesByDayAndModel(t *testing.T) {
+	dir := t.TempDir()
+	records, meta, err := ParseGeminiRecords(writeFixture(t, dir, "session-2026-05-01T09-00-s1.json", geminiFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Size == 0 {
+		t.Errorf("meta.Size = 0, want non-zero")
+	}
+	// input 1000 includes the 600 cached; tool prompt tokens (5) bill as input
+	// and thoughts (10) as output.
+	want := []Record{
+		{Day: "2026-05-01", ModelUsage: ModelUsage{Model: "gemini-2.5-pro", Input: 405, Output: 50, CacheRead: 600}},
+		{Day: "2026-05-02", ModelUsage: ModelUsage{Model: "unknown", Input: 20, Output: 2}},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("records = %+v, want %+v", records, want)
+	}
+	for i := range want {
+		if records[i] != want[i] {
+			t.Errorf("records[%d] = %+v, want %+v", i, records[i], want[i])
+		}
+	}
+}
+
+func TestParseGeminiRecords_skipsMalformedJSON(t *testing.T) {
+	dir := t.TempDir()
+	records, _, err := ParseGeminiRecords(writeFixture(t, dir, "session-bad.json", "{not json"))
+	if err != nil {
+		t.Fatalf("malformed file should not error, got %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("records = %+v, want none", records)
+	}
+}
+
+func TestIsGeminiChat(t *testing.T) {
+	for path, want := range map[string]bool{
+		filepath.Join("tmp", "0f3c", "chats", "session-2026-05-01T09-00-s1.json"): true,
+		filepath.Join("tmp", "0f3c", "logs.json"):                                 false,
+		filepath.Join("tmp", "0f3c", "checkpoints", "session-1.json"):             false,
+	} {
+		if got := isGeminiChat(path); got != want {
+			t.Errorf("isGeminiChat(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
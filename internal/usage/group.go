@@ -0,0 +1,179 @@
+package usage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Dimension is a way to group usage records.
+type Dimension string
+
+// Grouping dimensions, in the order the CLI lists them.
+const (
+	ByDay     Dimension = "day"
+	ByWeek    Dimension = "week"
+	ByMonth   Dimension = "month"
+	ByProject Dimension = "project"
+	ByModel   Dimension = "model"
+	ByAccount Dimension = "account"
+)
+
+// Dimensions lists every grouping dimension.
+var Dimensions = []Dimension{ByDay, ByWeek, ByMonth, ByProject, ByModel, ByAccount}
+
+// ParseDimension resolves a dimension name, case-insensitively.
+func ParseDimension(s string) (Dimension, error) {
+	d := Dimension(strings.ToLower(strings.TrimSpace(s)))
+	for _, known := range Dimensions {
+		if d == known {
+			return d, nil
+		}
+	}
+	names := make([]string, len(Dimensions))
+	for i, known := range Dimensions {
+		names[i] = string(known)
+	}
+	return "", fmt.Errorf("unknown grouping %q (want %s)", s, strings.Join(names, ", "))
+}
+
+// Temporal reports whether d groups by time, so groups sort newest-first
+// rather than by size.
+func (d Dimension) Temporal() bool { return d == ByDay || d == ByWeek || d == ByMonth }
+
+// Key returns the group r falls under: its day (YYYY-MM-DD), ISO week
+// (YYYY-Www), month (YYYY-MM), project path, model id, or account key.
+// Month-precision records migrated from an old cache have no day, so day and
+// week keep them under their month.
+func (d Dimension) Key(r Record) string {
+	switch d {
+	case ByDay:
+		return r.Day
+	case ByWeek:
+		t, err := time.Parse("2006-01-02", r.Day)
+		if err != nil {
+			return r.Month()
+		}
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case ByMonth:
+		return r.Month()
+	case ByProject:
+		return r.Project
+	case ByModel:
+		return r.Model
+	case ByAccount:
+		return r.Account
+	}
+	return ""
+}
+
+// GroupUsage is the usage of one group. The flat fields are the sum across
+// Models; Models is the per-model breakdown sorted by Total() desc.
+type GroupUsage struct {
+	Key        string       `json:"key"`
+	Input      int64        `json:"input"`
+	Output     int64        `json:"output"`
+	CacheWrite int64        `json:"cache_write"`
+	CacheRead  int64        `json:"cache_read"`
+	Models     []ModelUsage `json:"models"`
+}
+
+// Total returns the sum of all token columns.
+func (g GroupUsage) Total() int64 {
+	return g.Input + g.Output + g.CacheWrite + g.CacheRead
+}
+
+// GroupRecords folds records into one GroupUsage per key of by. Time groupings
+// are ordered newest-first; the others by total tokens, largest first
+// (tie-break by key). Records of an unknown project or account share the ""
+// group.
+func GroupRecords(records []Record, by Dimension) []GroupUsage {
+	acc := map[string]map[string]*ModelUsage{}
+	for _, r := range records {
+		key := by.Key(r)
+		byModel := acc[key]
+		if byModel == nil {
+			byModel = map[string]*ModelUsage{}
+			acc[key] = byModel
+		}
+		a := byModel[r.Model]
+		if a == nil {
+			a = &ModelUsage{Model: r.Model}
+			byModel[r.Model] = a
+		}
+		addUsage(a, r.ModelUsage)
+	}
+	out := make([]GroupUsage, 0, len(acc))
+	for key, byModel := range acc {
+		g := GroupUsage{Key: key, Models: sortedModels(byModel)}
+		if len(g.Models) == 0 {
+			continue
+		}
+		for _, m := range g.Models {
+			g.Input += m.Input
+			g.Output += m.Output
+			g.CacheWrite += m.CacheWrite
+			g.CacheRead += m.CacheRead
+		}
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if by.Temporal() {
+			return out[i].Key > out[j].Key
+		}
+		if ti, tj := out[i].Total(), out[j].Total(); ti != tj {
+			return ti > tj
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// CostUSD sums the cost of every priced model in the group. allPriced is false
+// if any model lacked a pricing entry.
+func (g GroupUsage) CostUSD() (float64, bool) {
+	return costOf(g.Models)
+}
+
+// FilterRecords keeps the records whose day falls within [since, until], both
+// inclusive. A bound is a day (YYYY-MM-DD), a month (YYYY-MM, covering the whole
+// month) or "" for unbounded. Bounds are compared at the coarser of the two
+// precisions, so a month-precision record is kept when its month overlaps the
+// range at all.
+func FilterRecords(records []Record, since, until string) []Record {
+	out := make([]Record, 0, len(records))
+	for _, r := range records {
+		if since != "" {
+			n := min(len(r.Day), len(since))
+			if r.Day[:n] < since[:n] {
+				continue
+			}
+		}
+		if until != "" {
+			n := min(len(r.Day), len(until))
+			if r.Day[:n] > until[:n] {
+				continue
+			}
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// DayUsage folds the records of one UTC day (YYYY-MM-DD) into a GroupUsage
+// keyed by it, empty when the day has none. Unlike FilterRecords it leaves out
+// month-precision history, which cannot be placed on a day.
+func DayUsage(records []Record, day string) GroupUsage {
+	var same []Record
+	for _, r := range records {
+		if r.Day == day {
+			same = append(same, r)
+		}
+	}
+	if g := GroupRecords(same, ByDay); len(g) > 0 {
+		return g[0]
+	}
+	return GroupUsage{Key: day}
+}
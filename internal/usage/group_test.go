@@ -0,0 +1,105 @@
+package usage
+
+import (
+	"strings"
+	"testing"
+)
+
+func rec(day, project, model string, in int64) Record {
+	return Record{Day: day, Project: project, ModelUsage: ModelUsage{Model: model, Input: in}}
+}
+
+func groupKeys(gs []GroupUsage) []string {
+	keys := make([]string, len(gs))
+	for i, g := range gs {
+		keys[i] = g.Key
+	}
+	return keys
+}
+
+func TestGroupRecords_eachDimension(t *testing.T) {
+	records := []Record{
+		rec("2026-04-30", "/a", "opus", 1),
+		rec("2026-05-01", "/b", "opus", 10),
+		rec("2026-05-04", "/a", "sonnet", 100),
+		rec("2026-05", "", "opus", 1000), // migrated month-precision row
+	}
+	cases := []struct {
+		by   Dimension
+		want []string
+	}{
+		{ByDay, []string{"2026-05-04", "2026-05-01", "2026-05", "2026-04-30"}},
+		{ByWeek, []string{"2026-W19", "2026-W18", "2026-05"}},
+		{ByMonth, []string{"2026-05", "2026-04"}},
+		{ByProject, []string{"", "/a", "/b"}},
+		{ByModel, []string{"opus", "sonnet"}},
+	}
+	for _, c := range cases {
+		got := groupKeys(GroupRecords(records, c.by))
+		if len(got) != len(c.want) {
+			t.Errorf("%s keys = %v, want %v", c.by, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("%s keys = %v, want %v", c.by, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestGroupRecords_sumsModelsWithinAGroup(t *testing.T) {
+	gs := GroupRecords([]Record{
+		rec("2026-05-01", "/a", "opus", 3),
+		rec("2026-05-02", "/a", "opus", 4),
+		rec("2026-05-02", "/a", "sonnet", 10),
+	}, ByProject)
+	if len(gs) != 1 {
+		t.Fatalf("groups = %+v, want one for /a", gs)
+	}
+	g := gs[0]
+	if g.Input != 17 || len(g.Models) != 2 || g.Models[0].Model != "sonnet" || g.Models[1].Input != 7 {
+		t.Errorf("group = %+v, want 17 input split sonnet 10 / opus 7", g)
+	}
+}
+
+func TestParseDimension(t *testing.T) {
+	for _, s := range []string{"day", "Week", " MONTH ", "project", "model"} {
+		if _, err := ParseDimension(s); err != nil {
+			t.Errorf("ParseDimension(%q) = %v", s, err)
+		}
+	}
+	if _, err := ParseDimension("year"); err == nil {
+		t.Error("ParseDimension(year) = nil error, want unknown grouping")
+	}
+}
+
+func TestFilterRecords_boundsAreInclusiveAtTheirPrecision(t *testing.T) {
+	records := []Record{
+		rec("2026-04-30", "", "m", 1),
+		rec("2026-05-01", "", "m", 1),
+		rec("2026-05-31", "", "m", 1),
+		rec("2026-06-01", "", "m", 1),
+		rec("2026-05", "", "m", 1), // migrated month-precision row
+	}
+	cases := []struct {
+		since, until string
+		want         []string
+	}{
+		{"2026-05", "2026-05", []string{"2026-05-01", "2026-05-31", "2026-05"}},
+		{"2026-05-15", "", []string{"2026-05-31", "2026-06-01", "2026-05"}},
+		{"", "2026-04-30", []string{"2026-04-30"}},
+		{"", "", []string{"2026-04-30", "2026-05-01", "2026-05-31", "2026-06-01", "2026-05"}},
+	}
+	for _, c := range cases {
+		got := FilterRecords(records, c.since, c.until)
+		var days []string
+		for _, r := range got {
+			days = append(days, r.Day)
+		}
+		if strings.Join(days, ",") != strings.Join(c.want, ",") {
+			t.Errorf("FilterRecords(%q, %q) = %v, want %v", c.since, c.until, days, c.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ledgerEntry is one line of the account-rotation proxy's usage ledger
+// (proxy.LedgerEntry; written to <accounts dir>/proxy-usage.jsonl, and rotated
+// into <accounts dir>/proxy-usage/). Unlike transcripts it records which
+// account actually served the call, so it gives real per-account consumption
+// rather than an inference from config dirs.
+type ledgerEntry struct {
+	Time         time.Time `json:"ts"`
+	Account      string    `json:"account"`
+	Dir          string    `json:"dir"`
+	Model        string    `json:"model"`
+	ID           string    `json:"id"`
+	Input        int64     `json:"input"`
+	Output       int64     `json:"output"`
+	CacheWrite   int64     `json:"cache_write"`
+	CacheWrite1h int64     `json:"cache_write_1h"`
+	CacheRead    int64     `json:"cache_read"`
+}
+
+// ledgerCall is one call from the proxy ledger, kept apart from the others in
+// the cache so the calls a Claude transcript also counted can be left out when
+// the scan merges (see ledgerRecords).
+type ledgerCall struct {
+	ID string `json:"id,omitempty"`
+	Record
+}
+
+// ProxyLedgerPath returns the production ledger path under the wisp-deck
+// accounts dir (${XDG_CONFIG_HOME:-~/.config}/wisp-deck/claude-accounts).
+func ProxyLedgerPath(home string) string {
+	return filepath.Join(wispDeckConfigDir(home), "claude-accounts", "proxy-usage.jsonl")
+}
+
+// ProxyLedgerArchiveDir returns the dir the proxy rotates full ledgers into,
+// next to ProxyLedgerPath.
+func ProxyLedgerArchiveDir(home string) string {
+	return filepath.Join(wispDeckConfigDir(home), "claude-accounts", "proxy-usage")
+}
+
+// ParseProxyLedgerCalls reads a proxy usage ledger into one call per billed
+// message, each tagged with the account dir that served it (DefaultAccount when
+// the entry names none). Entries are deduped by message id; malformed lines
+// and entries without a timestamp are skipped, and an entry with no model is
+// attributed to "unknown". The proxy cannot see the working directory, so the
+// calls carry no project.
+func ParseProxyLedgerCalls(path string) ([]ledgerCall, FileMeta, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, FileMeta{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, FileMeta{}, err
+	}
+	meta := FileMeta{ModTime: info.ModTime(), Size: info.Size()}
+
+	var calls []ledgerCall
+	seen := map[string]bool{}
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	for sc.Scan() {
+		var e ledgerEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil || e.Time.IsZero() {
+			continue
+		}
+		if e.ID != "" {
+			if seen[e.ID] {
+				continue
+			}
+			seen[e.ID] = true
+		}
+		account := e.Dir
+		if account == "" {
+			account = DefaultAccount
+		}
+		model := e.Model
+		if model == "" {
+			model = "unknown"
+		}
+		calls = append(calls, ledgerCall{ID: e.ID, Record: Record{
+			Day:     e.Time.UTC().Format("2006-01-02"),
+			Account: account,
+			ModelUsage: ModelUsage{
+				Model:        model,
+				Input:        e.Input,
+				Output:       e.Output,
+				CacheWrite:   e.CacheWrite,
+				CacheWrite1h: e.CacheWrite1h,
+				CacheRead:    e.CacheRead,
+			},
+		}})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, meta, err
+	}
+	return calls, meta, nil
+}
+
+// parseProxyLedgerEntry is ParseProxyLedgerCalls as a parseFunc. The entry's
+// Records stay empty until the scan has seen every transcript (see
+// ledgerRecords).
+func parseProxyLedgerEntry(path string) (fileCacheEntry, error) {
+	calls, meta, err := ParseProxyLedgerCalls(path)
+	return fileCacheEntry{Meta: meta, Calls: calls}, err
+}
+
+// countedIDs returns the message ids the Claude transcripts in files have
+// counted, plus those of sealed transcripts kept in sealedIDs.
+func countedIDs(files map[string]fileCacheEntry, sealedIDs map[string]bool) map[string]bool {
+	counted := make(map[string]bool, len(sealedIDs))
+	for id := range sealedIDs {
+		counted[id] = true
+	}
+	for _, e := range files {
+		for _, id := range e.MessageIDs {
+			counted[id] = true
+		}
+	}
+	return counted
+}
+
+// ledgerRecords folds a ledger's calls into records, leaving out every call a
+// transcript already counted: the transcript knows the call's project, so the
+// ledger only adds the calls that reached no transcript (a client that keeps
+// none, or one pruned before a scan saw it). A call without an id cannot be
+// matched and is counted.
+func ledgerRecords(calls []ledgerCall, counted map[string]bool) []Record {
+	s := recordSet{}
+	for _, c := range calls {
+		if c.ID != "" && counted[c.ID] {
+			continue
+		}
+		s.add(c.Record)
+	}
+	return s.records()
+}
@@ -0,0 +1,97 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const ledgerFixture = `{"ts":"2026-05-01T10:00:00Z","account":"work","dir":"acct-1","model":"claude-opus-4-8","id":"msg_1","input":10,"output":20,"cache_write":5,"cache_write_1h":2,"cache_read":100}
+{"ts":"2026-05-01T10:00:00Z","account":"work","dir":"acct-1","model":"claude-opus-4-8","id":"msg_1","input":10,"output":20,"cache_write":5,"cache_write_1h":2,"cache_read":100}
+{"ts":"2026-05-02T09:00:00Z","account":"personal","dir":"acct-2","model":"claude-opus-4-8","id":"msg_2","input":1,"output":2,"cache_write":0,"cache_write_1h":0,"cache_read":3}
+not json
+{"ts":"2026-06-01T00:00:00Z","account":"work","dir":"acct-1","model":"","id":"msg_3","input":7,"output":0,"cache_write":0,"cache_write_1h":0,"cache_read":0}
+`
+
+func TestParseProxyLedgerCalls_tagsEachCallWithItsAccount(t *testing.T) {
+	p := writeFixture(t, t.TempDir(), "proxy-usage.jsonl", ledgerFixture)
+	calls, meta, err := ParseProxyLedgerCalls(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Size == 0 {
+		t.Error("meta.Size = 0, want non-zero")
+	}
+	// msg_1 appears twice but is counted once.
+	if len(calls) != 3 {
+		t.Fatalf("calls = %+v, want msg_1, msg_2 and msg_3", calls)
+	}
+	if c := calls[0]; c.ID != "msg_1" || c.Account != "acct-1" || c.Day != "2026-05-01" || c.CacheWrite1h != 2 || c.CacheRead != 100 {
+		t.Errorf("first call = %+v", c)
+	}
+	if c := calls[2]; c.Model != "unknown" || c.Day != "2026-06-01" {
+		t.Errorf("model-less call = %+v, want an unknown-model row", c)
+	}
+}
+
+func TestParseProxyLedgerCalls_missingFileErrors(t *testing.T) {
+	if _, _, err := ParseProxyLedgerCalls(filepath.Join(t.TempDir(), "nope.jsonl")); err == nil {
+		t.Error("expected an error for a missing ledger")
+	}
+}
+
+func TestProxyLedgerPath_honorsXDGConfigHome(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	if got := ProxyLedgerPath("/home/u"); got != "/xdg/wisp-deck/claude-accounts/proxy-usage.jsonl" {
+		t.Errorf("path = %q", got)
+	}
+	if got := ProxyLedgerArchiveDir("/home/u"); got != "/xdg/wisp-deck/claude-accounts/proxy-usage" {
+		t.Errorf("archive dir = %q", got)
+	}
+	t.Setenv("XDG_CONFIG_HOME", "")
+	if got := ProxyLedgerPath("/home/u"); !strings.HasPrefix(got, "/home/u/.config/") {
+		t.Errorf("path = %q, want under ~/.config", got)
+	}
+}
+
+// A proxied call is in both the transcript and the ledger: the scan counts it
+// once, from the transcript, and adds only what the ledger alone logged. Once
+// the transcript is pruned its calls stay counted once, from the archive.
+func TestScan_countsProxyLedgerCallsNoTranscriptHas(t *testing.T) {
+	root := t.TempDir()
+	proj := filepath.Join(root, "projects", "-src-app")
+	mkdirAll(t, proj)
+	transcript := writeFixture(t, proj, "s.jsonl",
+		`{"type":"assistant","timestamp":"2026-05-01T10:00:00Z","sessionId":"s","cwd":"/src/app","message":{"id":"msg_1","model":"claude-opus-4-8","usage":{"input_tokens":10,"output_tokens":20}}}`+"\n")
+	ledger := writeFixture(t, root, "proxy-usage.jsonl", ledgerFixture)
+	sources := []Source{
+		{Kind: SourceClaude, Root: filepath.Join(root, "projects"), Account: DefaultAccount},
+		{Kind: SourceProxy, Root: ledger},
+	}
+	cachePath := filepath.Join(root, "cache.json")
+
+	check := func(when string) {
+		t.Helper()
+		snap, err := Scan(sources, cachePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var total int64
+		byAccount := map[string]int64{}
+		for _, r := range snap.Records {
+			total += r.Input
+			byAccount[r.Account] += r.Input
+		}
+		// msg_1 from the transcript (10), msg_2 (1) and msg_3 (7) from the ledger.
+		if total != 18 || byAccount[DefaultAccount] != 10 || byAccount["acct-2"] != 1 || byAccount["acct-1"] != 7 {
+			t.Errorf("%s: input by account = %v, want 10 default, 1 acct-2, 7 acct-1", when, byAccount)
+		}
+	}
+	check("first scan")
+	check("cached scan")
+	if err := os.Remove(transcript); err != nil {
+		t.Fatal(err)
+	}
+	check("after the transcript is pruned")
+}
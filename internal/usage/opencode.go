@@ -19,6 +19,10 @@ type opencodeMessage struct {
 	Time    struct {
 		Created int64 `json:"created"`
 	} `json:"time"`
+	Path struct {
+		Cwd  string `json:"cwd"`
+		Root string `json:"root"`
+	} `json:"path"`
 	Tokens *struct {
 		Input     int64 `json:"input"`
 		Output    int64 `json:"output"`
@@ -35,28 +39,36 @@ type opencodeMessage struct {
 // below it, so the split is unambiguous for real session data.
 const millisThreshold = 1_000_000_000_000
 
-// monthFromEpoch converts an OpenCode time.created value to a YYYY-MM bucket.
+// timeFromEpoch converts an OpenCode time.created value to a UTC time.
 // OpenCode stores JS epoch milliseconds, but some older/custom providers have
 // been observed storing seconds, so sub-threshold values are read as seconds to
 // avoid bucketing them into 1970.
-func monthFromEpoch(v int64) string {
-	var t time.Time
+func timeFromEpoch(v int64) time.Time {
 	if v >= millisThreshold {
-		t = time.UnixMilli(v).UTC()
-	} else {
-		t = time.Unix(v, 0).UTC()
+		return time.UnixMilli(v).UTC()
 	}
-	return t.Format("2006-01")
+	return time.Unix(v, 0).UTC()
 }
 
 // ParseOpenCodeMessage reads a single OpenCode message JSON file and aggregates
-// its token usage by month and model, matching ParseFile's contract so the
-// Aggregate cache can treat both sources uniformly. Non-assistant messages,
-// messages without token usage or a timestamp, zero-token turns, and malformed
-// files are skipped (returning an empty map, not an error). A message with no
-// model id is attributed to "unknown". Reasoning tokens are folded into Output
-// since they are billed at the output rate.
+// its token usage by month and model, matching ParseFile's contract; it is
+// ParseOpenCodeRecords folded to months.
 func ParseOpenCodeMessage(path string) (map[string]*MonthlyUsage, FileMeta, error) {
+	records, meta, err := ParseOpenCodeRecords(path)
+	if err != nil {
+		return nil, meta, err
+	}
+	return monthsOf(records), meta, nil
+}
+
+// ParseOpenCodeRecords reads a single OpenCode message JSON file into records,
+// matching ParseFileRecords' contract so the Aggregate cache can treat both
+// sources uniformly. Non-assistant messages, messages without token usage or a
+// timestamp, zero-token turns, and malformed files are skipped (returning no
+// records, not an error). A message with no model id is attributed to
+// "unknown". Reasoning tokens are folded into Output since they are billed at
+// the output rate. The project is the session's root, else its cwd.
+func ParseOpenCodeRecords(path string) ([]Record, FileMeta, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, FileMeta{}, err
@@ -69,7 +81,7 @@ func ParseOpenCodeMessage(path string) (map[string]*MonthlyUsage, FileMeta, erro
 	}
 	meta := FileMeta{ModTime: info.ModTime(), Size: info.Size()}
 
-	empty := map[string]*MonthlyUsage{}
+	var empty []Record
 	data, err := io.ReadAll(io.LimitReader(f, maxLineBytes))
 	if err != nil {
 		return nil, meta, err
@@ -86,20 +98,18 @@ func ParseOpenCodeMessage(path string) (map[string]*MonthlyUsage, FileMeta, erro
 	if model == "" {
 		model = "unknown"
 	}
-	byModel := map[string]*ModelUsage{}
+	project := msg.Path.Root
+	if project == "" {
+		project = msg.Path.Cwd
+	}
+	acc := recordSet{}
 	// OpenCode does not split cache writes by TTL, so the whole cache.write total
 	// is charged at the 5-minute rate (CacheWrite1h stays 0) via the flat path.
-	addCounts(byModel, model, usageCounts{
+	acc.addCounts(timeFromEpoch(msg.Time.Created).Format("2006-01-02"), project, model, usageCounts{
 		Input:      msg.Tokens.Input,
 		Output:     msg.Tokens.Output + msg.Tokens.Reasoning,
 		CacheWrite: msg.Tokens.Cache.Write,
 		CacheRead:  msg.Tokens.Cache.Read,
 	})
-
-	month := monthFromEpoch(msg.Time.Created)
-	months := map[string]*MonthlyUsage{}
-	if mu := buildMonthly(month, byModel); mu != nil {
-		months[month] = mu
-	}
-	return months, meta, nil
+	return acc.records(), meta, nil
 }
@@ -2,6 +2,7 @@ package usage
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -101,14 +102,28 @@ func TestParseOpenCodeMessage_skipsMissingTimestamp(t *testing.T) {
 	}
 }
 
-func TestMonthFromEpoch_handlesMillisAndSeconds(t *testing.T) {
+func TestTimeFromEpoch_handlesMillisAndSeconds(t *testing.T) {
 	ts := time.Date(2026, 5, 15, 12, 0, 0, 0, time.UTC)
-	if got := monthFromEpoch(ts.UnixMilli()); got != "2026-05" {
-		t.Errorf("millis -> %q, want 2026-05", got)
+	if got := timeFromEpoch(ts.UnixMilli()); !got.Equal(ts) {
+		t.Errorf("millis -> %v, want %v", got, ts)
 	}
 	// Older/custom providers may store seconds; values below the millis threshold
 	// are interpreted as seconds rather than landing in 1970.
-	if got := monthFromEpoch(ts.Unix()); got != "2026-05" {
-		t.Errorf("seconds -> %q, want 2026-05", got)
+	if got := timeFromEpoch(ts.Unix()); !got.Equal(ts) {
+		t.Errorf("seconds -> %v, want %v", got, ts)
+	}
+}
+
+func TestParseOpenCodeRecords_projectFromSessionRoot(t *testing.T) {
+	dir := t.TempDir()
+	created := time.Date(2026, 5, 15, 12, 0, 0, 0, time.UTC).UnixMilli()
+	msg := strings.TrimSuffix(ocMsg("assistant", "claude-opus-4-8", created, 10, 0, 0, 0, 0), "}") +
+		`,"path":{"cwd":"/src/app/web","root":"/src/app"}}`
+	records, _, err := ParseOpenCodeRecords(writeFixture(t, dir, "msg_a.json", msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Day != "2026-05-15" || records[0].Project != "/src/app" {
+		t.Errorf("records = %+v, want one 2026-05-15 row for the session root", records)
 	}
 }
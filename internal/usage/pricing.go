@@ -6,103 +6,122 @@ import (
 	"github.com/jackuait/wisp-deck/internal/claudeconfig"
 )
 
-type modelRate struct{ inPerMTok, outPerMTok float64 }
+// modelRate is a built-in input/output price plus the model's cache pricing
+// as multiples of its input rate.
+type modelRate struct {
+	inPerMTok, outPerMTok float64
+	cache                 cacheRates
+}
+
+// cacheRates prices cache traffic relative to a model's input rate: creation
+// at the 5-minute and 1-hour TTLs, and reads.
+type cacheRates struct{ write, write1h, read float64 }
+
+// anthropicCache is Anthropic's cache pricing: writes at a premium over input,
+// reads at a tenth. It is also the fallback for a model whose provider's cache
+// pricing is not recorded.
+var anthropicCache = cacheRates{write: 1.25, write1h: 2.0, read: 0.10}
+
+// inputCache is the cache pricing of providers that bill cached prompt tokens
+// as plain input when they are written and discount them when read back.
+func inputCache(read float64) cacheRates { return cacheRates{write: 1, write1h: 1, read: read} }
 
 // modelRates holds published Anthropic prices per 1,000,000 tokens, matched by
 // model-id prefix so date-suffixed ids (e.g. claude-haiku-4-5-20251001) resolve.
 var modelRates = map[string]modelRate{
-	"claude-opus-4-5":   {5, 25},
-	"claude-opus-4-6":   {5, 25},
-	"claude-opus-4-7":   {5, 25},
-	"claude-opus-4-8":   {5, 25},
-	"claude-sonnet-4-5": {3, 15},
-	"claude-sonnet-4-6": {3, 15},
-	"claude-haiku-4-5":  {1, 5},
-	"claude-fable-5":    {10, 50},
-	"claude-mythos-5":   {10, 50},
+	"claude-opus-4-5":   {5, 25, anthropicCache},
+	"claude-opus-4-6":   {5, 25, anthropicCache},
+	"claude-opus-4-7":   {5, 25, anthropicCache},
+	"claude-opus-4-8":   {5, 25, anthropicCache},
+	"claude-sonnet-4-5": {3, 15, anthropicCache},
+	"claude-sonnet-4-6": {3, 15, anthropicCache},
+	"claude-haiku-4-5":  {1, 5, anthropicCache},
+	"claude-fable-5":    {10, 50, anthropicCache},
+	"claude-mythos-5":   {10, 50, anthropicCache},
 
 	// Bare aliases recorded by Claude Code in some transcripts. They carry real
 	// tokens, so they must be priced at the current-generation rate rather than
 	// dropped as $0. Listed after the full ids; the matched-prefix lookup never
 	// confuses them (a full id like "claude-opus-4-8" does not start with "opus").
-	"opus":   {5, 25},
-	"sonnet": {3, 15},
-	"haiku":  {1, 5},
+	"opus":   {5, 25, anthropicCache},
+	"sonnet": {3, 15, anthropicCache},
+	"haiku":  {1, 5, anthropicCache},
 
 	// GLM (Z.ai) and MiMo (Xiaomi) subscription-provider models are defined once in
 	// claudeconfig's catalog and folded into this map by init() below, so their ids
 	// and prices stay identical in the mapping UI, this cost calc, and the OpenCode
-	// mirror. mimo-v2.5 is a prefix of mimo-v2.5-pro; rateFor's longest-prefix match
+	// mirror. mimo-v2.5 is a prefix of mimo-v2.5-pro; PriceFor's longest-prefix match
 	// keeps them distinct.
 
 	// Models routed through OpenCode (and other tools), priced from models.dev —
 	// the catalog OpenCode itself uses — input/output USD per 1M tokens, sourced
-	// 2026-06-20. Only input/output are stored; cache reads/writes reuse the shared
-	// multipliers below (cache_read ≈ 0.1x input holds for these). Version siblings
-	// that differ in price get their own entry so longest-prefix resolves them; a
-	// not-yet-listed newer variant falls back to its base prefix (a close estimate).
+	// 2026-06-20. OpenAI, Gemini and DeepSeek bill no cache-write premium and
+	// discount cache reads by model, so their entries carry their own cache rates;
+	// the rest fall back to Anthropic's. Version siblings that differ in price get
+	// their own entry so longest-prefix resolves them; a not-yet-listed newer
+	// variant falls back to its base prefix (a close estimate).
 
 	// OpenAI
-	"gpt-5":        {1.25, 10},
-	"gpt-5-mini":   {0.25, 2},
-	"gpt-5-nano":   {0.05, 0.4},
-	"gpt-5.1":      {1.25, 10},
-	"gpt-5.2":      {1.75, 14},
-	"gpt-5.3":      {1.75, 14}, // covers gpt-5.3-codex / -spark / -chat
-	"gpt-5.4":      {2.5, 15},
-	"gpt-5.5":      {5, 30},
-	"gpt-4.1":      {2, 8},
-	"gpt-4.1-mini": {0.4, 1.6},
-	"gpt-4.1-nano": {0.1, 0.4},
-	"gpt-4o":       {2.5, 10},
-	"gpt-4o-mini":  {0.15, 0.6},
-	"o3":           {2, 8},
-	"o3-mini":      {1.1, 4.4},
-	"o4-mini":      {1.1, 4.4},
+	"gpt-5":        {1.25, 10, inputCache(0.1)},
+	"gpt-5-mini":   {0.25, 2, inputCache(0.1)},
+	"gpt-5-nano":   {0.05, 0.4, inputCache(0.1)},
+	"gpt-5.1":      {1.25, 10, inputCache(0.1)},
+	"gpt-5.2":      {1.75, 14, inputCache(0.1)},
+	"gpt-5.3":      {1.75, 14, inputCache(0.1)}, // covers gpt-5.3-codex / -spark / -chat
+	"gpt-5.4":      {2.5, 15, inputCache(0.1)},
+	"gpt-5.5":      {5, 30, inputCache(0.1)},
+	"gpt-4.1":      {2, 8, inputCache(0.25)},
+	"gpt-4.1-mini": {0.4, 1.6, inputCache(0.25)},
+	"gpt-4.1-nano": {0.1, 0.4, inputCache(0.25)},
+	"gpt-4o":       {2.5, 10, inputCache(0.5)},
+	"gpt-4o-mini":  {0.15, 0.6, inputCache(0.5)},
+	"o3":           {2, 8, inputCache(0.25)},
+	"o3-mini":      {1.1, 4.4, inputCache(0.5)},
+	"o4-mini":      {1.1, 4.4, inputCache(0.25)},
 
 	// Google Gemini
-	"gemini-2.5-pro":        {1.25, 10},
-	"gemini-2.5-flash":      {0.3, 2.5},
-	"gemini-2.5-flash-lite": {0.1, 0.4},
-	"gemini-2.0-flash":      {0.1, 0.4},
-	"gemini-3-pro":          {2, 12}, // -preview suffix
-	"gemini-3.1-pro":        {2, 12},
-	"gemini-3-flash":        {0.5, 3},
-	"gemini-3.5-flash":      {1.5, 9},
-	"gemini-3.1-flash-lite": {0.25, 1.5},
+	"gemini-2.5-pro":        {1.25, 10, inputCache(0.1)},
+	"gemini-2.5-flash":      {0.3, 2.5, inputCache(0.1)},
+	"gemini-2.5-flash-lite": {0.1, 0.4, inputCache(0.1)},
+	"gemini-2.0-flash":      {0.1, 0.4, inputCache(0.25)},
+	"gemini-3-pro":          {2, 12, inputCache(0.1)}, // -preview suffix
+	"gemini-3.1-pro":        {2, 12, inputCache(0.1)},
+	"gemini-3-flash":        {0.5, 3, inputCache(0.1)},
+	"gemini-3.5-flash":      {1.5, 9, inputCache(0.1)},
+	"gemini-3.1-flash-lite": {0.25, 1.5, inputCache(0.1)},
 
 	// xAI (grok-4 family currently all input 1.25 / output 2.5)
-	"grok-4": {1.25, 2.5},
+	"grok-4": {1.25, 2.5, anthropicCache},
 
 	// DeepSeek (input is the standard/cache-miss rate)
-	"deepseek-chat":     {0.14, 0.28},
-	"deepseek-reasoner": {0.14, 0.28},
-	"deepseek-v4-pro":   {0.435, 0.87},
-	"deepseek-v4-flash": {0.14, 0.28},
+	"deepseek-chat":     {0.14, 0.28, inputCache(0.1)},
+	"deepseek-reasoner": {0.14, 0.28, inputCache(0.1)},
+	"deepseek-v4-pro":   {0.435, 0.87, inputCache(0.1)},
+	"deepseek-v4-flash": {0.14, 0.28, inputCache(0.1)},
 
 	// Alibaba Qwen
-	"qwen3-coder-plus":  {1, 5},
-	"qwen3-coder-flash": {0.3, 1.5},
-	"qwen3-coder":       {1.5, 7.5}, // covers qwen3-coder-480b-a35b-instruct
-	"qwen3-max":         {1.2, 6},
-	"qwen-max":          {1.6, 6.4},
+	"qwen3-coder-plus":  {1, 5, anthropicCache},
+	"qwen3-coder-flash": {0.3, 1.5, anthropicCache},
+	"qwen3-coder":       {1.5, 7.5, anthropicCache}, // covers qwen3-coder-480b-a35b-instruct
+	"qwen3-max":         {1.2, 6, anthropicCache},
+	"qwen-max":          {1.6, 6.4, anthropicCache},
 
 	// Moonshot Kimi
-	"kimi-k2":                {0.6, 2.5},
-	"kimi-k2-turbo":          {2.4, 10},
-	"kimi-k2-thinking-turbo": {1.15, 8},
-	"kimi-k2.5":              {0.6, 3},
-	"kimi-k2.6":              {0.95, 4},
-	"kimi-k2.7-code":         {0.95, 4},
+	"kimi-k2":                {0.6, 2.5, anthropicCache},
+	"kimi-k2-turbo":          {2.4, 10, anthropicCache},
+	"kimi-k2-thinking-turbo": {1.15, 8, anthropicCache},
+	"kimi-k2.5":              {0.6, 3, anthropicCache},
+	"kimi-k2.6":              {0.95, 4, anthropicCache},
+	"kimi-k2.7-code":         {0.95, 4, anthropicCache},
 
 	// Z.ai GLM base model kept as a prefix fallback for historical usage; the
 	// offered glm-5/5.1/5.2/4.6/4.7/4.5-air come from the catalog (folded in init).
-	"glm-4.5": {0.6, 2.2},
+	"glm-4.5": {0.6, 2.2, anthropicCache},
 
 	// Mistral (coding models)
-	"codestral":       {0.3, 0.9},
-	"devstral-small":  {0.1, 0.3},
-	"devstral-medium": {0.4, 2},
+	"codestral":       {0.3, 0.9, anthropicCache},
+	"devstral-small":  {0.1, 0.3, anthropicCache},
+	"devstral-medium": {0.4, 2, anthropicCache},
 }
 
 // init folds the subscription-provider model prices from claudeconfig's catalog
@@ -110,48 +129,95 @@ var modelRates = map[string]modelRate{
 // place and can never drift between the catalog and the Stats cost calculator.
 func init() {
 	for _, m := range claudeconfig.CatalogModels() {
-		modelRates[m.ID] = modelRate{m.InPerM, m.OutPerM}
+		modelRates[m.ID] = modelRate{m.InPerM, m.OutPerM, anthropicCache}
 	}
 }
 
-const (
-	// cacheWriteMult prices 5-minute-TTL cache creation relative to the input rate.
-	cacheWriteMult = 1.25
-	// cacheWrite1hMult prices 1-hour-TTL cache creation relative to the input rate.
-	cacheWrite1hMult = 2.0
-	// cacheReadMult prices cache reads relative to the input rate.
-	cacheReadMult = 0.10
-)
+// Price is a model's resolved rate card in USD per 1,000,000 tokens, cache
+// traffic included, so cache pricing is per model rather than one set of
+// multipliers for every provider.
+type Price struct {
+	Input        float64 `json:"input"`
+	Output       float64 `json:"output"`
+	CacheWrite   float64 `json:"cache_write"`
+	CacheWrite1h float64 `json:"cache_write_1h"`
+	CacheRead    float64 `json:"cache_read"`
+}
+
+// builtinPrice derives a full rate card from a built-in rate and its cache
+// multipliers.
+func builtinPrice(r modelRate) Price {
+	return Price{
+		Input:        r.inPerMTok,
+		Output:       r.outPerMTok,
+		CacheWrite:   r.inPerMTok * r.cache.write,
+		CacheWrite1h: r.inPerMTok * r.cache.write1h,
+		CacheRead:    r.inPerMTok * r.cache.read,
+	}
+}
+
+// cacheFor returns the cache multipliers of model's provider, as recorded for
+// the built-in model sharing the longest prefix with it: its own entry when it
+// has one, otherwise a sibling of its family (the id up to its first "-"), so
+// an override for "gpt-6" prices its cache like "gpt-5". A model of no known
+// family gets anthropicCache. Ties go to the lexically last key, the newest
+// sibling, so the answer never depends on map order.
+func cacheFor(model string) cacheRates {
+	family, _, _ := strings.Cut(model, "-")
+	best, bestN, bestKey := anthropicCache, 0, ""
+	for key, r := range modelRates {
+		if keyFamily, _, _ := strings.Cut(key, "-"); keyFamily != family {
+			continue
+		}
+		n := 0
+		for n < len(key) && n < len(model) && key[n] == model[n] {
+			n++
+		}
+		if n > bestN || (n == bestN && key > bestKey) {
+			best, bestN, bestKey = r.cache, n, key
+		}
+	}
+	return best
+}
 
-// rateFor returns the rate whose key is the longest prefix of model. Longest-prefix
-// wins so overlapping ids resolve deterministically despite random map iteration
-// order (e.g. "mimo-v2.5-pro" must not match the shorter "mimo-v2.5").
-func rateFor(model string) (modelRate, bool) {
+// PriceFor returns the rate card whose key is the longest prefix of model,
+// searching the pricing overrides (see LoadPricing) and the built-in table
+// together. Longest-prefix wins so overlapping ids resolve deterministically
+// despite random map iteration order (e.g. "mimo-v2.5-pro" must not match the
+// shorter "mimo-v2.5"); on an exact tie the override wins.
+func PriceFor(model string) (Price, bool) {
 	var (
-		best  modelRate
+		best  Price
 		bestN = -1
 		found bool
 	)
 	for prefix, r := range modelRates {
 		if len(prefix) > bestN && strings.HasPrefix(model, prefix) {
-			best, bestN, found = r, len(prefix), true
+			best, bestN, found = builtinPrice(r), len(prefix), true
+		}
+	}
+	overridesMu.RLock()
+	defer overridesMu.RUnlock()
+	for prefix, o := range overrides {
+		if len(prefix) >= bestN && strings.HasPrefix(model, prefix) {
+			best, bestN, found = o.price(cacheFor(model)), len(prefix), true
 		}
 	}
 	return best, found
 }
 
-// RateFor returns the published input/output price per 1,000,000 tokens for a
-// model id, resolved by longest-prefix match, plus whether a rate was found.
+// RateFor returns the input/output price per 1,000,000 tokens for a model id,
+// resolved by longest-prefix match, plus whether a rate was found.
 // Exported so other packages (e.g. OpenCode config mirroring) can enrich model
 // metadata without duplicating this catalog.
 func RateFor(model string) (inPerMTok, outPerMTok float64, ok bool) {
-	r, found := rateFor(model)
-	return r.inPerMTok, r.outPerMTok, found
+	p, found := PriceFor(model)
+	return p.Input, p.Output, found
 }
 
 // ModelCostUSD returns the estimated USD cost for a model's usage and whether the
-// model had a pricing entry. Input and output use the model's rates; cache-write
-// is 1.25x the input rate and cache-read is 0.1x.
+// model had a pricing entry. Each token column is charged at the model's own
+// rate from PriceFor.
 func ModelCostUSD(m ModelUsage) (float64, bool) {
 	// A row with no tokens (e.g. Claude Code's "<synthetic>" model) costs nothing
 	// regardless of rate, so treat it as priced to avoid flagging a month as an
@@ -159,33 +225,37 @@ func ModelCostUSD(m ModelUsage) (float64, bool) {
 	if m.Total() == 0 {
 		return 0, true
 	}
-	r, ok := rateFor(m.Model)
+	p, ok := PriceFor(m.Model)
 	if !ok {
 		return 0, false
 	}
-	inRate := r.inPerMTok / 1_000_000
-	outRate := r.outPerMTok / 1_000_000
-	// CacheWrite is the total; CacheWrite1h is its 1-hour-TTL subset (2x), the rest
-	// is 5-minute-TTL (1.25x). Guard against a malformed subset exceeding the total.
+	// CacheWrite is the total; CacheWrite1h is its 1-hour-TTL subset, the rest is
+	// 5-minute-TTL. Guard against a malformed subset exceeding the total.
 	cw1h := m.CacheWrite1h
 	if cw1h > m.CacheWrite {
 		cw1h = m.CacheWrite
 	}
 	cw5m := m.CacheWrite - cw1h
-	usd := float64(m.Input)*inRate +
-		float64(m.Output)*outRate +
-		float64(cw5m)*cacheWriteMult*inRate +
-		float64(cw1h)*cacheWrite1hMult*inRate +
-		float64(m.CacheRead)*cacheReadMult*inRate
-	return usd, true
+	usd := float64(m.Input)*p.Input +
+		float64(m.Output)*p.Output +
+		float64(cw5m)*p.CacheWrite +
+		float64(cw1h)*p.CacheWrite1h +
+		float64(m.CacheRead)*p.CacheRead
+	return usd / 1_000_000, true
 }
 
 // CostUSD sums the cost of every priced model in the month. allPriced is false if
 // any model in the month lacked a pricing entry.
 func (mu MonthlyUsage) CostUSD() (float64, bool) {
+	return costOf(mu.Models)
+}
+
+// costOf sums the cost of every priced model. allPriced is false if any model
+// lacked a pricing entry.
+func costOf(models []ModelUsage) (float64, bool) {
 	var total float64
 	allPriced := true
-	for _, m := range mu.Models {
+	for _, m := range models {
 		usd, priced := ModelCostUSD(m)
 		if !priced {
 			allPriced = false
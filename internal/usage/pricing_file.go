@@ -0,0 +1,165 @@
+package usage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jackuait/wisp-deck/internal/util"
+)
+
+// PriceOverride is one entry of the pricing file, in USD per 1,000,000 tokens.
+// Input and output are required; a cache rate left out is derived from Input
+// with the model provider's multipliers (see cacheFor).
+type PriceOverride struct {
+	Input        float64  `json:"input"`
+	Output       float64  `json:"output"`
+	CacheRead    *float64 `json:"cache_read,omitempty"`
+	CacheWrite   *float64 `json:"cache_write,omitempty"`
+	CacheWrite1h *float64 `json:"cache_write_1h,omitempty"`
+}
+
+// price resolves the override into a full rate card, filling the cache rates
+// it leaves out with the multipliers in cache.
+func (o PriceOverride) price(cache cacheRates) Price {
+	p := builtinPrice(modelRate{o.Input, o.Output, cache})
+	if o.CacheRead != nil {
+		p.CacheRead = *o.CacheRead
+	}
+	if o.CacheWrite != nil {
+		p.CacheWrite = *o.CacheWrite
+	}
+	if o.CacheWrite1h != nil {
+		p.CacheWrite1h = *o.CacheWrite1h
+	}
+	return p
+}
+
+// PricingFile is the on-disk pricing override table. Keys are model-id
+// prefixes matched longest-first together with the built-in table, so a key
+// can price one dated release or a whole family.
+type PricingFile struct {
+	Models map[string]PriceOverride `json:"models"`
+}
+
+var (
+	overridesMu sync.RWMutex
+	overrides   map[string]PriceOverride
+)
+
+// PricingPath returns the pricing override file in Wisp Deck's config dir.
+func PricingPath(home string) string {
+	return filepath.Join(wispDeckConfigDir(home), "pricing.json")
+}
+
+// ReadPricing reads a pricing file. A missing file is an empty table, not an
+// error, since most installs have none.
+func ReadPricing(path string) (PricingFile, error) {
+	f := PricingFile{Models: map[string]PriceOverride{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return f, err
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return PricingFile{Models: map[string]PriceOverride{}}, fmt.Errorf("%s: %w", path, err)
+	}
+	if f.Models == nil {
+		f.Models = map[string]PriceOverride{}
+	}
+	for id, o := range f.Models {
+		if o.Input < 0 || o.Output < 0 {
+			return PricingFile{Models: map[string]PriceOverride{}}, fmt.Errorf("%s: %s has a negative rate", path, id)
+		}
+	}
+	return f, nil
+}
+
+// LoadPricing reads the pricing file at path and makes its entries take
+// effect over the built-in table for every later cost calculation. A missing
+// file clears the overrides; an unreadable or malformed one leaves them as
+// they were and returns the error.
+func LoadPricing(path string) error {
+	f, err := ReadPricing(path)
+	if err != nil {
+		return err
+	}
+	SetPricingOverrides(f.Models)
+	return nil
+}
+
+// SetPricingOverrides replaces the active pricing overrides.
+func SetPricingOverrides(models map[string]PriceOverride) {
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+	overrides = models
+}
+
+// Save writes the pricing file atomically (see util.WriteFileAtomic).
+func (f PricingFile) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.WriteFileAtomic(path, append(data, '\n'), 0o644)
+}
+
+// modelsDevProvider is the part of a models.dev api.json provider entry the
+// importer reads.
+type modelsDevProvider struct {
+	Models map[string]struct {
+		Cost *struct {
+			Input      float64  `json:"input"`
+			Output     float64  `json:"output"`
+			CacheRead  *float64 `json:"cache_read"`
+			CacheWrite *float64 `json:"cache_write"`
+		} `json:"cost"`
+	} `json:"models"`
+}
+
+// ImportModelsDev extracts price overrides from a models.dev api.json dump
+// (provider id → models → cost, USD per 1M tokens). Only the listed providers
+// are read, and when several list the same model id the earlier provider wins.
+// Models without a cost, free ones (an aggregator's $0 listing would hide a
+// real price) and namespaced ids like "anthropic/claude-…" that no transcript
+// records are skipped. models.dev has a single cache-write rate; it is used for
+// the 5-minute TTL and the 1-hour rate keeps the default multiplier. It
+// returns the overrides and the providers that were found in the dump.
+func ImportModelsDev(data []byte, providers []string) (map[string]PriceOverride, []string, error) {
+	var dump map[string]modelsDevProvider
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return nil, nil, fmt.Errorf("not a models.dev api.json: %w", err)
+	}
+	out := map[string]PriceOverride{}
+	var found []string
+	for _, pid := range providers {
+		p, ok := dump[pid]
+		if !ok {
+			continue
+		}
+		found = append(found, pid)
+		ids := make([]string, 0, len(p.Models))
+		for id := range p.Models {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			c := p.Models[id].Cost
+			if c == nil || (c.Input == 0 && c.Output == 0) || strings.Contains(id, "/") {
+				continue
+			}
+			if _, dup := out[id]; dup {
+				continue
+			}
+			out[id] = PriceOverride{Input: c.Input, Output: c.Output, CacheRead: c.CacheRead, CacheWrite: c.CacheWrite}
+		}
+	}
+	return out, found, nil
+}
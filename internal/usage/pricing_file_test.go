@@ -0,0 +1,128 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func f64(v float64) *float64 { return &v }
+
+func withOverrides(t *testing.T, models map[string]PriceOverride) {
+	t.Helper()
+	SetPricingOverrides(models)
+	t.Cleanup(func() { SetPricingOverrides(nil) })
+}
+
+func TestPriceFor_overridesMergeOverBuiltins(t *testing.T) {
+	withOverrides(t, map[string]PriceOverride{
+		"claude-opus-4-7":   {Input: 4, Output: 20},                      // same key: replaces
+		"claude-opus-4-7-x": {Input: 1, Output: 2},                       // longer prefix: wins for its ids
+		"claude-nova-6":     {Input: 7, Output: 35, CacheRead: f64(0.2)}, // new release
+	})
+	if p, _ := PriceFor("claude-opus-4-7-20260101"); p.Input != 4 || p.CacheWrite != 5 {
+		t.Errorf("opus-4-7 = %+v, want the override with derived cache rates", p)
+	}
+	if p, _ := PriceFor("claude-opus-4-7-x1"); p.Input != 1 {
+		t.Errorf("opus-4-7-x = %+v, want the longer override", p)
+	}
+	if p, _ := PriceFor("claude-sonnet-4-6"); p.Input != 3 {
+		t.Errorf("sonnet = %+v, want the built-in untouched", p)
+	}
+	usd, priced := ModelCostUSD(ModelUsage{Model: "claude-nova-6", Input: 1_000_000, CacheRead: 1_000_000})
+	if !priced || !approx(usd, 7.2) {
+		t.Errorf("nova = %v priced=%v, want 7.2 with its own cache-read rate", usd, priced)
+	}
+}
+
+// Cache rates an override leaves out follow its provider's multipliers, not
+// Anthropic's: OpenAI bills cache writes as input and reads at a tenth.
+func TestPriceFor_overrideCacheDefaultsFollowTheProvider(t *testing.T) {
+	withOverrides(t, map[string]PriceOverride{
+		"gpt-6":         {Input: 2, Output: 16},
+		"claude-nova-6": {Input: 4, Output: 20},
+		"zeta-1":        {Input: 1, Output: 2},
+	})
+	if p, _ := PriceFor("gpt-6-2026"); !approx(p.CacheWrite, 2) || !approx(p.CacheWrite1h, 2) || !approx(p.CacheRead, 0.2) {
+		t.Errorf("gpt-6 = %+v, want OpenAI's cache pricing", p)
+	}
+	if p, _ := PriceFor("claude-nova-6"); !approx(p.CacheWrite, 5) || !approx(p.CacheWrite1h, 8) || !approx(p.CacheRead, 0.4) {
+		t.Errorf("nova = %+v, want Anthropic's cache pricing", p)
+	}
+	if p, _ := PriceFor("zeta-1"); !approx(p.CacheWrite, 1.25) {
+		t.Errorf("zeta = %+v, want the Anthropic fallback for an unknown family", p)
+	}
+}
+
+func TestLoadPricing_missingClearsAndMalformedErrors(t *testing.T) {
+	dir := t.TempDir()
+	withOverrides(t, map[string]PriceOverride{"claude-nova-6": {Input: 1, Output: 1}})
+	if err := LoadPricing(filepath.Join(dir, "none.json")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := PriceFor("claude-nova-6"); ok {
+		t.Error("a missing pricing file should leave only the built-ins")
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(bad, []byte(`{"models":{"x":{"input":-1,"output":1}}}`), 0o644)
+	if err := LoadPricing(bad); err == nil {
+		t.Error("a negative rate should be rejected")
+	}
+	os.WriteFile(bad, []byte(`{`), 0o644)
+	if err := LoadPricing(bad); err == nil {
+		t.Error("malformed JSON should be rejected")
+	}
+}
+
+func TestPricingPath_honoursXDGConfigHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+	if got, want := PricingPath(home), filepath.Join(home, "xdg", "wisp-deck", "pricing.json"); got != want {
+		t.Errorf("PricingPath = %q, want %q", got, want)
+	}
+	t.Setenv("XDG_CONFIG_HOME", "")
+	if got, want := PricingPath(home), filepath.Join(home, ".config", "wisp-deck", "pricing.json"); got != want {
+		t.Errorf("PricingPath = %q, want %q", got, want)
+	}
+}
+
+func TestPricingFile_saveRoundTrip(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "pricing.json")
+	in := PricingFile{Models: map[string]PriceOverride{"m": {Input: 1, Output: 2, CacheWrite: f64(3)}}}
+	if err := in.Save(p); err != nil {
+		t.Fatal(err)
+	}
+	out, err := ReadPricing(p)
+	if err != nil || out.Models["m"].CacheWrite == nil || *out.Models["m"].CacheWrite != 3 || out.Models["m"].CacheRead != nil {
+		t.Errorf("round trip = %+v, %v", out, err)
+	}
+}
+
+func TestImportModelsDev_providerOrderAndSkips(t *testing.T) {
+	dump := []byte(`{
+		"anthropic": {"models": {
+			"claude-nova-6": {"cost": {"input": 7, "output": 35, "cache_read": 0.7, "cache_write": 8.75}},
+			"claude-free": {"cost": {"input": 0, "output": 0}},
+			"claude-nocost": {}
+		}},
+		"router": {"models": {
+			"claude-nova-6": {"cost": {"input": 9, "output": 40}},
+			"anthropic/claude-nova-6": {"cost": {"input": 9, "output": 40}},
+			"other-1": {"cost": {"input": 1, "output": 2}}
+		}}
+	}`)
+	got, found, err := ImportModelsDev(dump, []string{"anthropic", "missing", "router"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 2 || found[0] != "anthropic" || found[1] != "router" {
+		t.Errorf("found = %v", found)
+	}
+	if len(got) != 2 || got["claude-nova-6"].Input != 7 || *got["claude-nova-6"].CacheRead != 0.7 || got["other-1"].Output != 2 {
+		t.Errorf("imported = %+v, want nova from anthropic and other-1", got)
+	}
+	if _, _, err := ImportModelsDev([]byte(`[]`), nil); err == nil {
+		t.Error("a non-object dump should be rejected")
+	}
+}
@@ -42,6 +42,29 @@ func TestModelCostUSD_cacheMultipliers(t *testing.T) {
 	}
 }
 
+func TestModelCostUSD_providerCacheRates(t *testing.T) {
+	// OpenAI, Gemini and DeepSeek bill cache writes as input and discount reads
+	// per model; 1M of each at the model's input rate.
+	cases := []struct {
+		model       string
+		write, read float64
+	}{
+		{"gpt-5-codex", 1.25, 0.125},
+		{"gpt-4.1", 2, 0.5},
+		{"gpt-4o", 2.5, 1.25},
+		{"gemini-2.5-pro", 1.25, 0.125},
+		{"gemini-2.0-flash", 0.1, 0.025},
+		{"deepseek-chat", 0.14, 0.014},
+	}
+	for _, c := range cases {
+		write, _ := ModelCostUSD(ModelUsage{Model: c.model, CacheWrite: 1_000_000, CacheWrite1h: 400_000})
+		read, _ := ModelCostUSD(ModelUsage{Model: c.model, CacheRead: 1_000_000})
+		if !approx(write, c.write) || !approx(read, c.read) {
+			t.Errorf("%s cache write/read = %v/%v, want %v/%v", c.model, write, read, c.write, c.read)
+		}
+	}
+}
+
 func TestModelCostUSD_cacheTTLSplit(t *testing.T) {
 	// opus input $5/MTok. 1M cache-write total, 400k of it at the 1-hour TTL:
 	//   5m: 600k * 1.25 * $5/MTok = $3.75
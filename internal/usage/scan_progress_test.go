@@ -0,0 +1,103 @@
+package usage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writeCorpus writes files transcripts of lines billed calls each, spread over
+// a few projects and months, and returns their root.
+func writeCorpus(tb testing.TB, files, lines int) string {
+	tb.Helper()
+	root := filepath.Join(tb.TempDir(), "projects")
+	for f := 0; f < files; f++ {
+		dir := filepath.Join(root, fmt.Sprintf("-src-app%d", f%7))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			tb.Fatal(err)
+		}
+		var b strings.Builder
+		for l := 0; l < lines; l++ {
+			fmt.Fprintf(&b, `{"type":"user","timestamp":"2026-%02d-%02dT10:00:00Z","sessionId":"s%d","message":{"content":"%s"}}`+"\n",
+				1+f%12, 1+l%28, f, strings.Repeat("x", 200))
+			fmt.Fprintf(&b, `{"type":"assistant","timestamp":"2026-%02d-%02dT10:00:01Z","sessionId":"s%d","cwd":"/src/app%d","message":{"id":"m%d-%d","model":"claude-opus-4-%d","usage":{"input_tokens":%d,"output_tokens":%d,"cache_read_input_tokens":%d}}}`+"\n",
+				1+f%12, 1+l%28, f, f%7, f, l, 5+l%3, 10+l, 20+f, 1000+l)
+		}
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("s%d.jsonl", f)), []byte(b.String()), 0o644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestScanProgress_parsesInParallelDeterministically(t *testing.T) {
+	root := writeCorpus(t, 40, 20)
+
+	defer func(n int) { parseWorkers = n }(parseWorkers)
+	parseWorkers = 1
+	serial, err := Scan(Sources([]string{root}, ""), filepath.Join(t.TempDir(), "cache.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parseWorkers = 8
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+	var reports []Progress
+	parallel, err := ScanProgress(Sources([]string{root}, ""), cachePath, func(p Progress) { reports = append(reports, p) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(serial.Records, parallel.Records) || !reflect.DeepEqual(serial.Sessions, parallel.Sessions) {
+		t.Error("parallel scan differs from the serial one")
+	}
+
+	if len(reports) != 41 {
+		t.Fatalf("got %d progress reports, want one before the first file and one per file (41)", len(reports))
+	}
+	first, last := reports[0], reports[len(reports)-1]
+	if first.FilesDone != 0 || first.FilesTotal != 40 || first.BytesTotal == 0 {
+		t.Errorf("first report = %+v, want 0 of 40 files with the bytes to parse", first)
+	}
+	if last.FilesDone != 40 || last.BytesDone != last.BytesTotal || last.Fraction() != 1 {
+		t.Errorf("last report = %+v, want everything done", last)
+	}
+	for i := 1; i < len(reports); i++ {
+		if reports[i].BytesDone < reports[i-1].BytesDone {
+			t.Fatalf("progress went backwards: %+v after %+v", reports[i], reports[i-1])
+		}
+	}
+
+	// A warm scan parses nothing.
+	reports = nil
+	ScanProgress(Sources([]string{root}, ""), cachePath, func(p Progress) { reports = append(reports, p) })
+	if len(reports) != 1 || reports[0].FilesTotal != 0 || reports[0].Fraction() != 1 {
+		t.Errorf("warm scan reports = %+v, want a single 0 of 0", reports)
+	}
+}
+
+// BenchmarkScan_coldCache parses a synthetic corpus with no cache, serially
+// and with the default pool:
+//
+//	go test ./internal/usage -bench Scan_coldCache -run '^$'
+func BenchmarkScan_coldCache(b *testing.B) {
+	root := writeCorpus(b, 200, 400)
+	pools := []int{1}
+	if n := runtime.GOMAXPROCS(0); n > 1 {
+		pools = append(pools, n)
+	}
+	for _, workers := range pools {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			defer func(n int) { parseWorkers = n }(parseWorkers)
+			parseWorkers = workers
+			for i := 0; i < b.N; i++ {
+				if _, err := Scan(Sources([]string{root}, ""), filepath.Join(b.TempDir(), "cache.json")); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package usage
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SessionSpan identifies the Claude conversation a transcript belongs to: its
+// session id, the directory it was started in (where `claude --resume` must run
+// to find it), and the first and last timestamps seen in the file.
+type SessionSpan struct {
+	ID    string    `json:"id"`
+	Cwd   string    `json:"cwd"`
+	First time.Time `json:"first"`
+	Last  time.Time `json:"last"`
+}
+
+// observe widens the span to cover one transcript line. The first session id
+// and cwd seen win; later lines of a resumed or forked conversation may carry
+// others but the file still belongs to the conversation it was opened as.
+func (s *SessionSpan) observe(rec transcriptRecord) {
+	if s.ID == "" {
+		s.ID = rec.SessionID
+	}
+	if s.Cwd == "" {
+		s.Cwd = rec.Cwd
+	}
+	t, err := time.Parse(time.RFC3339Nano, rec.Timestamp)
+	if err != nil {
+		return
+	}
+	if s.First.IsZero() || t.Before(s.First) {
+		s.First = t
+	}
+	if t.After(s.Last) {
+		s.Last = t
+	}
+}
+
+// sessionIDFromPath recovers the session id of a transcript that names none:
+// <id>.jsonl for a conversation, or the <id>/subagents/ dir a subagent
+// transcript is nested under, so agent runs are charged to their parent.
+func sessionIDFromPath(path string) string {
+	dir := filepath.Dir(path)
+	if filepath.Base(dir) == "subagents" {
+		return filepath.Base(filepath.Dir(dir))
+	}
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// SessionUsage is the usage of one Claude conversation, summed across its
+// transcript and any subagent transcripts. Project is the directory the
+// conversation was started in, Path its main transcript ("" when only subagent
+// transcripts survive) and Account the login whose config dir holds it (see
+// AccountKey), which `claude --resume` must run under to find it. The flat
+// fields are the sum across Models; Models is sorted by Total() desc. Records
+// breaks the same usage down by day, project and model, so a view can tell
+// what the conversation used on one day of the several it may span.
+type SessionUsage struct {
+	ID         string       `json:"id"`
+	Project    string       `json:"project"`
+	Path       string       `json:"path,omitempty"`
+	Account    string       `json:"account,omitempty"`
+	First      time.Time    `json:"first"`
+	Last       time.Time    `json:"last"`
+	Input      int64        `json:"input"`
+	Output     int64        `json:"output"`
+	CacheWrite int64        `json:"cache_write"`
+	CacheRead  int64        `json:"cache_read"`
+	Models     []ModelUsage `json:"models"`
+	Records    []Record     `json:"records,omitempty"`
+}
+
+// Total returns the sum of all token columns.
+func (s SessionUsage) Total() int64 {
+	return s.Input + s.Output + s.CacheWrite + s.CacheRead
+}
+
+// CostUSD sums the cost of every priced model in the session. allPriced is
+// false if any model lacked a pricing entry.
+func (s SessionUsage) CostUSD() (float64, bool) {
+	return costOf(s.Models)
+}
+
+// Resumable reports whether the conversation can be reopened: its main
+// transcript is still on disk and its starting directory is known.
+func (s SessionUsage) Resumable() bool {
+	return s.Path != "" && s.Project != ""
+}
+
+// UnattributedRecords returns the part of records no conversation in sessions
+// accounts for: other tools' usage, sealed history and calls only the proxy
+// ledger logged. Rows a conversation fully accounts for are dropped, as are the
+// conversations' rows that records lacks, so records may be a filtered slice.
+func UnattributedRecords(records []Record, sessions []SessionUsage) []Record {
+	s := recordSet{}
+	for _, r := range records {
+		s.add(r)
+	}
+	for _, sess := range sessions {
+		for _, r := range sess.Records {
+			if a := s[recordKey{r.Day, r.Project, r.Account, r.Model}]; a != nil {
+				subUsage(&a.ModelUsage, r.ModelUsage)
+			}
+		}
+	}
+	return s.records()
+}
+
+// buildSessions folds the live Claude cache entries into one SessionUsage per
+// conversation, most expensive first (tie-break by total tokens, then id).
+// Sealed history has no transcript left to resume, so only live files count.
+func buildSessions(files map[string]fileCacheEntry) []SessionUsage {
+	type acc struct {
+		s       SessionUsage
+		models  map[string]*ModelUsage
+		records recordSet
+	}
+	byID := map[string]*acc{}
+	for path, e := range files {
+		span := e.Session
+		if span == nil || span.ID == "" {
+			continue
+		}
+		a := byID[span.ID]
+		if a == nil {
+			a = &acc{s: SessionUsage{ID: span.ID}, models: map[string]*ModelUsage{}, records: recordSet{}}
+			byID[span.ID] = a
+		}
+		// Records carry their source's Account; the main transcript's wins.
+		account := ""
+		if len(e.Records) > 0 {
+			account = e.Records[0].Account
+		}
+		if strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) == span.ID {
+			a.s.Path = path
+			a.s.Project = span.Cwd
+			a.s.Account = account
+		} else if a.s.Project == "" {
+			a.s.Project = span.Cwd
+		}
+		if a.s.Path == "" && a.s.Account == "" {
+			a.s.Account = account
+		}
+		if !span.First.IsZero() && (a.s.First.IsZero() || span.First.Before(a.s.First)) {
+			a.s.First = span.First
+		}
+		if span.Last.After(a.s.Last) {
+			a.s.Last = span.Last
+		}
+		for _, r := range e.Records {
+			m := a.models[r.Model]
+			if m == nil {
+				m = &ModelUsage{Model: r.Model}
+				a.models[r.Model] = m
+			}
+			addUsage(m, r.ModelUsage)
+			a.records.add(r)
+		}
+	}
+
+	out := make([]SessionUsage, 0, len(byID))
+	cost := map[string]float64{}
+	for id, a := range byID {
+		s := a.s
+		s.Models = sortedModels(a.models)
+		s.Records = a.records.records()
+		if len(s.Models) == 0 {
+			continue // no billed turns (e.g. a conversation opened and abandoned)
+		}
+		for _, m := range s.Models {
+			s.Input += m.Input
+			s.Output += m.Output
+			s.CacheWrite += m.CacheWrite
+			s.CacheRead += m.CacheRead
+		}
+		cost[id], _ = s.CostUSD()
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if ci, cj := cost[out[i].ID], cost[out[j].ID]; ci != cj {
+			return ci > cj
+		}
+		if ti, tj := out[i].Total(), out[j].Total(); ti != tj {
+			return ti > tj
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
@@ -0,0 +1,116 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScan_indexesSessionsWithTheirSubagents(t *testing.T) {
+	root := t.TempDir()
+	proj := filepath.Join(root, "projects", "-src-app")
+	mkdirAll(t, filepath.Join(proj, "big", "subagents"))
+	writeFixture(t, proj, "big.jsonl", `{"type":"user","timestamp":"2026-05-01T09:00:00Z","sessionId":"big","cwd":"/src/app"}
+{"type":"assistant","timestamp":"2026-05-01T09:01:00Z","sessionId":"big","cwd":"/src/app","message":{"id":"a","model":"claude-opus-4-7","usage":{"input_tokens":1000,"output_tokens":100}}}
+`)
+	writeFixture(t, filepath.Join(proj, "big", "subagents"), "agent-1.jsonl",
+		`{"type":"assistant","timestamp":"2026-05-01T11:30:00Z","sessionId":"big","cwd":"/src/app/sub","message":{"id":"b","model":"claude-opus-4-7","usage":{"output_tokens":900000}}}`+"\n")
+	writeFixture(t, proj, "small.jsonl",
+		`{"type":"assistant","timestamp":"2026-05-02T10:00:00Z","sessionId":"small","cwd":"/src/app","message":{"id":"c","model":"claude-opus-4-7","usage":{"input_tokens":5}}}`+"\n")
+
+	snap, err := Scan(Sources([]string{filepath.Join(root, "projects")}, filepath.Join(root, "none")), filepath.Join(root, "cache.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Sessions) != 2 {
+		t.Fatalf("sessions = %+v, want big and small", snap.Sessions)
+	}
+	big := snap.Sessions[0]
+	if big.ID != "big" || big.Output != 900100 || big.Input != 1000 {
+		t.Errorf("first session = %+v, want big with its subagent's output folded in", big)
+	}
+	if big.Project != "/src/app" || big.Path != filepath.Join(proj, "big.jsonl") || !big.Resumable() {
+		t.Errorf("big = project %q path %q, want the main transcript's cwd and path", big.Project, big.Path)
+	}
+	if !big.First.Equal(time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)) || !big.Last.Equal(time.Date(2026, 5, 1, 11, 30, 0, 0, time.UTC)) {
+		t.Errorf("big span = %v – %v", big.First, big.Last)
+	}
+	// Its records keep the subagent's cwd apart from the main transcript's.
+	byProject := map[string]int64{}
+	for _, r := range big.Records {
+		byProject[r.Day+" "+r.Project] += r.Total()
+	}
+	if len(byProject) != 2 || byProject["2026-05-01 /src/app"] != 1100 || byProject["2026-05-01 /src/app/sub"] != 900000 {
+		t.Errorf("big records by day and project = %v", byProject)
+	}
+
+	// A second scan served from the cache yields the same index.
+	again, _ := Scan(Sources([]string{filepath.Join(root, "projects")}, filepath.Join(root, "none")), filepath.Join(root, "cache.json"))
+	if len(again.Sessions) != 2 || again.Sessions[0].Path != big.Path || !again.Sessions[0].Last.Equal(big.Last) {
+		t.Errorf("cached sessions = %+v, want the same index", again.Sessions)
+	}
+}
+
+func TestScan_sessionWithoutIdFallsBackToFileName(t *testing.T) {
+	root := t.TempDir()
+	proj := filepath.Join(root, "projects", "-src-app")
+	mkdirAll(t, proj)
+	writeFixture(t, proj, "0b1c.jsonl",
+		`{"type":"assistant","timestamp":"2026-05-02T10:00:00Z","message":{"id":"c","model":"m","usage":{"input_tokens":5}}}`+"\n")
+	snap, _ := Scan(Sources([]string{filepath.Join(root, "projects")}, ""), filepath.Join(root, "cache.json"))
+	if len(snap.Sessions) != 1 || snap.Sessions[0].ID != "0b1c" || snap.Sessions[0].Project != "/src/app" {
+		t.Errorf("sessions = %+v, want id from the file name and project from its dir", snap.Sessions)
+	}
+}
+
+func TestScan_sessionCarriesItsAccount(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", "")
+	def := filepath.Join(home, ".claude", "projects", "-src-app")
+	work := filepath.Join(home, ".config", "wisp-deck", "claude-accounts", "work", "projects", "-src-app")
+	mkdirAll(t, def)
+	mkdirAll(t, work)
+	writeFixture(t, def, "mine.jsonl",
+		`{"type":"assistant","timestamp":"2026-05-01T09:00:00Z","sessionId":"mine","cwd":"/src/app","message":{"id":"a","model":"m","usage":{"input_tokens":5}}}`+"\n")
+	writeFixture(t, work, "theirs.jsonl",
+		`{"type":"assistant","timestamp":"2026-05-02T09:00:00Z","sessionId":"theirs","cwd":"/src/app","message":{"id":"b","model":"m","usage":{"input_tokens":5}}}`+"\n")
+
+	snap, err := Scan(Sources(ClaudeAccountProjectDirs(home), ""), filepath.Join(home, "cache.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, s := range snap.Sessions {
+		got[s.ID] = s.Account
+	}
+	if len(got) != 2 || got["mine"] != DefaultAccount || got["theirs"] != "work" {
+		t.Errorf("session accounts = %v, want mine on default and theirs on work", got)
+	}
+}
+
+func TestUnattributedRecords_leavesWhatNoConversationHolds(t *testing.T) {
+	rec := func(project, model string, in int64) Record {
+		return Record{Day: "2026-05-01", Project: project, ModelUsage: ModelUsage{Model: model, Input: in}}
+	}
+	records := []Record{rec("/src/app", "m", 100), rec("/src/app", "gpt-5", 40), rec("/src/web", "m", 7)}
+	sessions := []SessionUsage{
+		{ID: "a", Records: []Record{rec("/src/app", "m", 60)}},
+		{ID: "b", Records: []Record{rec("/src/app", "m", 40), rec("/src/lib", "m", 5)}},
+	}
+	got := UnattributedRecords(records, sessions)
+	if len(got) != 2 || got[0] != rec("/src/app", "gpt-5", 40) || got[1] != rec("/src/web", "m", 7) {
+		t.Errorf("unattributed = %+v, want app's gpt-5 row and web's row", got)
+	}
+}
+
+// A v6 cache has no session spans: its entries are kept but re-parsed.
+func TestLoadCache_migratesV6ForReparse(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "cache.json")
+	os.WriteFile(p, []byte(`{"version":6,"files":{"/a.jsonl":{"meta":{"mod_time":"2026-05-01T00:00:00Z","size":9},"records":[{"day":"2026-05-01","project":"/p","model":"m","input":3}]}},"archive":[],"sealed":{}}`), 0o644)
+	c := LoadCache(p)
+	e, ok := c.Files["/a.jsonl"]
+	if c.Version != cacheVersion || !ok || len(e.Records) != 1 || e.Meta.Size != 0 {
+		t.Errorf("migrated = %+v, want the records kept with a zero meta", c)
+	}
+}
@@ -0,0 +1,125 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackuait/wisp-deck/internal/models"
+)
+
+// Source kinds name the assistants a scan can read usage from. Each has an
+// entry in sourceKinds saying which files under a root are its logs and how to
+// parse one.
+const (
+	SourceClaude   = "claude"    // Claude Code .jsonl transcripts
+	SourceOpenCode = "opencode"  // OpenCode msg_*.json message files
+	SourceCodex    = "codex"     // Codex CLI rollout-*.jsonl session logs
+	SourceGemini   = "gemini"    // Gemini CLI chats/session-*.json chat logs
+	SourceAider    = "aider"     // Aider .aider.chat.history.md files
+	SourceAiderLog = "aider-log" // Aider --analytics-log .jsonl event logs
+	SourceProxy    = "proxy"     // the account proxy's usage ledger
+)
+
+// Source is one root a scan walks: every file under Root that Kind recognises
+// is parsed by Kind's parser and its records tagged with Account. Root may be a
+// single file. A missing Root is skipped, so a source for an assistant that is
+// not installed costs nothing. A proxy ledger source has no Account: each call
+// is tagged with the login that served it.
+type Source struct {
+	Kind    string
+	Root    string
+	Account string
+}
+
+// sourceKind is a registry entry: match picks a kind's files out of a walk and
+// parse reads one into its cache entry.
+type sourceKind struct {
+	match func(path string) bool
+	parse parseFunc
+}
+
+// sourceKinds is the parser registry Scan dispatches on. Adding an assistant
+// is a parser, an entry here and a Source in DefaultSources.
+var sourceKinds = map[string]sourceKind{
+	SourceClaude:   {match: hasSuffix(".jsonl"), parse: parseTranscript},
+	SourceOpenCode: {match: hasSuffix(".json"), parse: parseOpenCodeEntry},
+	SourceCodex:    {match: isCodexSession, parse: parseCodexEntry},
+	SourceGemini:   {match: isGeminiChat, parse: parseGeminiEntry},
+	SourceAider:    {match: hasSuffix(aiderHistoryFile), parse: parseAiderHistoryEntry},
+	SourceAiderLog: {match: hasSuffix(".jsonl"), parse: parseAiderLogEntry},
+	SourceProxy:    {match: hasSuffix(".jsonl"), parse: parseProxyLedgerEntry},
+}
+
+// hasSuffix matches the files whose path ends in suffix.
+func hasSuffix(suffix string) func(string) bool {
+	return func(path string) bool { return strings.HasSuffix(path, suffix) }
+}
+
+// recordsOnly adapts a records-only parser (every source but Claude's) to
+// parseFunc.
+func recordsOnly(parse func(path string) ([]Record, FileMeta, error)) parseFunc {
+	return func(path string) (fileCacheEntry, error) {
+		records, meta, err := parse(path)
+		return fileCacheEntry{Meta: meta, Records: records}, err
+	}
+}
+
+var (
+	parseOpenCodeEntry     = recordsOnly(ParseOpenCodeRecords)
+	parseCodexEntry        = recordsOnly(ParseCodexRecords)
+	parseGeminiEntry       = recordsOnly(ParseGeminiRecords)
+	parseAiderHistoryEntry = recordsOnly(ParseAiderHistoryRecords)
+	parseAiderLogEntry     = recordsOnly(ParseAiderLogRecords)
+)
+
+// Sources returns the sources for Claude transcript roots, each tagged with
+// its AccountKey, followed by the OpenCode message dir ("" for none).
+func Sources(claudeDirs []string, opencodeDir string) []Source {
+	out := make([]Source, 0, len(claudeDirs)+1)
+	for _, dir := range claudeDirs {
+		out = append(out, Source{Kind: SourceClaude, Root: dir, Account: AccountKey(dir)})
+	}
+	if opencodeDir != "" {
+		out = append(out, Source{Kind: SourceOpenCode, Root: opencodeDir, Account: OpenCodeAccount})
+	}
+	return out
+}
+
+// DefaultSources returns every source the Stats tab counts: each native
+// Claude account (see ClaudeAccountProjectDirs), the account proxy's ledger
+// and its rotated files (only the calls no transcript counted), OpenCode, the
+// Codex CLI's sessions dir (honoring CODEX_HOME), the Gemini CLI's
+// ~/.gemini/tmp and Aider.
+// Aider keeps no global log by default, so its usage comes from the analytics
+// log named by AIDER_ANALYTICS_LOG when set, and otherwise from the chat
+// history in the root of each project on the wisp-deck project list; reading
+// both would count every message twice.
+func DefaultSources(home string) []Source {
+	_, opencodeDir, _ := DefaultPaths(home)
+	out := Sources(ClaudeAccountProjectDirs(home), opencodeDir)
+	out = append(out,
+		Source{Kind: SourceProxy, Root: ProxyLedgerPath(home)},
+		Source{Kind: SourceProxy, Root: ProxyLedgerArchiveDir(home)},
+	)
+
+	codexHome := strings.TrimSpace(os.Getenv("CODEX_HOME"))
+	if codexHome == "" {
+		codexHome = filepath.Join(home, ".codex")
+	}
+	out = append(out,
+		Source{Kind: SourceCodex, Root: filepath.Join(codexHome, "sessions"), Account: CodexAccount},
+		Source{Kind: SourceGemini, Root: filepath.Join(home, ".gemini", "tmp"), Account: GeminiAccount},
+	)
+
+	if log := strings.TrimSpace(os.Getenv("AIDER_ANALYTICS_LOG")); log != "" {
+		return append(out, Source{Kind: SourceAiderLog, Root: log, Account: AiderAccount})
+	}
+	projects, _ := models.LoadProjects(filepath.Join(wispDeckConfigDir(home), "projects"))
+	for _, p := range projects {
+		if !p.Stale {
+			out = append(out, Source{Kind: SourceAider, Root: filepath.Join(p.Path, aiderHistoryFile), Account: AiderAccount})
+		}
+	}
+	return out
+}
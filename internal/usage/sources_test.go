@@ -0,0 +1,58 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScan_defaultSourcesCountEveryAssistant(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("OPENCODE_DATA_DIR", "")
+	t.Setenv("CODEX_HOME", "")
+	t.Setenv("AIDER_ANALYTICS_LOG", "")
+
+	claude := filepath.Join(home, ".claude", "projects", "-src-app")
+	codex := filepath.Join(home, ".codex", "sessions", "2026", "05", "01")
+	gemini := filepath.Join(home, ".gemini", "tmp", "0f3c", "chats")
+	repo := filepath.Join(home, "src", "repo")
+	cfg := filepath.Join(home, ".config", "wisp-deck")
+	for _, d := range []string{claude, codex, gemini, repo, cfg} {
+		mkdirAll(t, d)
+	}
+	writeFixture(t, claude, "a.jsonl", watchLine("a", 10))
+	writeFixture(t, codex, "rollout-2026-05-01T09-00-00-s1.jsonl", cxTokens("2026-05-01T09:00:05.000Z", 200, 0, 0))
+	writeFixture(t, codex, "history.jsonl", cxTokens("2026-05-01T09:00:05.000Z", 99999, 0, 0))
+	writeFixture(t, gemini, "session-2026-05-01T09-00-s1.json", geminiFixture)
+	writeFixture(t, repo, aiderHistoryFile, aiderHistoryFixture)
+	if err := os.WriteFile(filepath.Join(cfg, "projects"), []byte("repo:"+repo+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := Collect(DefaultSources(home), filepath.Join(home, "cache.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]int64{}
+	for _, g := range GroupRecords(records, ByAccount) {
+		got[g.Key] = g.Total()
+	}
+	want := map[string]int64{DefaultAccount: 10, CodexAccount: 200, GeminiAccount: 1077, AiderAccount: 14090}
+	if len(got) != len(want) {
+		t.Fatalf("by account = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("by account = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestScan_rejectsAnUnknownSourceKind(t *testing.T) {
+	_, err := Scan([]Source{{Kind: "nope", Root: t.TempDir()}}, filepath.Join(t.TempDir(), "cache.json"))
+	if err == nil {
+		t.Fatal("Scan with an unknown kind succeeded, want an error")
+	}
+}
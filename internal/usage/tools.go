@@ -0,0 +1,259 @@
+package usage
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// ToolRecord is one tool's calls on one day: the grain the parser and the
+// cache keep for tool analytics. Agent is the sub-agent type for a Task (or
+// Agent) spawn, "" for every other tool. Errors counts calls whose tool_result
+// came back with is_error. Tokens is the tool's share of the billed tokens of
+// the assistant turns that called it, each turn split evenly across its calls.
+type ToolRecord struct {
+	Day    string `json:"day"`
+	Tool   string `json:"tool"`
+	Agent  string `json:"agent,omitempty"`
+	Calls  int64  `json:"calls"`
+	Errors int64  `json:"errors"`
+	Tokens int64  `json:"tokens"`
+}
+
+// contentBlock is the part of a message content block tool analytics reads:
+// tool_use blocks in assistant turns, tool_result blocks in user turns.
+type contentBlock struct {
+	Type      string          `json:"type"`
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Input     json.RawMessage `json:"input"`
+	ToolUseID string          `json:"tool_use_id"`
+	IsError   bool            `json:"is_error"`
+}
+
+// contentBlocks decodes a message's content when it is a block array; plain
+// string content (most user prompts) has none.
+func contentBlocks(raw json.RawMessage) []contentBlock {
+	if len(raw) == 0 || raw[0] != '[' {
+		return nil
+	}
+	var blocks []contentBlock
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return nil
+	}
+	return blocks
+}
+
+// IsAgentTool reports whether tool spawns a sub-agent.
+func IsAgentTool(tool string) bool { return tool == "Task" || tool == "Agent" }
+
+// MCPServer returns the server an MCP tool ("mcp__<server>__<tool>") belongs
+// to; ok is false for built-in tools.
+func MCPServer(tool string) (server string, ok bool) {
+	rest, found := strings.CutPrefix(tool, "mcp__")
+	if !found {
+		return "", false
+	}
+	server, _, _ = strings.Cut(rest, "__")
+	return server, server != ""
+}
+
+// toolCall is one tool_use block seen in a transcript.
+type toolCall struct {
+	day, tool, agent, message string
+	failed                    bool
+}
+
+// toolSet collects the tool calls of one transcript. Claude Code writes each
+// content block of a turn on its own line under the same message id (and the
+// same usage), so calls are keyed by their tool_use id rather than deduped with
+// the message, and the turn's tokens are matched up once the file is read.
+type toolSet struct {
+	calls  map[string]*toolCall // by tool_use id
+	order  []string
+	tokens map[string]int64 // billed tokens by message id
+}
+
+// observe picks the tool_use and tool_result blocks out of one line.
+func (s *toolSet) observe(rec transcriptRecord) {
+	for _, b := range contentBlocks(rec.Message.Content) {
+		switch b.Type {
+		case "tool_use":
+			if b.ID == "" || s.calls[b.ID] != nil || len(rec.Timestamp) < 10 {
+				continue
+			}
+			c := &toolCall{day: rec.Timestamp[:10], tool: b.Name, message: rec.Message.ID}
+			if IsAgentTool(b.Name) {
+				var in struct {
+					SubagentType string `json:"subagent_type"`
+				}
+				json.Unmarshal(b.Input, &in)
+				c.agent = in.SubagentType
+				if c.agent == "" {
+					c.agent = "general-purpose"
+				}
+			}
+			if s.calls == nil {
+				s.calls = map[string]*toolCall{}
+			}
+			s.calls[b.ID] = c
+			s.order = append(s.order, b.ID)
+		case "tool_result":
+			if c := s.calls[b.ToolUseID]; c != nil && b.IsError {
+				c.failed = true
+			}
+		}
+	}
+}
+
+// bill records the tokens of one billed call for its turn's tools.
+func (s *toolSet) bill(rec *transcriptRecord, m ModelUsage) {
+	if rec.Message.ID == "" {
+		return
+	}
+	if s.tokens == nil {
+		s.tokens = map[string]int64{}
+	}
+	s.tokens[rec.Message.ID] += m.Total()
+}
+
+// records folds the calls into per-day, per-tool rows ordered by day, tool and
+// agent.
+func (s *toolSet) records() []ToolRecord {
+	perMessage := map[string]int64{}
+	for _, id := range s.order {
+		perMessage[s.calls[id].message]++
+	}
+	set := toolRecordSet{}
+	for _, id := range s.order {
+		c := s.calls[id]
+		r := ToolRecord{Day: c.day, Tool: c.tool, Agent: c.agent, Calls: 1}
+		if c.failed {
+			r.Errors = 1
+		}
+		if c.message != "" {
+			r.Tokens = s.tokens[c.message] / perMessage[c.message]
+		}
+		set.add(r)
+	}
+	return set.records()
+}
+
+// toolRecordKey identifies the row a ToolRecord folds into.
+type toolRecordKey struct{ day, tool, agent string }
+
+// toolRecordSet accumulates tool records, merging rows that share day, tool
+// and agent.
+type toolRecordSet map[toolRecordKey]*ToolRecord
+
+func (s toolRecordSet) add(r ToolRecord) {
+	k := toolRecordKey{r.Day, r.Tool, r.Agent}
+	a := s[k]
+	if a == nil {
+		a = &ToolRecord{Day: r.Day, Tool: r.Tool, Agent: r.Agent}
+		s[k] = a
+	}
+	a.Calls += r.Calls
+	a.Errors += r.Errors
+	a.Tokens += r.Tokens
+}
+
+func (s toolRecordSet) records() []ToolRecord {
+	out := make([]ToolRecord, 0, len(s))
+	for _, r := range s {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Tool != b.Tool {
+			return a.Tool < b.Tool
+		}
+		return a.Agent < b.Agent
+	})
+	return out
+}
+
+// mergeToolRecords folds any number of tool record lists into one.
+func mergeToolRecords(lists ...[]ToolRecord) []ToolRecord {
+	set := toolRecordSet{}
+	for _, l := range lists {
+		for _, r := range l {
+			set.add(r)
+		}
+	}
+	return set.records()
+}
+
+// ToolUsage is the calls of one tool, MCP server or sub-agent type.
+type ToolUsage struct {
+	Name   string `json:"name"`
+	Calls  int64  `json:"calls"`
+	Errors int64  `json:"errors"`
+	Tokens int64  `json:"tokens"`
+}
+
+// ErrorRate returns the share of calls that failed.
+func (t ToolUsage) ErrorRate() float64 {
+	if t.Calls == 0 {
+		return 0
+	}
+	return float64(t.Errors) / float64(t.Calls)
+}
+
+// ToolSummary is tool analytics folded over every day: each tool, each MCP
+// server (its tools summed), and each sub-agent type spawned, all sorted by
+// calls, most first. Total sums every tool.
+type ToolSummary struct {
+	Tools   []ToolUsage `json:"tools"`
+	Servers []ToolUsage `json:"servers"`
+	Agents  []ToolUsage `json:"agents"`
+	Total   ToolUsage   `json:"total"`
+}
+
+// SummarizeTools folds tool records into a ToolSummary.
+func SummarizeTools(records []ToolRecord) ToolSummary {
+	tools, servers, agents := map[string]*ToolUsage{}, map[string]*ToolUsage{}, map[string]*ToolUsage{}
+	add := func(m map[string]*ToolUsage, name string, r ToolRecord) {
+		a := m[name]
+		if a == nil {
+			a = &ToolUsage{Name: name}
+			m[name] = a
+		}
+		a.Calls += r.Calls
+		a.Errors += r.Errors
+		a.Tokens += r.Tokens
+	}
+	sum := ToolSummary{Total: ToolUsage{Name: "Total"}}
+	for _, r := range records {
+		add(tools, r.Tool, r)
+		if server, ok := MCPServer(r.Tool); ok {
+			add(servers, server, r)
+		}
+		if r.Agent != "" {
+			add(agents, r.Agent, r)
+		}
+		sum.Total.Calls += r.Calls
+		sum.Total.Errors += r.Errors
+		sum.Total.Tokens += r.Tokens
+	}
+	sum.Tools, sum.Servers, sum.Agents = sortedTools(tools), sortedTools(servers), sortedTools(agents)
+	return sum
+}
+
+// sortedTools returns the entries by calls desc (tie-break by name).
+func sortedTools(m map[string]*ToolUsage) []ToolUsage {
+	out := make([]ToolUsage, 0, len(m))
+	for _, t := range m {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Calls != out[j].Calls {
+			return out[i].Calls > out[j].Calls
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
@@ -0,0 +1,70 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// toolTranscript is one turn split across two lines (as Claude Code writes it)
+// calling Bash and an MCP tool that fails, then a turn spawning an Explore
+// sub-agent.
+const toolTranscript = `{"type":"assistant","timestamp":"2026-05-01T09:00:00Z","message":{"id":"a","model":"m","content":[{"type":"text","text":"hi"},{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"ls"}}],"usage":{"input_tokens":60,"output_tokens":40}}}
+{"type":"assistant","timestamp":"2026-05-01T09:00:00Z","message":{"id":"a","model":"m","content":[{"type":"tool_use","id":"t2","name":"mcp__github__create_issue","input":{}}],"usage":{"input_tokens":60,"output_tokens":40}}}
+{"type":"user","timestamp":"2026-05-01T09:00:05Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"},{"type":"tool_result","tool_use_id":"t2","is_error":true,"content":"denied"}]}}
+{"type":"user","timestamp":"2026-05-01T09:00:06Z","message":{"role":"user","content":"plain prompt"}}
+{"type":"assistant","timestamp":"2026-05-02T09:00:00Z","message":{"id":"b","model":"m","content":[{"type":"tool_use","id":"t3","name":"Task","input":{"subagent_type":"Explore","prompt":"look"}}],"usage":{"output_tokens":30}}}
+`
+
+func TestScan_extractsToolCalls(t *testing.T) {
+	root := t.TempDir()
+	proj := filepath.Join(root, "projects", "-src-app")
+	mkdirAll(t, proj)
+	p := writeFixture(t, proj, "s.jsonl", toolTranscript)
+	cachePath := filepath.Join(root, "cache.json")
+
+	snap, err := Scan(Sources([]string{filepath.Join(root, "projects")}, ""), cachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := SummarizeTools(snap.Tools)
+	wantTools := []ToolUsage{
+		{Name: "Bash", Calls: 1, Tokens: 50},
+		{Name: "Task", Calls: 1, Tokens: 30},
+		{Name: "mcp__github__create_issue", Calls: 1, Errors: 1, Tokens: 50},
+	}
+	if !reflect.DeepEqual(sum.Tools, wantTools) {
+		t.Errorf("tools = %+v, want %+v", sum.Tools, wantTools)
+	}
+	if len(sum.Servers) != 1 || sum.Servers[0] != (ToolUsage{Name: "github", Calls: 1, Errors: 1, Tokens: 50}) {
+		t.Errorf("servers = %+v, want github with its failed call", sum.Servers)
+	}
+	if len(sum.Agents) != 1 || sum.Agents[0].Name != "Explore" || sum.Agents[0].Calls != 1 {
+		t.Errorf("agents = %+v, want one Explore spawn", sum.Agents)
+	}
+	if sum.Total.Calls != 3 || sum.Total.Errors != 1 || sum.Total.Tokens != 130 {
+		t.Errorf("total = %+v", sum.Total)
+	}
+
+	// The tool calls outlive the transcript, like its usage does.
+	os.Remove(p)
+	again, _ := Scan(Sources([]string{filepath.Join(root, "projects")}, ""), cachePath)
+	if !reflect.DeepEqual(again.Tools, snap.Tools) {
+		t.Errorf("after pruning tools = %+v, want the sealed %+v", again.Tools, snap.Tools)
+	}
+}
+
+func TestMCPServer(t *testing.T) {
+	for tool, want := range map[string]string{
+		"mcp__github__create_issue":       "github",
+		"mcp__claude_ai_Linear__getIssue": "claude_ai_Linear",
+		"Bash":                            "",
+		"mcp__":                           "",
+	} {
+		got, ok := MCPServer(tool)
+		if got != want || ok != (want != "") {
+			t.Errorf("MCPServer(%q) = %q, %v; want %q", tool, got, ok, want)
+		}
+	}
+}
@@ -2,22 +2,30 @@ package usage
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
+	"io"
 	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 )
 
 // ModelUsage holds token counts for a single model within a month. CacheWrite is
 // the total cache-creation tokens; CacheWrite1h is the 1-hour-TTL subset of it
 // (priced at 2x input vs the 5-minute 1.25x), so it is NOT added again by Total.
+// CacheUnread (CacheUnread1h its 1-hour share) is the part of CacheWrite no
+// later call read back (see cacheLedger); it is likewise not counted again.
 type ModelUsage struct {
-	Model        string `json:"model"`
-	Input        int64  `json:"input"`
-	Output       int64  `json:"output"`
-	CacheWrite   int64  `json:"cache_write"`
-	CacheWrite1h int64  `json:"cache_write_1h"`
-	CacheRead    int64  `json:"cache_read"`
+	Model         string `json:"model"`
+	Input         int64  `json:"input"`
+	Output        int64  `json:"output"`
+	CacheWrite    int64  `json:"cache_write"`
+	CacheWrite1h  int64  `json:"cache_write_1h"`
+	CacheRead     int64  `json:"cache_read"`
+	CacheUnread   int64  `json:"cache_unread,omitempty"`
+	CacheUnread1h int64  `json:"cache_unread_1h,omitempty"`
 }
 
 // Total returns the sum of all token columns.
@@ -41,35 +49,183 @@ func (m MonthlyUsage) Total() int64 {
 	return m.Input + m.Output + m.CacheWrite + m.CacheRead
 }
 
+// Record is one model's token usage on one day in one project under one
+// account: the grain the parsers and the cache keep, from which every grouping
+// (day, ISO week, month, project, model, account) is folded. Day is the UTC
+// calendar day (YYYY-MM-DD), or just YYYY-MM for history migrated from a
+// month-only cache. Project is the working directory the session ran in; ""
+// when unknown. Account is the login's key (see AccountKey), set by Scan from
+// the root the file was found under; "" for history archived before accounts
+// were tracked.
+type Record struct {
+	Day     string `json:"day"`
+	Project string `json:"project,omitempty"`
+	Account string `json:"account,omitempty"`
+	ModelUsage
+}
+
+// Month returns the record's YYYY-MM bucket.
+func (r Record) Month() string { return r.Day[:min(len(r.Day), 7)] }
+
+// recordKey identifies the row a Record folds into.
+type recordKey struct{ day, project, account, model string }
+
+// recordSet accumulates records, merging rows that share day, project, account
+// and model.
+type recordSet map[recordKey]*Record
+
+// add folds r into its row.
+func (s recordSet) add(r Record) {
+	k := recordKey{r.Day, r.Project, r.Account, r.Model}
+	a := s[k]
+	if a == nil {
+		a = &Record{Day: r.Day, Project: r.Project, Account: r.Account, ModelUsage: ModelUsage{Model: r.Model}}
+		s[k] = a
+	}
+	addUsage(&a.ModelUsage, r.ModelUsage)
+}
+
+// addCounts folds one billed call's token counts into the row for day, project
+// and model.
+func (s recordSet) addCounts(day, project, model string, c usageCounts) {
+	s.add(Record{Day: day, Project: project, ModelUsage: c.modelUsage(model)})
+}
+
+// records returns the accumulated rows ordered by day, project, account and
+// model.
+// Rows with zero total tokens (e.g. "<synthetic>" placeholder records) are
+// dropped so they neither render nor flag a bucket as partially unpriced.
+func (s recordSet) records() []Record {
+	out := make([]Record, 0, len(s))
+	for _, r := range s {
+		if r.Total() > 0 {
+			out = append(out, *r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Project != b.Project {
+			return a.Project < b.Project
+		}
+		if a.Account != b.Account {
+			return a.Account < b.Account
+		}
+		return a.Model < b.Model
+	})
+	return out
+}
+
+// mergeRecords folds any number of record lists into one, merging rows that
+// share day, project, account and model.
+func mergeRecords(lists ...[]Record) []Record {
+	set := recordSet{}
+	for _, l := range lists {
+		for _, r := range l {
+			set.add(r)
+		}
+	}
+	return set.records()
+}
+
+// addUsage adds src's token columns to dst.
+func addUsage(dst *ModelUsage, src ModelUsage) {
+	dst.Input += src.Input
+	dst.Output += src.Output
+	dst.CacheWrite += src.CacheWrite
+	dst.CacheWrite1h += src.CacheWrite1h
+	dst.CacheRead += src.CacheRead
+	dst.CacheUnread += src.CacheUnread
+	dst.CacheUnread1h += src.CacheUnread1h
+}
+
+// subUsage takes src's token columns from dst.
+func subUsage(dst *ModelUsage, src ModelUsage) {
+	dst.Input -= src.Input
+	dst.Output -= src.Output
+	dst.CacheWrite -= src.CacheWrite
+	dst.CacheWrite1h -= src.CacheWrite1h
+	dst.CacheRead -= src.CacheRead
+	dst.CacheUnread -= src.CacheUnread
+	dst.CacheUnread1h -= src.CacheUnread1h
+}
+
+// sortedModels returns the models with any tokens sorted by Total() desc
+// (tie-break by model id).
+func sortedModels(models map[string]*ModelUsage) []ModelUsage {
+	var out []ModelUsage
+	for _, m := range models {
+		if m.Total() > 0 {
+			out = append(out, *m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if ti, tj := out[i].Total(), out[j].Total(); ti != tj {
+			return ti > tj
+		}
+		return out[i].Model < out[j].Model
+	})
+	return out
+}
+
 // buildMonthly assembles a MonthlyUsage from per-model accumulators: it sums the
 // flat fields and returns Models sorted by Total() desc (tie-break by model id).
 // Models with zero total tokens (e.g. "<synthetic>" placeholder records) are
 // dropped so they neither render nor flag the month as partially unpriced. Returns
 // nil when no model has any tokens.
 func buildMonthly(month string, models map[string]*ModelUsage) *MonthlyUsage {
-	mu := &MonthlyUsage{Month: month}
-	for _, m := range models {
-		if m.Total() == 0 {
-			continue
-		}
+	mu := &MonthlyUsage{Month: month, Models: sortedModels(models)}
+	if len(mu.Models) == 0 {
+		return nil
+	}
+	for _, m := range mu.Models {
 		mu.Input += m.Input
 		mu.Output += m.Output
 		mu.CacheWrite += m.CacheWrite
 		mu.CacheRead += m.CacheRead
-		mu.Models = append(mu.Models, *m)
 		// (CacheWrite1h is a subset of CacheWrite; tracked per-model for pricing,
 		// not summed into the month flat fields.)
 	}
-	if len(mu.Models) == 0 {
-		return nil
+	return mu
+}
+
+// monthsOf folds records into per-month usage keyed by YYYY-MM.
+func monthsOf(records []Record) map[string]*MonthlyUsage {
+	acc := map[string]map[string]*ModelUsage{}
+	for _, r := range records {
+		byModel := acc[r.Month()]
+		if byModel == nil {
+			byModel = map[string]*ModelUsage{}
+			acc[r.Month()] = byModel
+		}
+		a := byModel[r.Model]
+		if a == nil {
+			a = &ModelUsage{Model: r.Model}
+			byModel[r.Model] = a
+		}
+		addUsage(a, r.ModelUsage)
 	}
-	sort.Slice(mu.Models, func(i, j int) bool {
-		if ti, tj := mu.Models[i].Total(), mu.Models[j].Total(); ti != tj {
-			return ti > tj
+	months := make(map[string]*MonthlyUsage, len(acc))
+	for month, byModel := range acc {
+		if mu := buildMonthly(month, byModel); mu != nil {
+			months[month] = mu
 		}
-		return mu.Models[i].Model < mu.Models[j].Model
-	})
-	return mu
+	}
+	return months
+}
+
+// Months folds records into per-month usage sorted newest-first, the shape the
+// Stats tab renders.
+func Months(records []Record) []MonthlyUsage {
+	byMonth := monthsOf(records)
+	out := make([]MonthlyUsage, 0, len(byMonth))
+	for _, mu := range byMonth {
+		out = append(out, *mu)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Month > out[j].Month })
+	return out
 }
 
 // FileMeta captures the on-disk identity used for incremental caching.
@@ -99,10 +255,13 @@ type usageCounts struct {
 type transcriptRecord struct {
 	Type      string `json:"type"`
 	Timestamp string `json:"timestamp"`
+	Cwd       string `json:"cwd"`
+	SessionID string `json:"sessionId"`
 	Message   struct {
-		ID    string `json:"id"`
-		Model string `json:"model"`
-		Usage *struct {
+		ID      string          `json:"id"`
+		Model   string          `json:"model"`
+		Content json.RawMessage `json:"content"`
+		Usage   *struct {
 			usageCounts
 			// Iterations holds each separately billed API round within a single
 			// assistant turn (e.g. a model fallback: a refused fable-5 round followed
@@ -118,108 +277,259 @@ type transcriptRecord struct {
 	} `json:"message"`
 }
 
-// addCounts folds one billed call's token counts into the per-model accumulator
-// for a month, creating the accumulator on first use. The per-TTL breakdown is
-// preferred (authoritative); the flat CacheWrite is the fallback for older
-// transcripts that lack it, charged entirely at the 5m rate.
-func addCounts(byModel map[string]*ModelUsage, model string, c usageCounts) {
-	mu := byModel[model]
-	if mu == nil {
-		mu = &ModelUsage{Model: model}
-		byModel[model] = mu
+// ParseFile reads a single .jsonl transcript and aggregates token usage by month
+// and by model; it is ParseFileRecords folded to months.
+func ParseFile(path string) (map[string]*MonthlyUsage, FileMeta, error) {
+	records, meta, err := ParseFileRecords(path)
+	if err != nil {
+		return nil, meta, err
 	}
-	mu.Input += c.Input
-	mu.Output += c.Output
-	mu.CacheRead += c.CacheRead
-	if cc := c.CacheCreation; cc != nil {
-		mu.CacheWrite += cc.Ephemeral5m + cc.Ephemeral1h
-		mu.CacheWrite1h += cc.Ephemeral1h
-	} else {
-		mu.CacheWrite += c.CacheWrite
+	return monthsOf(records), meta, nil
+}
+
+// ParseFileRecords reads a single .jsonl transcript into per-day, per-project,
+// per-model records. Non-assistant records, records without usage, and malformed
+// lines are skipped. Assistant records are deduped by message.id within this
+// file. A record with no model id is attributed to "unknown". The project is the
+// record's cwd, falling back to the transcript's encoded project dir.
+func ParseFileRecords(path string) ([]Record, FileMeta, error) {
+	e, err := parseTranscript(path)
+	return e.Records, e.Meta, err
+}
+
+// parseTranscript is ParseFileRecords plus the transcript's session span (the
+// conversation it belongs to, where it started, and when it ran) and its tool
+// calls. Every line with a timestamp widens the span, not just billed ones.
+func parseTranscript(path string) (fileCacheEntry, error) {
+	t := newTranscriptTail(path)
+	meta, err := t.read()
+	if err != nil {
+		return fileCacheEntry{Meta: meta}, err
 	}
+	return t.entry(meta), nil
 }
 
-// ParseFile reads a single .jsonl transcript and aggregates token usage by month
-// and by model. Non-assistant records, records without usage, and malformed lines
-// are skipped. Assistant records are deduped by message.id within this file. A
-// record with no model id is attributed to "unknown".
-func ParseFile(path string) (map[string]*MonthlyUsage, FileMeta, error) {
-	f, err := os.Open(path)
+// transcriptTail is parseTranscript's state kept between reads, so a
+// transcript that has only grown is read on from the byte where the last read
+// stopped instead of from the start (see Watcher).
+type transcriptTail struct {
+	path     string
+	offset   int64
+	calls    callScanner
+	acc      recordSet
+	span     SessionSpan
+	tools    toolSet
+	ledger   cacheLedger
+	fallback string
+}
+
+func newTranscriptTail(path string) *transcriptTail {
+	t := &transcriptTail{path: path, acc: recordSet{}, ledger: cacheLedger{}}
+	t.calls = callScanner{
+		seen: map[string]bool{},
+		line: func(rec transcriptRecord) {
+			t.span.observe(rec)
+			t.tools.observe(rec)
+		},
+		call: func(rec *transcriptRecord, m ModelUsage) {
+			t.tools.bill(rec, m)
+			project := rec.Cwd
+			if project == "" {
+				if t.fallback == "" {
+					t.fallback = projectFromPath(t.path)
+				}
+				project = t.fallback
+			}
+			t.acc.add(Record{Day: rec.Timestamp[:10], Project: project, ModelUsage: m})
+			t.ledger.add(rec.Timestamp[:10], project, m)
+		},
+	}
+	return t
+}
+
+// read consumes the lines written since the last read and returns the file's
+// size and mtime as of this read.
+func (t *transcriptTail) read() (FileMeta, error) {
+	f, err := os.Open(t.path)
 	if err != nil {
-		return nil, FileMeta{}, err
+		return FileMeta{}, err
 	}
 	defer f.Close()
 
 	info, err := f.Stat()
 	if err != nil {
-		return nil, FileMeta{}, err
+		return FileMeta{}, err
 	}
 	meta := FileMeta{ModTime: info.ModTime(), Size: info.Size()}
+	if _, err := f.Seek(t.offset, io.SeekStart); err != nil {
+		return meta, err
+	}
+	n, err := t.calls.scan(f)
+	t.offset += n
+	return meta, err
+}
 
-	// month -> model -> accumulator
-	acc := map[string]map[string]*ModelUsage{}
-	seen := map[string]bool{}
+// grown reports whether the file still holds every byte already read, as an
+// append-only transcript does; a shorter file has been rewritten.
+func (t *transcriptTail) grown() bool {
+	info, err := os.Stat(t.path)
+	return err == nil && info.Size() >= t.offset
+}
+
+// entry returns everything read so far as a cache entry stamped with meta.
+func (t *transcriptTail) entry(meta FileMeta) fileCacheEntry {
+	records := t.acc.records()
+	if unread := t.ledger.unread(); len(unread) > 0 {
+		records = mergeRecords(records, unread)
+	}
+	span := t.span
+	if span.ID == "" {
+		span.ID = sessionIDFromPath(t.path)
+	}
+	if span.Cwd == "" {
+		span.Cwd = projectFromPath(t.path)
+	}
+	ids := make([]string, 0, len(t.calls.seen))
+	for id := range t.calls.seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return fileCacheEntry{Meta: meta, Records: records, Session: &span, Tools: t.tools.records(), MessageIDs: ids}
+}
+
+// modelUsage converts one billed call's counts into a ModelUsage row. The
+// per-TTL breakdown is preferred (authoritative); the flat CacheWrite is the
+// fallback for older transcripts that lack it, charged entirely at the 5m rate.
+func (c usageCounts) modelUsage(model string) ModelUsage {
+	m := ModelUsage{Model: model, Input: c.Input, Output: c.Output, CacheRead: c.CacheRead}
+	if cc := c.CacheCreation; cc != nil {
+		m.CacheWrite = cc.Ephemeral5m + cc.Ephemeral1h
+		m.CacheWrite1h = cc.Ephemeral1h
+	} else {
+		m.CacheWrite = c.CacheWrite
+	}
+	return m
+}
+
+// scanTranscript decodes a .jsonl transcript, handing every well-formed line to
+// line and every billed API call to call. Only assistant records with usage and
+// a timestamp are billed; they are deduped by message.id within the file. When
+// the turn has per-iteration usage, each iteration is a separately billed call
+// (possibly on a different model), so each is attributed to its own model and
+// the top-level totals (which mirror only the last one) are ignored. A call with
+// no model id is attributed to "unknown".
+func scanTranscript(r io.Reader, line func(rec transcriptRecord), call func(rec *transcriptRecord, m ModelUsage)) error {
+	s := callScanner{seen: map[string]bool{}, line: line, call: call}
+	_, err := s.scan(r)
+	return err
+}
 
-	sc := bufio.NewScanner(f)
+// callScanner is scanTranscript's state; seen carries the message.id dedup
+// across every scan of the same file.
+type callScanner struct {
+	seen map[string]bool
+	line func(rec transcriptRecord)
+	call func(rec *transcriptRecord, m ModelUsage)
+}
+
+// scan reads r's complete lines and returns how many bytes they took. A final
+// line with no newline counts only once it is well-formed JSON: one still being
+// written is left for the next scan.
+func (s *callScanner) scan(r io.Reader) (int64, error) {
+	var n int64
+	sc := bufio.NewScanner(r)
 	sc.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
-	for sc.Scan() {
-		line := sc.Bytes()
-		var rec transcriptRecord
-		if err := json.Unmarshal(line, &rec); err != nil {
-			continue
-		}
-		if rec.Type != "assistant" || rec.Message.Usage == nil {
-			continue
+	sc.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			n += int64(i + 1)
+			return i + 1, data[:i], nil
 		}
-		if len(rec.Timestamp) < 7 {
-			continue
+		if atEOF && len(data) > 0 && json.Valid(data) {
+			n += int64(len(data))
+			return len(data), data, nil
 		}
-		if id := rec.Message.ID; id != "" {
-			if seen[id] {
-				continue
-			}
-			seen[id] = true
+		return 0, nil, nil
+	})
+	for sc.Scan() {
+		s.feed(sc.Bytes())
+	}
+	return n, sc.Err()
+}
+
+// feed handles one transcript line.
+func (s *callScanner) feed(b []byte) {
+	var rec transcriptRecord
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return
+	}
+	s.line(rec)
+	if rec.Type != "assistant" || rec.Message.Usage == nil {
+		return
+	}
+	if len(rec.Timestamp) < 10 {
+		return
+	}
+	if id := rec.Message.ID; id != "" {
+		if s.seen[id] {
+			return
 		}
-		month := rec.Timestamp[:7]
-		byModel := acc[month]
-		if byModel == nil {
-			byModel = map[string]*ModelUsage{}
-			acc[month] = byModel
-		}
-		u := rec.Message.Usage
-		// When the turn has per-iteration usage, each iteration is a separately
-		// billed call (possibly on a different model), so attribute each to its own
-		// model and ignore the top-level totals (which mirror only the last one).
-		// Otherwise fall back to the single top-level usage.
-		if len(u.Iterations) > 0 {
-			for i := range u.Iterations {
-				it := &u.Iterations[i]
-				model := it.Model
-				if model == "" {
-					model = rec.Message.Model
-				}
-				if model == "" {
-					model = "unknown"
-				}
-				addCounts(byModel, model, it.usageCounts)
+		s.seen[id] = true
+	}
+	u := rec.Message.Usage
+	if len(u.Iterations) > 0 {
+		for i := range u.Iterations {
+			it := &u.Iterations[i]
+			model := it.Model
+			if model == "" {
+				model = rec.Message.Model
 			}
-		} else {
-			model := rec.Message.Model
 			if model == "" {
 				model = "unknown"
 			}
-			addCounts(byModel, model, u.usageCounts)
+			s.call(&rec, it.usageCounts.modelUsage(model))
+		}
+	} else {
+		model := rec.Message.Model
+		if model == "" {
+			model = "unknown"
 		}
+		s.call(&rec, u.usageCounts.modelUsage(model))
 	}
-	if err := sc.Err(); err != nil {
-		return nil, meta, err
+}
+
+// projectFromPath recovers the project of a transcript that carries no cwd from
+// its ~/.claude/projects/<encoded-cwd>/ directory (subagent transcripts sit
+// deeper in the same dir).
+func projectFromPath(path string) string {
+	parts := strings.Split(filepath.ToSlash(filepath.Dir(path)), "/")
+	for i := len(parts) - 2; i >= 0; i-- {
+		if parts[i] == "projects" {
+			return DecodeProjectDir(parts[i+1])
+		}
 	}
+	return DecodeProjectDir(parts[len(parts)-1])
+}
 
-	months := make(map[string]*MonthlyUsage, len(acc))
-	for month, byModel := range acc {
-		if mu := buildMonthly(month, byModel); mu != nil {
-			months[month] = mu
+// DecodeProjectDir turns Claude Code's encoded project dir name (the cwd with
+// every "/" and "." replaced by "-") back into a path. The encoding is lossy, so
+// each run of segments is matched against the filesystem, longest first, to keep
+// hyphenated directory names intact; segments that no longer exist decode with
+// "/". Names that are not encoded absolute paths are returned unchanged.
+func DecodeProjectDir(name string) string {
+	if !strings.HasPrefix(name, "-") {
+		return name
+	}
+	parts := strings.Split(name[1:], "-")
+	path := string(filepath.Separator)
+	for i := 0; i < len(parts); {
+		j := len(parts)
+		for ; j > i+1; j-- {
+			if _, err := os.Stat(filepath.Join(path, strings.Join(parts[i:j], "-"))); err == nil {
+				break
+			}
 		}
+		path = filepath.Join(path, strings.Join(parts[i:j], "-"))
+		i = j
 	}
-	return months, meta, nil
+	return path
 }
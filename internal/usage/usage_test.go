@@ -3,6 +3,7 @@ package usage
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -222,3 +223,54 @@ func TestParseFile_dropsAllZeroMonth(t *testing.T) {
 		t.Errorf("month with only zero-token usage should be dropped: %+v", months)
 	}
 }
+
+func TestParseFileRecords_dayAndProjectFromCwd(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFixture(t, dir, "s.jsonl", `{"type":"assistant","timestamp":"2026-05-01T23:59:00Z","cwd":"/src/app","message":{"id":"a","model":"m","usage":{"input_tokens":10}}}
+{"type":"assistant","timestamp":"2026-05-02T00:01:00Z","cwd":"/src/app","message":{"id":"b","model":"m","usage":{"input_tokens":5}}}
+{"type":"assistant","timestamp":"2026-05-02T09:00:00Z","cwd":"/src/app/sub","message":{"id":"c","model":"m","usage":{"input_tokens":1}}}
+`)
+	records, _, err := ParseFileRecords(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Record{
+		{Day: "2026-05-01", Project: "/src/app", ModelUsage: ModelUsage{Model: "m", Input: 10}},
+		{Day: "2026-05-02", Project: "/src/app", ModelUsage: ModelUsage{Model: "m", Input: 5}},
+		{Day: "2026-05-02", Project: "/src/app/sub", ModelUsage: ModelUsage{Model: "m", Input: 1}},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("records = %+v, want %+v", records, want)
+	}
+	for i := range want {
+		if records[i] != want[i] {
+			t.Errorf("records[%d] = %+v, want %+v", i, records[i], want[i])
+		}
+	}
+}
+
+// Without a cwd, the project comes from the encoded dir the transcript sits in,
+// also for subagent transcripts nested under the session.
+func TestParseFileRecords_projectFromEncodedDir(t *testing.T) {
+	root := t.TempDir()
+	real := filepath.Join(root, "code", "wisp-deck")
+	mkdirAll(t, real)
+	encoded := strings.NewReplacer("/", "-", ".", "-").Replace(real)
+	sub := filepath.Join(root, "projects", encoded, "sess", "subagents")
+	mkdirAll(t, sub)
+	p := writeFixture(t, sub, "agent-1.jsonl",
+		`{"type":"assistant","timestamp":"2026-05-01T10:00:00Z","message":{"id":"a","model":"m","usage":{"input_tokens":1}}}`+"\n")
+	records, _, _ := ParseFileRecords(p)
+	if len(records) != 1 || records[0].Project != real {
+		t.Errorf("records = %+v, want project %q (hyphenated dir kept)", records, real)
+	}
+}
+
+func TestDecodeProjectDir(t *testing.T) {
+	if got := DecodeProjectDir("-nonexistent-one-two"); got != "/nonexistent/one/two" {
+		t.Errorf("unknown path decodes to %q, want slashes", got)
+	}
+	if got := DecodeProjectDir("plain"); got != "plain" {
+		t.Errorf("non-encoded name = %q, want unchanged", got)
+	}
+}
@@ -0,0 +1,110 @@
+package usage
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jackuait/wisp-deck/internal/claudeaccount"
+)
+
+// AccountPlans returns the subscription recorded for each login in the plans
+// sidecar next to claude-accounts.list (see claudeaccount.PlansFile), keyed
+// like Record.Account; the Default login's plan is under DefaultAccount. A
+// missing or unreadable sidecar yields no plans.
+func AccountPlans(home string) map[string]claudeaccount.Plan {
+	list := filepath.Join(wispDeckConfigDir(home), "claude-accounts.list")
+	plans, err := claudeaccount.LoadPlans(claudeaccount.PlansFile(list))
+	if err != nil {
+		return map[string]claudeaccount.Plan{}
+	}
+	return plans
+}
+
+// PlanValue is one month of one subscribed account: APIUSD is what its usage
+// would have cost at API list rates (see ModelCostUSD), against the flat
+// Plan price. Priced is false when a model had no rate, so APIUSD leaves it
+// out and understates the value.
+type PlanValue struct {
+	Month   string
+	Account string
+	Plan    claudeaccount.Plan
+	Tokens  int64
+	APIUSD  float64
+	Priced  bool
+}
+
+// Ratio is the API-equivalent value per dollar of plan: at 1 the plan breaks
+// even, above it the plan is cheaper than paying per token. A free plan has
+// no ratio (0).
+func (v PlanValue) Ratio() float64 {
+	if v.Plan.MonthlyUSD <= 0 {
+		return 0
+	}
+	return v.APIUSD / v.Plan.MonthlyUSD
+}
+
+// NetUSD is the API-equivalent value less the plan price: what the plan saved
+// (positive) or what was paid for unused headroom (negative).
+func (v PlanValue) NetUSD() float64 { return v.APIUSD - v.Plan.MonthlyUSD }
+
+// PlanValues measures every account that has a plan against its usage, one
+// row per month from the account's first month of usage through the latest
+// month in records, so a paid month with no use shows as zero value. Rows run
+// newest month first, then by account key.
+func PlanValues(records []Record, plans map[string]claudeaccount.Plan) []PlanValue {
+	type key struct{ month, account string }
+	rows := map[key]*PlanValue{}
+	first := map[string]string{}
+	latest := ""
+	for _, r := range records {
+		month := r.Month()
+		latest = max(latest, month)
+		p, ok := plans[r.Account]
+		if !ok {
+			continue
+		}
+		if f, seen := first[r.Account]; !seen || month < f {
+			first[r.Account] = month
+		}
+		k := key{month, r.Account}
+		v := rows[k]
+		if v == nil {
+			v = &PlanValue{Month: month, Account: r.Account, Plan: p, Priced: true}
+			rows[k] = v
+		}
+		v.Tokens += r.Total()
+		usd, priced := ModelCostUSD(r.ModelUsage)
+		v.APIUSD += usd
+		v.Priced = v.Priced && priced
+	}
+	for account, month := range first {
+		for ; month <= latest; month = nextMonth(month) {
+			if k := (key{month, account}); rows[k] == nil {
+				rows[k] = &PlanValue{Month: month, Account: account, Plan: plans[account], Priced: true}
+			}
+		}
+	}
+
+	out := make([]PlanValue, 0, len(rows))
+	for _, v := range rows {
+		out = append(out, *v)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Month != out[j].Month {
+			return out[i].Month > out[j].Month
+		}
+		return out[i].Account < out[j].Account
+	})
+	return out
+}
+
+// nextMonth returns the YYYY-MM after month, or "~" (after every month) when
+// month does not parse, which ends any walk over months.
+func nextMonth(month string) string {
+	t, err := time.Parse("2006-01", month)
+	if err != nil {
+		return "~"
+	}
+	return t.AddDate(0, 1, 0).Format("2006-01")
+}
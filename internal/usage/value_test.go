@@ -0,0 +1,59 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackuait/wisp-deck/internal/claudeaccount"
+)
+
+func TestPlanValues_monthsOfEachSubscribedAccount(t *testing.T) {
+	sonnet := func(day, account string, in int64) Record {
+		return Record{Day: day, Account: account, ModelUsage: ModelUsage{Model: "claude-sonnet-4-5", Input: in}}
+	}
+	records := []Record{
+		sonnet("2026-03-10", "work", 10_000_000), // $30
+		sonnet("2026-05-02", "work", 50_000_000), // $150
+		sonnet("2026-05-03", "work", 20_000_000), // $60
+		sonnet("2026-05-03", DefaultAccount, 1_000_000),
+		{Day: "2026-05-04", Account: "work", ModelUsage: ModelUsage{Model: "mystery-1", Input: 5}},
+	}
+	plans := map[string]claudeaccount.Plan{"work": {Name: "max-20x", MonthlyUSD: 200}}
+
+	got := PlanValues(records, plans)
+	want := []struct {
+		month  string
+		usd    float64
+		priced bool
+	}{{"2026-05", 210, false}, {"2026-04", 0, true}, {"2026-03", 30, true}}
+	if len(got) != len(want) {
+		t.Fatalf("values = %+v, want %d months of work", got, len(want))
+	}
+	for i, w := range want {
+		v := got[i]
+		if v.Month != w.month || v.Account != "work" || v.APIUSD < w.usd-0.001 || v.APIUSD > w.usd+0.001 || v.Priced != w.priced {
+			t.Errorf("values[%d] = %+v, want %s $%.0f priced %v", i, v, w.month, w.usd, w.priced)
+		}
+	}
+	if r := got[0].Ratio(); r < 1.049 || r > 1.051 {
+		t.Errorf("May ratio = %v, want 1.05 (just past break-even)", r)
+	}
+	if n := got[2].NetUSD(); n > -169.99 || n < -170.01 {
+		t.Errorf("March net = %v, want -170", n)
+	}
+}
+
+func TestAccountPlans_readsTheSidecarNextToTheList(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", "")
+	dir := filepath.Join(home, ".config", "wisp-deck")
+	mkdirAll(t, dir)
+	if err := os.WriteFile(filepath.Join(dir, "claude-accounts.plans"), []byte("default:pro $20\nwork:max-5x $100\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	plans := AccountPlans(home)
+	if plans[DefaultAccount].Name != "pro" || plans["work"].MonthlyUSD != 100 {
+		t.Errorf("plans = %+v", plans)
+	}
+}
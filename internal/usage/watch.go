@@ -0,0 +1,81 @@
+package usage
+
+import "sync"
+
+// Watcher repeats a scan for a live view, keeping the cache in memory between
+// refreshes so each one only stats the files and re-reads those whose size or
+// mtime changed. A transcript that changes after the first refresh is read in
+// full once; from then on each refresh reads just the lines appended since.
+// Changed live files are cheap to re-read on the next launch, so the cache is
+// written only when it holds what the disk no longer does: after the first
+// refresh and whenever a vanished transcript is sealed. A Watcher is not safe
+// for concurrent refreshes.
+type Watcher struct {
+	sources   []Source
+	cachePath string
+
+	cache  *Cache
+	primed bool
+
+	mu    sync.Mutex
+	tails map[string]*transcriptTail // by path, for transcripts changed since the first refresh
+}
+
+// NewWatcher returns a Watcher over the same sources and cache as ScanProgress.
+func NewWatcher(sources []Source, cachePath string) *Watcher {
+	return &Watcher{sources: sources, cachePath: cachePath, tails: map[string]*transcriptTail{}}
+}
+
+// Refresh scans again, reporting to progress (which may be nil) like
+// ScanProgress.
+func (w *Watcher) Refresh(progress func(Progress)) (*Snapshot, error) {
+	if w.cache == nil {
+		w.cache = LoadCache(w.cachePath)
+	}
+	snap, next, sealed, err := scanCache(w.cache, w.sources, progress, w.parseTranscript)
+	if err != nil {
+		return nil, err
+	}
+	w.cache = next
+	if !w.primed || sealed {
+		_ = next.Save(w.cachePath) // best-effort, as in ScanProgress
+	}
+	w.primed = true
+
+	w.mu.Lock()
+	for path := range w.tails {
+		if _, live := next.Files[path]; !live {
+			delete(w.tails, path)
+		}
+	}
+	w.mu.Unlock()
+	return snap, nil
+}
+
+// parseTranscript reads path on from its tail when the file has only grown
+// since, and from the start otherwise. The first refresh keeps no tails: most
+// transcripts never change again, and their state would only cost memory.
+func (w *Watcher) parseTranscript(path string) (fileCacheEntry, error) {
+	if !w.primed {
+		return parseTranscript(path)
+	}
+	w.mu.Lock()
+	t := w.tails[path]
+	w.mu.Unlock()
+
+	if t == nil || !t.grown() {
+		t = newTranscriptTail(path)
+	}
+	meta, err := t.read()
+	w.mu.Lock()
+	if err != nil {
+		delete(w.tails, path)
+	} else {
+		w.tails[path] = t
+	}
+	w.mu.Unlock()
+	if err != nil {
+		return fileCacheEntry{Meta: meta}, err
+	}
+	return t.entry(meta), nil
+}
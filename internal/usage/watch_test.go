@@ -0,0 +1,92 @@
+package usage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func watchLine(id string, in int) string {
+	return fmt.Sprintf(`{"type":"assistant","timestamp":"2026-05-01T09:00:00Z","cwd":"/src/app","message":{"id":%q,"model":"m","usage":{"input_tokens":%d}}}`+"\n", id, in)
+}
+
+func appendFile(t *testing.T, path, s string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(s); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcher_readsOnlyWhatWasAppended(t *testing.T) {
+	root := t.TempDir()
+	proj := filepath.Join(root, "projects", "-src-app")
+	mkdirAll(t, proj)
+	p := writeFixture(t, proj, "s.jsonl", watchLine("a", 1))
+	cachePath := filepath.Join(root, "cache.json")
+	w := NewWatcher(Sources([]string{filepath.Join(root, "projects")}, ""), cachePath)
+
+	total := func() int64 {
+		t.Helper()
+		snap, err := w.Refresh(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return DayUsage(snap.Records, "2026-05-01").Total()
+	}
+	if got := total(); got != 1 {
+		t.Fatalf("first refresh = %d, want 1", got)
+	}
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Errorf("first refresh should save the cache: %v", err)
+	}
+
+	// A repeat of a seen message id and half a line: the duplicate is deduped
+	// and the partial line waits for the rest.
+	half := watchLine("c", 100)
+	appendFile(t, p, watchLine("b", 10)+watchLine("a", 1)+half[:20])
+	if got := total(); got != 11 {
+		t.Fatalf("after append = %d, want 11", got)
+	}
+	appendFile(t, p, half[20:])
+	if got := total(); got != 111 {
+		t.Fatalf("after finishing the line = %d, want 111", got)
+	}
+
+	// The tail resumes at its offset: bytes already read are not read again,
+	// so garbling them in place changes nothing.
+	data, _ := os.ReadFile(p)
+	first := len(watchLine("a", 1))
+	garbled := strings.Repeat("x", first-1) + "\n" + string(data[first:]) + watchLine("d", 1000)
+	os.WriteFile(p, []byte(garbled), 0o644)
+	if got := total(); got != 1111 {
+		t.Fatalf("after garbling read bytes = %d, want 1111", got)
+	}
+
+	// A rewritten (shorter) file is read again from the start.
+	os.WriteFile(p, []byte(watchLine("e", 5)), 0o644)
+	if got := total(); got != 5 {
+		t.Errorf("after rewrite = %d, want 5", got)
+	}
+}
+
+func TestDayUsage_leavesOutOtherDaysAndMonthRows(t *testing.T) {
+	records := []Record{
+		rec("2026-05-01", "/a", "m", 1),
+		rec("2026-05-01", "/b", "m", 10),
+		rec("2026-05-02", "/a", "m", 100),
+		rec("2026-05", "", "m", 1000),
+	}
+	if got := DayUsage(records, "2026-05-01"); got.Key != "2026-05-01" || got.Total() != 11 {
+		t.Errorf("DayUsage = %+v, want 11 tokens", got)
+	}
+	if got := DayUsage(records, "2026-05-03"); got.Key != "2026-05-03" || got.Total() != 0 {
+		t.Errorf("empty day = %+v", got)
+	}
+}
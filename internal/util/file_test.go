@@ -0,0 +1,28 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic_replacesAndLeavesNoTemp(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sub", "f.list")
+	for _, body := range []string{"one\n", "two\n"} {
+		if err := WriteFileAtomic(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "two\n" {
+		t.Fatalf("content = %q, %v; want the last write", data, err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("dir holds %d entries, want only the file (no temp left behind)", len(entries))
+	}
+}
@@ -32,6 +32,21 @@ set_auto_switch() {
   fi
 }
 
+# get_rotation_policy <policy_file> — prints the proxy's rotation policy
+# (drain, round-robin, least-utilized or priority; default drain, and any other
+# value reads as drain). Passed to `wisp-deck-tui proxy --policy`.
+get_rotation_policy() {
+  local file="$1" val=""
+  if [ -f "$file" ]; then
+    IFS= read -r val < "$file" || true
+    val="${val//[[:space:]]/}"
+  fi
+  case "$val" in
+    drain|round-robin|least-utilized|priority) echo "$val" ;;
+    *) echo "drain" ;;
+  esac
+}
+
 # proxy_startup_port <json_line> — extracts the "port" number from the proxy's
 # startup JSON ({"port":N,"key":"..."}), or empty if not present.
 proxy_startup_port() {
@@ -56,8 +71,9 @@ is_auto_switch_enabled() {
 }
 
 # auto_switch_eligible <accounts_list_file> — exit 0 when the account list holds
-# at least two accounts (label:dir lines, skipping comments/blanks), i.e. there
-# is something to rotate between.
+# at least one account (label:dir lines, skipping comments/blanks). A pool of
+# one is worth proxying: the proxy picks up logins added later without a
+# restart, so the session can rotate as soon as a second one exists.
 auto_switch_eligible() {
   local file="$1" count=0 line
   [ -f "$file" ] || return 1
@@ -67,5 +83,21 @@ auto_switch_eligible() {
     [[ "$line" != *:* ]] && continue
     count=$((count + 1))
   done < "$file"
-  [ "$count" -ge 2 ]
+  [ "$count" -ge 1 ]
+}
+
+# attach_rotation_proxy <cfg_root> <client_pid> — attaches the session (its
+# wrapper pid) to the per-user shared rotation proxy, starting the daemon when
+# none is running, and prints the proxy's startup JSON line (empty on failure).
+# The daemon is shared by every session and exits by itself once none of the
+# attached pids is alive, so callers never stop it. Errors go to the daemon log.
+attach_rotation_proxy() {
+  local cfg_root="$1" client_pid="$2"
+  wisp-deck-tui proxy attach \
+    --accounts-dir "$cfg_root/claude-accounts" \
+    --list "$cfg_root/claude-accounts.list" \
+    --policy "$(get_rotation_policy "$cfg_root/rotation-policy")" \
+    --state-dir "$cfg_root" \
+    --client-pid "$client_pid" \
+    2>> "$cfg_root/proxy-daemon.log" | sed -n '1p'
 }
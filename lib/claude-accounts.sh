@@ -68,6 +68,16 @@ resolve_claude_account_dir() {
   [ -d "$path" ] && printf '%s\n' "$path"
 }
 
+# claude_account_key_dir <accounts_dir> <account_key> — abs path of the login a
+# usage account key names (see usage.AccountKey) iff its directory exists. The
+# "default" key resolves to empty, like the Default login's pointer.
+claude_account_key_dir() {
+  local accounts_dir="$1" key="$2"
+  [ -z "$key" ] || [ "$key" = "default" ] && return 0
+  local path="$accounts_dir/$key"
+  [ -d "$path" ] && printf '%s\n' "$path"
+}
+
 # apply_plain_terminal_claude_account <accounts_dir> <pointer_file> — exports
 # CLAUDE_CONFIG_DIR for the active account so `claude` launched in a plain Ghostty
 # shell (the "plain terminal" menu action) loads the login the user currently has
@@ -106,7 +106,7 @@ config_menu_interactive() {
         else
           set_auto_switch "$auto_switch_flag" "on"
           if ! auto_switch_eligible "$cfg_root/claude-accounts.list"; then
-            warn "Add a second Claude account for rotation to take effect."
+            warn "Add a Claude account for rotation to take effect."
             read -rsn1 -p "Press any key to continue..." </dev/tty
           fi
         fi
@@ -4,7 +4,8 @@
 
 # Interactive project selection using wisp-deck-tui main-menu
 # Returns 0 if an actionable item was selected, 1 if quit/cancelled
-# Sets: _selected_project_name, _selected_project_path, _selected_project_action, _selected_ai_tool
+# Sets: _selected_project_name, _selected_project_path, _selected_project_action, _selected_ai_tool,
+#       _selected_session_id, _selected_session_account (resume-session only)
 select_project_interactive() {
   local projects_file="$1"
 
@@ -72,6 +73,7 @@ select_project_interactive() {
   cmd_args+=("--claude-accounts-dir" "$gt_config_dir/claude-accounts")
   cmd_args+=("--claude-default-label-file" "$gt_config_dir/claude-account-default-label")
   cmd_args+=("--auto-switch-file" "$gt_config_dir/auto-switch-accounts")
+  cmd_args+=("--rotation-policy-file" "$gt_config_dir/rotation-policy")
   if [ -n "${_update_version:-}" ]; then
     cmd_args+=("--update-version" "$_update_version")
   fi
@@ -106,8 +108,10 @@ select_project_interactive() {
 
   _selected_project_action="$action"
 
+  _selected_session_id=""
+  _selected_session_account=""
   case "$action" in
-    select-project|open-once)
+    select-project|open-once|resume-session)
       local name path
       name=$(echo "$result" | jq -r '.name' 2>/dev/null)
       path=$(echo "$result" | jq -r '.path' 2>/dev/null)
@@ -123,6 +127,14 @@ select_project_interactive() {
 
       _selected_project_name="$name"
       _selected_project_path="$path"
+      if [[ "$action" == "resume-session" ]]; then
+        _selected_session_id=$(echo "$result" | jq -r '.session // ""' 2>/dev/null)
+        if [[ -z "$_selected_session_id" ]]; then
+          error "TUI returned no session to resume"
+          return 1
+        fi
+        _selected_session_account=$(echo "$result" | jq -r '.account // ""' 2>/dev/null)
+      fi
       return 0
       ;;
     quit)
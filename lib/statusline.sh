@@ -129,3 +129,21 @@ gt_stamp_claude_session() {
   [ -n "$sid" ] || return 0
   tmux set-environment WISP_DECK_CLAUDE_SESSION "$sid" 2>/dev/null || true
 }
+
+# Echo the budget alert for the statusline: the text `wisp-deck-tui budget
+# check` last recorded in the budget-alert state file (one line: month,
+# threshold, text) behind a cash glyph, yellow at 50/80% and red at 100%.
+# Echoes nothing when no alert is recorded for the current month, so the caller
+# omits the segment. Reading the state file keeps the statusline from
+# rescanning usage on every refresh.
+# Usage: gt_budget_segment [state_file]
+gt_budget_segment() {
+  local state="${1:-${XDG_CONFIG_HOME:-$HOME/.config}/wisp-deck/budget-alert}"
+  [ -f "$state" ] || return 0
+  local month threshold text
+  read -r month threshold text < "$state" || true
+  [ "$month" = "$(date -u +%Y-%m)" ] && [ -n "$text" ] || return 0
+  local color="01;33"
+  [ "$threshold" -ge 100 ] 2>/dev/null && color="01;31"
+  printf '\033[%sm󰄔 %s\033[00m' "$color" "$text"
+}
@@ -252,6 +252,23 @@ apply_tab_title() {
   esac
 }
 
+# Evaluate the monthly budget (the "budget" settings key) and, when spend has
+# newly crossed 50/80/100% of a cap, announce it through the same path as "AI is
+# waiting": the notification sound, plus a tmux message carrying the alert text.
+# `wisp-deck-tui budget check` records each crossing in the budget-alert state
+# file, so with several sessions open only the first watcher to see it sounds.
+# Usage: check_budget_alert <config_dir> <ai_tool> <tmux_cmd> <session_name>
+check_budget_alert() {
+  local config_dir="$1" ai_tool="$2" tmux_cmd="$3" session_name="$4"
+  [ -n "$(read_settings_value "$config_dir/settings" budget)" ] || return 0
+  command -v wisp-deck-tui >/dev/null 2>&1 || return 0
+  local alert
+  alert="$(wisp-deck-tui budget check 2>/dev/null)" || return 0
+  [ -n "$alert" ] || return 0
+  play_notification_sound "$ai_tool" "$config_dir"
+  "$tmux_cmd" display-message -t "$session_name" "Wisp Deck: $alert" 2>/dev/null || true
+}
+
 # Discover the AI tool pane (rightmost pane in the tmux session).
 # Usage: discover_ai_pane <session_name> <tmux_cmd>
 # Outputs the pane index of the rightmost pane.
@@ -292,9 +309,22 @@ start_tab_title_watcher() {
     local last_accent=""
 
     local was_waiting=false
+    # The budget is checked once a minute (every 120th tick), starting with the
+    # first tick, in the background so a usage scan never stalls the poll. A
+    # check still running (a cold scan can outlast the minute) skips the next
+    # rather than stacking another full scan on top of it.
+    local budget_tick=0 budget_pid=""
     while true; do
       sleep 0.5
 
+      if [ -n "$config_dir" ]; then
+        if [ "$budget_tick" -eq 0 ] && ! { [ -n "$budget_pid" ] && kill -0 "$budget_pid" 2>/dev/null; }; then
+          check_budget_alert "$config_dir" "$ai_tool" "$tmux_cmd" "$session_name" &
+          budget_pid=$!
+        fi
+        budget_tick=$(( (budget_tick + 1) % 120 ))
+      fi
+
       # Live settings re-read: pick up tab-title mode + theme changes mid-session.
       if [ -n "$settings_file" ] && [ -f "$settings_file" ]; then
         local _tt
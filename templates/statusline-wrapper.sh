@@ -85,4 +85,10 @@ fi
 if [ -n "$model_name" ]; then
   line="$line$(printf ' | \033[01;34m%s\033[00m' "$model_name")"
 fi
+# Monthly budget alert (50/80/100% of a cap), recorded by the tab-title watcher.
+budget_label=""
+type gt_budget_segment &>/dev/null && budget_label=$(gt_budget_segment)
+if [ -n "$budget_label" ]; then
+  line="$line | $budget_label"
+fi
 printf '%s' "$line"
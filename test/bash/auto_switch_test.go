@@ -1,6 +1,7 @@
 package bash_test
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -83,7 +84,7 @@ func TestProxyStartupParse_extracts_ca_path(t *testing.T) {
 	}
 }
 
-func TestAutoSwitchEligible_needs_two_accounts(t *testing.T) {
+func TestAutoSwitchEligible_needs_one_account(t *testing.T) {
 	dir := t.TempDir()
 	list := filepath.Join(dir, "claude-accounts.list")
 
@@ -93,10 +94,10 @@ func TestAutoSwitchEligible_needs_two_accounts(t *testing.T) {
 		t.Fatal("0 accounts should not be eligible")
 	}
 
-	// One account → not eligible (nothing to rotate to).
+	// One account → eligible: the proxy picks up logins added later.
 	writeTempFile(t, dir, "claude-accounts.list", "Work:work\n")
-	if _, code := runBashFunc(t, "lib/auto-switch.sh", "auto_switch_eligible", []string{list}, nil); code == 0 {
-		t.Fatal("1 account should not be eligible")
+	if _, code := runBashFunc(t, "lib/auto-switch.sh", "auto_switch_eligible", []string{list}, nil); code != 0 {
+		t.Fatal("1 account should be eligible")
 	}
 
 	// Two accounts → eligible.
@@ -105,3 +106,50 @@ func TestAutoSwitchEligible_needs_two_accounts(t *testing.T) {
 		t.Fatal("2 accounts should be eligible")
 	}
 }
+
+func TestRotationPolicy_defaults_to_drain_and_normalizes(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "rotation-policy")
+
+	out, code := runBashFunc(t, "lib/auto-switch.sh", "get_rotation_policy", []string{file}, nil)
+	assertExitCode(t, code, 0)
+	if strings.TrimSpace(out) != "drain" {
+		t.Errorf("missing file = %q, want drain", strings.TrimSpace(out))
+	}
+
+	writeTempFile(t, dir, "rotation-policy", "priority\n")
+	out, _ = runBashFunc(t, "lib/auto-switch.sh", "get_rotation_policy", []string{file}, nil)
+	if strings.TrimSpace(out) != "priority" {
+		t.Errorf("got %q, want priority", strings.TrimSpace(out))
+	}
+
+	writeTempFile(t, dir, "rotation-policy", "bogus\n")
+	out, _ = runBashFunc(t, "lib/auto-switch.sh", "get_rotation_policy", []string{file}, nil)
+	if strings.TrimSpace(out) != "drain" {
+		t.Errorf("unknown value should read as drain, got %q", strings.TrimSpace(out))
+	}
+}
+
+func TestAttachRotationProxy_passesConfigAndPrintsStartupLine(t *testing.T) {
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	bin := mockCommand(t, dir, "wisp-deck-tui",
+		`printf '%s\n' "$@" > "`+argsFile+`"
+echo '{"port":4242,"key":"wd-k"}'
+echo 'extra line'`)
+	writeTempFile(t, dir, "rotation-policy", "priority\n")
+	env := buildEnv(t, []string{bin})
+	out, code := runBashFunc(t, "lib/auto-switch.sh", "attach_rotation_proxy", []string{dir, "1234"}, env)
+	assertExitCode(t, code, 0)
+	if strings.TrimSpace(out) != `{"port":4242,"key":"wd-k"}` {
+		t.Errorf("output = %q, want only the startup line", out)
+	}
+	data, _ := os.ReadFile(argsFile)
+	args := string(data)
+	for _, want := range []string{"proxy\nattach\n", "--state-dir\n" + dir + "\n", "--client-pid\n1234\n", "--policy\npriority\n",
+		"--list\n" + filepath.Join(dir, "claude-accounts.list") + "\n"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("args missing %q:\n%s", want, args)
+		}
+	}
+}
@@ -86,6 +86,28 @@ func TestResolveClaudeAccountDir_default_is_empty(t *testing.T) {
 	}
 }
 
+// A conversation resumed from the Stats tab names its login by usage account
+// key: "default" is the Keychain login, anything else a dir under accounts.
+func TestClaudeAccountKeyDir(t *testing.T) {
+	dir := t.TempDir()
+	acctRoot := filepath.Join(dir, "claude-accounts")
+	if err := os.MkdirAll(filepath.Join(acctRoot, "work"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{
+		"work":    filepath.Join(acctRoot, "work"),
+		"default": "",
+		"":        "",
+		"gone":    "",
+	} {
+		out, _ := runBashFunc(t, "lib/claude-accounts.sh", "claude_account_key_dir",
+			[]string{acctRoot, key}, nil)
+		if got := strings.TrimSpace(out); got != want {
+			t.Errorf("claude_account_key_dir %q = %q, want %q", key, got, want)
+		}
+	}
+}
+
 // get_active_claude_account_name maps the active pointer to its display label so
 // the compact-view ledger / menu can show which account is in use. Default (no
 // pointer) reads as "Default".
@@ -177,6 +177,37 @@ echo "path=$_selected_project_path"
 	assertContains(t, out, "path=/tmp/temp")
 }
 
+func TestMenu_handles_resume_session_action(t *testing.T) {
+	dir := t.TempDir()
+	binDir := mockCommand(t, dir, "wisp-deck-tui", `echo '{"action":"resume-session","name":"app","path":"/tmp/app","session":"3f2a1b9c","ai_tool":"claude"}'`)
+	projectsFile := writeTempFile(t, dir, "projects", "proj1:/tmp/p1\n")
+	root := projectRoot(t)
+	env := buildEnv(t, []string{binDir},
+		"XDG_CONFIG_HOME="+filepath.Join(dir, "config"),
+	)
+
+	script := fmt.Sprintf(`
+source %q 2>/dev/null || true
+source %q
+error() { echo "ERROR: $*" >&2; }
+AI_TOOLS_AVAILABLE=("claude")
+SELECTED_AI_TOOL="claude"
+_update_version=""
+select_project_interactive %q
+echo "action=$_selected_project_action"
+echo "path=$_selected_project_path"
+echo "session=$_selected_session_id"
+`, filepath.Join(root, "lib/tui.sh"),
+		filepath.Join(root, "lib/menu-tui.sh"),
+		projectsFile)
+
+	out, code := runBashSnippet(t, script, env)
+	assertExitCode(t, code, 0)
+	assertContains(t, out, "action=resume-session")
+	assertContains(t, out, "path=/tmp/app")
+	assertContains(t, out, "session=3f2a1b9c")
+}
+
 func TestMenu_handles_plain_terminal_action(t *testing.T) {
 	dir := t.TempDir()
 	binDir := mockCommand(t, dir, "wisp-deck-tui", `echo '{"action":"plain-terminal","ai_tool":"claude"}'`)
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 // ============================================================
@@ -1638,3 +1639,31 @@ func TestStatusline_stamp_claude_session_noop_without_session_id(t *testing.T) {
 		t.Error("must not stamp when the payload has no session_id")
 	}
 }
+
+// --- gt_budget_segment ---
+
+func TestStatusline_budget_segment_shows_this_months_alert(t *testing.T) {
+	dir := t.TempDir()
+	month := time.Now().UTC().Format("2006-01")
+	for _, tc := range []struct {
+		threshold, color string
+	}{{"80", "01;33"}, {"100", "01;31"}} {
+		state := writeTempFile(t, dir, "budget-alert", month+" "+tc.threshold+" 82% of budget ($164/$200)\n")
+		out, code := runBashFunc(t, "lib/statusline.sh", "gt_budget_segment", []string{state}, buildEnv(t, nil))
+		assertExitCode(t, code, 0)
+		assertContains(t, out, "\033["+tc.color+"m")
+		assertContains(t, out, "82% of budget ($164/$200)")
+	}
+}
+
+func TestStatusline_budget_segment_empty_for_a_past_month_or_no_state(t *testing.T) {
+	dir := t.TempDir()
+	state := writeTempFile(t, dir, "budget-alert", "1999-01 100 120% of budget ($240/$200)\n")
+	for _, path := range []string{state, filepath.Join(dir, "missing")} {
+		out, code := runBashFunc(t, "lib/statusline.sh", "gt_budget_segment", []string{path}, buildEnv(t, nil))
+		assertExitCode(t, code, 0)
+		if out != "" {
+			t.Errorf("gt_budget_segment(%s) = %q, want nothing", path, out)
+		}
+	}
+}
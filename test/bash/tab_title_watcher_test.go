@@ -735,6 +735,75 @@ func TestTabTitleWatcher_play_notification_sound_uses_default_when_features_file
 	assertContains(t, string(data), "Bottle.aiff")
 }
 
+// --- check_budget_alert ---
+
+func TestTabTitleWatcher_check_budget_alert_sounds_a_new_crossing(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "config")
+	writeTempFile(t, configDir, "settings", "budget=200\n")
+	writeTempFile(t, configDir, "claude-features.json", `{"sound": true, "sound_name": "Glass"}`)
+
+	logFile := filepath.Join(tmpDir, "calls.log")
+	mockCommand(t, tmpDir, "afplay", fmt.Sprintf(`echo "afplay $1" >> %q`, logFile))
+	mockCommand(t, tmpDir, "tmux", fmt.Sprintf(`echo "tmux $*" >> %q`, logFile))
+	binDir := mockCommand(t, tmpDir, "wisp-deck-tui", `[ "$*" = "budget check" ] && echo "82% of budget (\$164/\$200)"`)
+	env := buildEnv(t, []string{binDir})
+
+	snippet := soundWatcherSnippet(t,
+		fmt.Sprintf(`check_budget_alert %q claude tmux gt-app`, configDir))
+	_, code := runBashSnippet(t, snippet, env)
+	assertExitCode(t, code, 0)
+
+	time.Sleep(200 * time.Millisecond)
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("expected the crossing to be announced: %v", err)
+	}
+	assertContains(t, string(data), "afplay /System/Library/Sounds/Glass.aiff")
+	assertContains(t, string(data), "display-message -t gt-app Wisp Deck: 82% of budget ($164/$200)")
+}
+
+func TestTabTitleWatcher_check_budget_alert_silent_without_a_crossing_or_budget(t *testing.T) {
+	for name, settings := range map[string]string{"no crossing": "budget=200\n", "no budget": "theme=auto\n"} {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			configDir := filepath.Join(tmpDir, "config")
+			writeTempFile(t, configDir, "settings", settings)
+
+			logFile := filepath.Join(tmpDir, "calls.log")
+			mockCommand(t, tmpDir, "afplay", fmt.Sprintf(`echo "afplay $1" >> %q`, logFile))
+			binDir := mockCommand(t, tmpDir, "wisp-deck-tui", fmt.Sprintf(`echo "ran $*" >> %q`, filepath.Join(tmpDir, "tui.log")))
+			env := buildEnv(t, []string{binDir})
+
+			snippet := soundWatcherSnippet(t,
+				fmt.Sprintf(`check_budget_alert %q claude tmux gt-app`, configDir))
+			_, code := runBashSnippet(t, snippet, env)
+			assertExitCode(t, code, 0)
+
+			time.Sleep(200 * time.Millisecond)
+
+			if _, err := os.Stat(logFile); !os.IsNotExist(err) {
+				t.Errorf("expected no sound")
+			}
+			_, err := os.Stat(filepath.Join(tmpDir, "tui.log"))
+			if ran := err == nil; ran != (name == "no crossing") {
+				t.Errorf("budget check ran = %v, want it only when a budget is set", ran)
+			}
+		})
+	}
+}
+
+func TestTabTitleWatcher_loop_checks_the_budget(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(projectRoot(t), "lib", "tab-title-watcher.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `check_budget_alert "$config_dir" "$ai_tool" "$tmux_cmd" "$session_name" &`) {
+		t.Error("the watcher loop should check the budget in the background")
+	}
+}
+
 func TestTabTitleWatcher_wrapper_passes_config_dir_to_watcher(t *testing.T) {
 	root := projectRoot(t)
 	wrapperPath := filepath.Join(root, "wrapper.sh")
@@ -1503,7 +1503,7 @@ func TestMainMenu_SettingsNavigationKeys(t *testing.T) {
 }
 
 func TestMainMenu_SettingsNavigationWraps(t *testing.T) {
-	const numItems = 9 // claude tool has 9 settings items (incl. Theme, Panel, Plan, Login, Auto-switch accounts rows)
+	const numItems = 11 // claude tool has 11 settings items (incl. Theme, Panel, Plan, Login, Auto-switch accounts, Rotation policy, Monthly budget rows)
 
 	jKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}}
 	kKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}}
@@ -5037,32 +5037,33 @@ func TestSettings_ProjectsRootItem_ShowsCurrentValue(t *testing.T) {
 	}
 }
 
-func TestSettings_NavWrapsWithNineItems(t *testing.T) {
-	// claude tool shows 9 settings items (Mascot, Tab title, Sound, Panel,
-	// Theme, Default projects dir, Plan, Login)
+func TestSettings_NavWrapsWithElevenItems(t *testing.T) {
+	// claude tool shows 11 settings items (Mascot, Tab title, Sound, Panel,
+	// Theme, Default projects dir, Plan, Login, Auto-switch, Rotation policy,
+	// Monthly budget)
 	m := tui.NewMainMenu(nil, []string{"claude"}, "claude", "animated")
 	m.EnterSettings()
-	// j 8 times — wraps back to 0 (vim accelerator wraps within the list)
-	for i := 0; i < 9; i++ {
+	// j 11 times — wraps back to 0 (vim accelerator wraps within the list)
+	for i := 0; i < 11; i++ {
 		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
 	}
 	if m.SettingsSelected() != 0 {
-		t.Errorf("expected settingsSelected=0 after wrapping past 9 items, got %d", m.SettingsSelected())
+		t.Errorf("expected settingsSelected=0 after wrapping past 11 items, got %d", m.SettingsSelected())
 	}
 }
 
-func TestSettings_NavWrapsWithNineItems_NonClaude(t *testing.T) {
-	// The Plan + Login rows are shared across agents, so opencode also shows 9
+func TestSettings_NavWrapsWithElevenItems_NonClaude(t *testing.T) {
+	// The Plan + Login rows are shared across agents, so opencode also shows 11
 	// settings items (Mascot, Tab title, Sound, Panel, Theme, Default
-	// projects dir, Plan, Login).
+	// projects dir, Plan, Login, Auto-switch, Rotation policy, Monthly budget).
 	m := tui.NewMainMenu(nil, []string{"opencode"}, "opencode", "animated")
 	m.EnterSettings()
-	// j 8 times — wraps back to 0 (vim accelerator wraps within the list)
-	for i := 0; i < 9; i++ {
+	// j 11 times — wraps back to 0 (vim accelerator wraps within the list)
+	for i := 0; i < 11; i++ {
 		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
 	}
 	if m.SettingsSelected() != 0 {
-		t.Errorf("expected settingsSelected=0 after wrapping past 9 items, got %d", m.SettingsSelected())
+		t.Errorf("expected settingsSelected=0 after wrapping past 11 items, got %d", m.SettingsSelected())
 	}
 }
 
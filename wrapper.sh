@@ -146,6 +146,21 @@ else
           cd "$_selected_project_path" || exit 1
           break
           ;;
+        resume-session)
+          # A conversation picked from the Stats tab: reopen it by id in the
+          # directory it was started in. Transcripts are Claude's, so this
+          # launch runs Claude whichever agent the menu has selected, and under
+          # the login whose config dir holds the transcript (see below).
+          PROJECT_NAME="$_selected_project_name"
+          cd "$_selected_project_path" || exit 1
+          SELECTED_AI_TOOL="claude"
+          export WISP_DECK_RESUME=1
+          # shellcheck disable=SC2154
+          export WISP_DECK_RESUME_SESSION="$_selected_session_id"
+          # shellcheck disable=SC2154
+          _resume_account="$_selected_session_account"
+          break
+          ;;
         plain-terminal)
           # A plain Ghostty shell should still run `claude` under the login the
           # user has selected in the menu (the current Claude Code user), not the
@@ -225,8 +240,6 @@ WATCHER_PID=$!
 cleanup() {
   stop_tab_title_watcher "$WISP_DECK_MARKER_FILE"
   [ -n "${HEARTBEAT_PID:-}" ] && kill_tree "$HEARTBEAT_PID" TERM 2>/dev/null || true
-  # Stop the account-rotation proxy (session-tied lifecycle) if one was started.
-  [ -n "${PROXY_PID:-}" ] && kill_tree "$PROXY_PID" TERM 2>/dev/null || true
   # Remove waiting indicator hooks if no other Wisp Deck sessions are running
   if [ "$SELECTED_AI_TOOL" = "claude" ]; then
     # Clean up orphaned markers and cooldown files from dead sessions (e.g., after SIGKILL)
@@ -249,7 +262,6 @@ cleanup() {
   fi
   cleanup_tmux_session "$SESSION_NAME" "$WATCHER_PID" "$TMUX_CMD"
   rm -f "$SHARE_DIR/spare-${SESSION_NAME}.conf"
-  rm -f "$SHARE_DIR/proxy-${SESSION_NAME}.log"
   rm -rf "$SHARE_DIR/spare-zdotdir-${SESSION_NAME}"
 }
 trap cleanup EXIT HUP TERM INT
@@ -269,9 +281,20 @@ export WISP_DECK_CLAUDE_SETTINGS
 # Resolve the active native Claude account (its isolated CLAUDE_CONFIG_DIR) and
 # export for build_ai_launch_cmd. Default (empty) leaves CLAUDE_CONFIG_DIR unset
 # so Claude uses the standard Keychain login.
+# A conversation resumed from the Stats tab instead runs under the login that
+# owns it: `claude --resume` only finds transcripts in its own config dir.
 WISP_DECK_CLAUDE_ACCOUNT_DIR=""
+_resume_pinned=""
 if [ "$SELECTED_AI_TOOL" = "claude" ]; then
-  WISP_DECK_CLAUDE_ACCOUNT_DIR="$(resolve_claude_account_dir "$_gt_cfg_root/claude-accounts" "$_gt_cfg_root/claude-account")"
+  if [ -n "${_resume_account:-}" ]; then
+    WISP_DECK_CLAUDE_ACCOUNT_DIR="$(claude_account_key_dir "$_gt_cfg_root/claude-accounts" "$_resume_account")"
+    if [ "$_resume_account" = "default" ] || [ -n "$WISP_DECK_CLAUDE_ACCOUNT_DIR" ]; then
+      _resume_pinned=1
+    fi
+  fi
+  if [ -z "$_resume_pinned" ]; then
+    WISP_DECK_CLAUDE_ACCOUNT_DIR="$(resolve_claude_account_dir "$_gt_cfg_root/claude-accounts" "$_gt_cfg_root/claude-account")"
+  fi
   # A non-Default account has its own isolated CLAUDE_CONFIG_DIR, which otherwise
   # starts blank — no status line, permission mode, skills, hooks, model, etc.
   # Link the standard login's settings into it so every login shares one set of
@@ -281,14 +304,15 @@ if [ "$SELECTED_AI_TOOL" = "claude" ]; then
     sync_claude_shared_settings "$HOME/.claude" "$WISP_DECK_CLAUDE_ACCOUNT_DIR"
   fi
 fi
-# Account-rotation proxy: when the auto-switch setting is on and there are at
-# least two accounts to rotate between, start Wisp Deck's local rotation proxy
-# and route claude through it (ANTHROPIC_BASE_URL/API_KEY, set in
-# build_ai_launch_cmd). The proxy injects the currently-active pooled account's
+# Account-rotation proxy: when the auto-switch setting is on and there is at
+# least one managed account (the pool grows as logins are added), start Wisp
+# Deck's local rotation proxy and route claude through it
+# (ANTHROPIC_BASE_URL/API_KEY, set in build_ai_launch_cmd). The proxy injects the currently-active pooled account's
 # OAuth token and switches accounts as quota is exhausted; claude keeps a single
-# config dir, so the conversation continues seamlessly across switches. The proxy
-# is a child of this wrapper and is torn down by cleanup() on window close.
-PROXY_PID=""
+# config dir, so the conversation continues seamlessly across switches. One
+# proxy daemon is shared by every session (so they agree on each account's
+# quota); this wrapper attaches to it, starting it if needed, and the daemon
+# exits on its own once no attached wrapper is alive.
 WISP_DECK_PROXY_PORT=""
 WISP_DECK_PROXY_KEY=""
 WISP_DECK_PROXY_CA=""
@@ -296,32 +320,17 @@ if [ "$SELECTED_AI_TOOL" = "claude" ] \
    && is_auto_switch_enabled "$_gt_cfg_root/auto-switch-accounts" \
    && auto_switch_eligible "$_gt_cfg_root/claude-accounts.list" \
    && command -v wisp-deck-tui >/dev/null 2>&1; then
-  _proxy_log="$SHARE_DIR/proxy-${SESSION_NAME}.log"
-  : > "$_proxy_log"
-  wisp-deck-tui proxy \
-    --accounts-dir "$_gt_cfg_root/claude-accounts" \
-    --list "$_gt_cfg_root/claude-accounts.list" \
-    >> "$_proxy_log" 2>&1 &
-  PROXY_PID=$!
-  # Wait briefly for the proxy to announce its port + key (first line of output).
-  _proxy_line=""
-  for _ in $(seq 1 30); do
-    _proxy_line="$(sed -n '1p' "$_proxy_log" 2>/dev/null)"
-    [ -n "$_proxy_line" ] && break
-    kill -0 "$PROXY_PID" 2>/dev/null || break
-    sleep 0.1
-  done
+  _proxy_line="$(attach_rotation_proxy "$_gt_cfg_root" "$$")"
   WISP_DECK_PROXY_PORT="$(proxy_startup_port "$_proxy_line")"
   WISP_DECK_PROXY_KEY="$(proxy_startup_key "$_proxy_line")"
   WISP_DECK_PROXY_CA="$(proxy_startup_ca "$_proxy_line")"
   if [ -n "$WISP_DECK_PROXY_PORT" ] && [ -n "$WISP_DECK_PROXY_KEY" ]; then
     # Rotation is upstream in the proxy, so claude uses its standard (Default)
-    # config dir rather than any single account's isolated dir.
-    WISP_DECK_CLAUDE_ACCOUNT_DIR=""
+    # config dir rather than any single account's isolated dir — unless it is
+    # resuming a conversation whose transcript lives in one.
+    [ -z "$_resume_pinned" ] && WISP_DECK_CLAUDE_ACCOUNT_DIR=""
   else
     warn "Account-rotation proxy failed to start; launching with the selected account."
-    [ -n "$PROXY_PID" ] && kill_tree "$PROXY_PID" TERM 2>/dev/null || true
-    PROXY_PID=""
   fi
 fi
 export WISP_DECK_CLAUDE_ACCOUNT_DIR WISP_DECK_PROXY_PORT WISP_DECK_PROXY_KEY WISP_DECK_PROXY_CA